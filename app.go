@@ -11,6 +11,8 @@ import (
 	_ "github.com/micro-plat/hydra/registry/watcher/wvalue"
 
 	_ "github.com/micro-plat/hydra/hydra/cmds/conf"
+	_ "github.com/micro-plat/hydra/hydra/cmds/cron"
+	_ "github.com/micro-plat/hydra/hydra/cmds/db"
 	_ "github.com/micro-plat/hydra/hydra/cmds/install"
 	_ "github.com/micro-plat/hydra/hydra/cmds/remove"
 	_ "github.com/micro-plat/hydra/hydra/cmds/run"
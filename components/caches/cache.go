@@ -44,7 +44,10 @@ func (s *StandardCache) GetCache(names ...string) (c ICache, err error) {
 			return nil, fmt.Errorf("节点/%s/%s未配置，或不可用", cacheTypeNode, name)
 		}
 		orgCache, err := cache.New(conf.GetString("proto"), string(conf.GetRaw()))
-		return orgCache, err
+		if err != nil {
+			return nil, err
+		}
+		return &traceCache{ICache: orgCache, proto: conf.GetString("proto")}, nil
 	})
 	if err != nil {
 		return nil, err
@@ -0,0 +1,83 @@
+package caches
+
+import (
+	"strings"
+
+	"github.com/micro-plat/hydra/context"
+)
+
+//traceCache 请求被跟踪时为每次缓存操作创建链路跟踪子跨度
+type traceCache struct {
+	ICache
+	proto string
+}
+
+//Get 获取缓存数据
+func (c *traceCache) Get(key string) (v string, err error) {
+	span := c.startSpan("get", key)
+	defer func() { span.SetError(err); span.End() }()
+	return c.ICache.Get(key)
+}
+
+//Gets 批量获取缓存数据
+func (c *traceCache) Gets(key ...string) (r []string, err error) {
+	span := c.startSpan("gets", strings.Join(key, ","))
+	defer func() { span.SetError(err); span.End() }()
+	return c.ICache.Gets(key...)
+}
+
+//Decrement 减少指定的值
+func (c *traceCache) Decrement(key string, delta int64) (n int64, err error) {
+	span := c.startSpan("decrement", key)
+	defer func() { span.SetError(err); span.End() }()
+	return c.ICache.Decrement(key, delta)
+}
+
+//Increment 增加指定的值
+func (c *traceCache) Increment(key string, delta int64) (n int64, err error) {
+	span := c.startSpan("increment", key)
+	defer func() { span.SetError(err); span.End() }()
+	return c.ICache.Increment(key, delta)
+}
+
+//Add 添加缓存数据，已存在时返回错误
+func (c *traceCache) Add(key string, value string, expiresAt int) (err error) {
+	span := c.startSpan("add", key)
+	defer func() { span.SetError(err); span.End() }()
+	return c.ICache.Add(key, value, expiresAt)
+}
+
+//Set 设置缓存数据
+func (c *traceCache) Set(key string, value string, expiresAt int) (err error) {
+	span := c.startSpan("set", key)
+	defer func() { span.SetError(err); span.End() }()
+	return c.ICache.Set(key, value, expiresAt)
+}
+
+//Delete 删除缓存数据
+func (c *traceCache) Delete(key string) (err error) {
+	span := c.startSpan("delete", key)
+	defer func() { span.SetError(err); span.End() }()
+	return c.ICache.Delete(key)
+}
+
+//Exists 检查缓存是否存在
+func (c *traceCache) Exists(key string) bool {
+	span := c.startSpan("exists", key)
+	defer span.End()
+	return c.ICache.Exists(key)
+}
+
+//Delay 延长缓存的过期时间
+func (c *traceCache) Delay(key string, expiresAt int) (err error) {
+	span := c.startSpan("delay", key)
+	defer func() { span.SetError(err); span.End() }()
+	return c.ICache.Delay(key, expiresAt)
+}
+
+func (c *traceCache) startSpan(operator string, key string) context.ITraceSpan {
+	span := context.StartSpan("cache", "cache."+operator)
+	span.SetTag("cache.type", c.proto)
+	span.SetTag("cache.key", key)
+	return span
+}
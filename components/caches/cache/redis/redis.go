@@ -90,22 +90,20 @@ func (c *Client) Gets(key ...string) (r []string, err error) {
 	return
 }
 
-// Add 添加数据到redis中,如果redis存在，则报错
+// Add 添加数据到redis中,如果redis存在，则报错。使用SetNX保证检查与写入的原子性，可用于标记一次性凭据
 func (c *Client) Add(key string, value string, expiresAt int) error {
 	expires := time.Duration(expiresAt) * time.Second
 	if expiresAt == 0 {
 		expires = 0
 	}
-	i, err := c.client.Exists(key).Result()
+	ok, err := c.client.SetNX(key, value, expires).Result()
 	if err != nil {
 		return err
 	}
-	if i == 1 {
-		err = fmt.Errorf("key:%s已存在", key)
-		return err
+	if !ok {
+		return fmt.Errorf("key:%s已存在", key)
 	}
-	_, err = c.client.Set(key, value, expires).Result()
-	return err
+	return nil
 }
 
 // Set 更新数据到redis中，没有则添加
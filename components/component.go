@@ -14,8 +14,6 @@ import (
 	"github.com/micro-plat/hydra/components/rpcs"
 	"github.com/micro-plat/hydra/components/uuid"
 	"github.com/micro-plat/hydra/context"
-	"github.com/micro-plat/hydra/global"
-	"github.com/micro-plat/hydra/registry"
 
 	_ "github.com/micro-plat/hydra/components/queues/mq/lmq"
 	_ "github.com/micro-plat/hydra/components/queues/mq/mqtt"
@@ -32,6 +30,7 @@ type IComponent interface {
 	HTTP() http.IComponentHTTPClient
 	DB() dbs.IComponentDB
 	DLock(name string) (dlock.ILock, error)
+	DLocks() dlock.IComponentDLock
 	UUID() uuid.UUID
 }
 
@@ -46,6 +45,7 @@ type Component struct {
 	cache      caches.IComponentCache
 	db         dbs.IComponentDB
 	httpClient http.IComponentHTTPClient
+	dlock      dlock.IComponentDLock
 }
 
 //NewComponent 创建组件
@@ -58,6 +58,7 @@ func NewComponent() *Component {
 	c.cache = caches.NewStandardCache(c.c)
 	c.db = dbs.NewStandardDB(c.c)
 	c.httpClient = http.NewStandardHTTPClient(c.c)
+	c.dlock = dlock.NewStandardDLock(c.c)
 	return c
 }
 
@@ -91,9 +92,14 @@ func (c *Component) HTTP() http.IComponentHTTPClient {
 	return c.httpClient
 }
 
-//DLock 获取分布式鍞
+//DLock 获取基于注册中心的分布式鍞
 func (c *Component) DLock(name string) (dlock.ILock, error) {
-	return dlock.NewLock(registry.Join(global.Def.PlatName, "dlock", name), global.Def.RegistryAddr, context.Current().Log())
+	return c.dlock.GetLock(name)
+}
+
+//DLocks 获取分布式锁组件，指定dlock.WithRedis时使用redis实现
+func (c *Component) DLocks() dlock.IComponentDLock {
+	return c.dlock
 }
 
 //UUID 获取全局唯一编号
@@ -1,7 +1,9 @@
 package dbs
 
 import (
+	"database/sql"
 	"fmt"
+	"strings"
 
 	"github.com/micro-plat/hydra/components/container"
 	"github.com/micro-plat/lib4go/db"
@@ -9,6 +11,7 @@ import (
 
 	"github.com/micro-plat/hydra/conf"
 	xdb "github.com/micro-plat/hydra/conf/vars/db"
+	"github.com/micro-plat/hydra/context"
 )
 
 const (
@@ -49,10 +52,89 @@ func (s *StandardDB) GetDB(names ...string) (d IDB, err error) {
 		if err = conf.ToStruct(&dbConf); err != nil {
 			return nil, fmt.Errorf("数据库[%s/%s]配置有误：%w", dbTypeNode, name, err)
 		}
-		return db.NewDB(dbConf.Provider, dbConf.ConnString, dbConf.MaxOpen, dbConf.MaxIdle, dbConf.LifeTime)
+		if err = checkDriver(dbConf.Provider); err != nil {
+			return nil, fmt.Errorf("数据库[%s/%s]配置有误：%w", dbTypeNode, name, err)
+		}
+		orgDB, err := db.NewDB(dbConf.Provider, dbConf.ConnString, dbConf.MaxOpen, dbConf.MaxIdle, dbConf.LifeTime)
+		if err != nil {
+			return nil, err
+		}
+		dialect, _ := GetDialect(dbConf.Provider)
+		return &dialectDB{IDB: orgDB, dialect: dialect, provider: dbConf.Provider}, nil
 	})
 	if err != nil {
 		return nil, err
 	}
 	return obj.(IDB), nil
 }
+
+//checkDriver 检查数据库驱动是否已注册，驱动包需由应用程序导入
+func checkDriver(provider string) error {
+	driver := strings.ToLower(provider)
+	switch driver {
+	case "ora", "oracle":
+		driver = db.OCI8
+	case "sqlite":
+		driver = db.SQLITE3
+	}
+	for _, d := range sql.Drivers() {
+		if d == driver {
+			return nil
+		}
+	}
+	return fmt.Errorf("数据库驱动%s未注册，请在程序中导入对应的驱动包", driver)
+}
+
+//dialectDB 包含方言信息的数据库操作对象，请求被跟踪时为每次数据库操作创建链路跟踪子跨度
+type dialectDB struct {
+	db.IDB
+	dialect  IDialect
+	provider string
+}
+
+//GetDialect 获取数据库方言，方言未注册时返回nil
+func (d *dialectDB) GetDialect() IDialect {
+	return d.dialect
+}
+
+//Query 查询数据
+func (d *dialectDB) Query(sql string, input map[string]interface{}) (data db.QueryRows, err error) {
+	span := d.startSpan("query", sql)
+	defer func() { span.SetError(err); span.End() }()
+	return d.IDB.Query(sql, input)
+}
+
+//Scalar 查询首行首列
+func (d *dialectDB) Scalar(sql string, input map[string]interface{}) (data interface{}, err error) {
+	span := d.startSpan("scalar", sql)
+	defer func() { span.SetError(err); span.End() }()
+	return d.IDB.Scalar(sql, input)
+}
+
+//Execute 执行SQL语句
+func (d *dialectDB) Execute(sql string, input map[string]interface{}) (row int64, err error) {
+	span := d.startSpan("execute", sql)
+	defer func() { span.SetError(err); span.End() }()
+	return d.IDB.Execute(sql, input)
+}
+
+//Executes 执行SQL语句，返回最后插入的编号与影响的行数
+func (d *dialectDB) Executes(sql string, input map[string]interface{}) (lastInsertID int64, affectedRow int64, err error) {
+	span := d.startSpan("executes", sql)
+	defer func() { span.SetError(err); span.End() }()
+	return d.IDB.Executes(sql, input)
+}
+
+//ExecuteSP 执行存储过程
+func (d *dialectDB) ExecuteSP(procName string, input map[string]interface{}, output ...interface{}) (row int64, err error) {
+	span := d.startSpan("sp", procName)
+	defer func() { span.SetError(err); span.End() }()
+	return d.IDB.ExecuteSP(procName, input, output...)
+}
+
+func (d *dialectDB) startSpan(operator string, statement string) context.ITraceSpan {
+	span := context.StartSpan("db", "db."+operator)
+	span.SetTag("db.type", d.provider)
+	span.SetTag("db.statement", statement)
+	return span
+}
@@ -0,0 +1,116 @@
+package dbs
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+//IDialect 数据库方言，处理不同数据库的占位符与分页语法差异
+type IDialect interface {
+
+	//Name 数据库类型名称
+	Name() string
+
+	//Placeholder 获取第index(从1开始)个参数的占位符
+	Placeholder(index int) string
+
+	//Paging 构建分页查询语句，pi为页码(从1开始)，ps为每页记录数
+	Paging(sql string, pi int, ps int) string
+}
+
+var dialects = map[string]IDialect{}
+var dialectLock sync.RWMutex
+
+//内置方言对应的驱动包需由应用程序导入，如mysql(github.com/go-sql-driver/mysql)、postgres(github.com/lib/pq)、
+//sqlite(github.com/mattn/go-sqlite3)、oracle(github.com/mattn/go-oci8)、sqlserver(github.com/denisenkom/go-mssqldb)
+func init() {
+	RegisterDialect("mysql", &dialect{name: "mysql", placeholder: question, paging: limitOffset})
+	RegisterDialect("sqlite", &dialect{name: "sqlite", placeholder: question, paging: limitOffset})
+	RegisterDialect("postgres", &dialect{name: "postgres", placeholder: numbered("$"), paging: limitOffset})
+	RegisterDialect("oracle", &dialect{name: "oracle", placeholder: numbered(":"), paging: rownum})
+	RegisterDialect("ora", &dialect{name: "ora", placeholder: numbered(":"), paging: rownum})
+	RegisterDialect("sqlserver", &dialect{name: "sqlserver", placeholder: numbered("@p"), paging: offsetFetch})
+}
+
+//RegisterDialect 注册数据库方言
+func RegisterDialect(provider string, d IDialect) {
+	dialectLock.Lock()
+	defer dialectLock.Unlock()
+	name := strings.ToLower(provider)
+	if _, ok := dialects[name]; ok {
+		panic(fmt.Sprintf("重复注册的数据库方言:%s", provider))
+	}
+	dialects[name] = d
+}
+
+//GetDialect 根据数据库类型获取方言
+func GetDialect(provider string) (IDialect, error) {
+	dialectLock.RLock()
+	defer dialectLock.RUnlock()
+	if d, ok := dialects[strings.ToLower(provider)]; ok {
+		return d, nil
+	}
+	return nil, fmt.Errorf("不支持的数据库类型:%s", provider)
+}
+
+type dialect struct {
+	name        string
+	placeholder func(index int) string
+	paging      func(sql string, offset int, limit int) string
+}
+
+func (d *dialect) Name() string {
+	return d.name
+}
+
+func (d *dialect) Placeholder(index int) string {
+	return d.placeholder(index)
+}
+
+func (d *dialect) Paging(sql string, pi int, ps int) string {
+	if pi < 1 {
+		pi = 1
+	}
+	return d.paging(strings.TrimRight(strings.TrimSpace(sql), ";"), (pi-1)*ps, ps)
+}
+
+func question(index int) string {
+	return "?"
+}
+
+func numbered(prefix string) func(int) string {
+	return func(index int) string {
+		return fmt.Sprint(prefix, index)
+	}
+}
+
+func limitOffset(sql string, offset int, limit int) string {
+	return fmt.Sprintf("%s limit %d offset %d", sql, limit, offset)
+}
+
+func rownum(sql string, offset int, limit int) string {
+	return fmt.Sprintf("select * from (select t_.*,rownum rn_ from (%s) t_ where rownum <= %d) where rn_ > %d", sql, offset+limit, offset)
+}
+
+//offsetFetch sql server 2012及以上版本的分页语法，要求查询语句包含order by
+func offsetFetch(sql string, offset int, limit int) string {
+	if !strings.Contains(strings.ToLower(sql), "order by") {
+		sql += " order by (select null)"
+	}
+	return fmt.Sprintf("%s offset %d rows fetch next %d rows only", sql, offset, limit)
+}
+
+//IsTableNotFound 是否是表不存在的错误(mysql,oracle,postgres,sqlite,sqlserver)
+func IsTableNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, p := range []string{"doesn't exist", "does not exist", "no such table", "invalid object name", "ora-00942"} {
+		if strings.Contains(msg, p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package dbs
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/micro-plat/lib4go/assert"
+	"github.com/micro-plat/lib4go/db/tpl"
+)
+
+func TestGetDialect(t *testing.T) {
+	tests := []struct {
+		name        string
+		provider    string
+		placeholder string
+		paging      string
+	}{
+		{name: "1. mysql", provider: "mysql", placeholder: "?", paging: "select * from t limit 10 offset 20"},
+		{name: "2. sqlite", provider: "SQLite", placeholder: "?", paging: "select * from t limit 10 offset 20"},
+		{name: "3. postgres", provider: "postgres", placeholder: "$2", paging: "select * from t limit 10 offset 20"},
+		{name: "4. oracle", provider: "oracle", placeholder: ":2", paging: "select * from (select t_.*,rownum rn_ from (select * from t) t_ where rownum <= 30) where rn_ > 20"},
+		{name: "5. sqlserver", provider: "sqlserver", placeholder: "@p2", paging: "select * from t order by (select null) offset 20 rows fetch next 10 rows only"},
+	}
+	for _, tt := range tests {
+		d, err := GetDialect(tt.provider)
+		assert.Equal(t, nil, err, tt.name)
+		assert.Equal(t, tt.placeholder, d.Placeholder(2), tt.name)
+		assert.Equal(t, tt.paging, d.Paging("select * from t;", 3, 10), tt.name)
+	}
+	_, err := GetDialect("db2")
+	assert.NotEqual(t, nil, err, "不支持的数据库类型")
+}
+
+func Test_sqlserverTPL(t *testing.T) {
+	ctx, err := tpl.GetDBContext("sqlserver")
+	assert.Equal(t, nil, err, "获取sqlserver模板")
+
+	query, args := ctx.GetSQLContext("select * from t where id=@id and name=@name", map[string]interface{}{"id": 1, "name": "hydra"})
+	assert.Equal(t, "select * from t where id=@p1 and name=@p2", query, "参数化语句")
+	assert.Equal(t, []interface{}{1, "hydra"}, args, "参数")
+
+	query, _ = ctx.GetSPContext("sp_order(@id,@name)", map[string]interface{}{"id": 1, "name": "hydra"})
+	assert.Equal(t, "exec sp_order @p1,@p2", query, "存储过程")
+
+	assert.Equal(t, "select * from t where id='1' and name=NULL", ctx.Replace("select * from t where id=@p1 and name=@p2", []interface{}{1}), "替换参数")
+}
+
+func TestGetDBDialect(t *testing.T) {
+	d, _ := GetDialect("postgres")
+	dl, ok := GetDBDialect(&dialectDB{dialect: d})
+	assert.Equal(t, true, ok, "已注册方言")
+	assert.Equal(t, "postgres", dl.Name(), "已注册方言")
+
+	_, ok = GetDBDialect(&dialectDB{})
+	assert.Equal(t, false, ok, "未注册方言")
+
+	err := checkDriver("sqlserver")
+	assert.NotEqual(t, nil, err, "未导入驱动包")
+}
+
+func TestIsTableNotFound(t *testing.T) {
+	assert.Equal(t, true, IsTableNotFound(errors.New("Error 1146: Table 'db.cron_history' doesn't exist")), "mysql")
+	assert.Equal(t, true, IsTableNotFound(errors.New(`pq: relation "cron_history" does not exist`)), "postgres")
+	assert.Equal(t, true, IsTableNotFound(errors.New("ORA-00942: table or view does not exist")), "oracle")
+	assert.Equal(t, false, IsTableNotFound(errors.New("dial tcp 127.0.0.1:3306: connect: connection refused")), "连接失败")
+	assert.Equal(t, false, IsTableNotFound(nil), "无错误")
+}
@@ -5,8 +5,21 @@ import "github.com/micro-plat/lib4go/db"
 //IDB 数据库接口
 type IDB = db.IDB
 
+//IDialectDB 可获取数据库方言的数据库对象，GetDB返回的已注册方言的数据库对象实现此接口
+type IDialectDB interface {
+	GetDialect() IDialect
+}
+
 //IComponentDB Component DB
 type IComponentDB interface {
 	GetRegularDB(names ...string) (d IDB)
 	GetDB(names ...string) (d IDB, err error)
 }
+
+//GetDBDialect 获取数据库对象的方言，未实现IDialectDB或方言未注册时返回false
+func GetDBDialect(d IDB) (IDialect, bool) {
+	if v, ok := d.(IDialectDB); ok && v.GetDialect() != nil {
+		return v.GetDialect(), true
+	}
+	return nil, false
+}
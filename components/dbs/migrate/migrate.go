@@ -0,0 +1,263 @@
+package migrate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/micro-plat/hydra/components/dbs"
+	"github.com/micro-plat/hydra/global"
+	"github.com/micro-plat/lib4go/security/md5"
+	"github.com/micro-plat/lib4go/types"
+)
+
+//TableName 迁移记录表名称
+const TableName = "schema_migrations"
+
+const (
+	//StatusApplied 已执行
+	StatusApplied = "applied"
+
+	//StatusPending 待执行
+	StatusPending = "pending"
+
+	//StatusModified 已执行，但脚本内容已变更
+	StatusModified = "modified"
+
+	//StatusMissing 已执行，但程序中未找到对应的脚本
+	StatusMissing = "missing"
+)
+
+var sqlCreateTable = `create table ` + TableName + `(
+version varchar(32) not null primary key,
+name varchar(128),
+checksum varchar(64) not null,
+applied_at varchar(32) not null)`
+
+var sqlCheckTable = `select count(1) from ` + TableName
+
+var sqlQueryRecords = `select version,name,checksum,applied_at from ` + TableName
+
+var sqlInsertRecord = `insert into ` + TableName + `(version,name,checksum,applied_at) values(@version,@name,@checksum,@applied_at)`
+
+var sqlDeleteRecord = `delete from ` + TableName + ` where version=@version`
+
+//State 迁移脚本状态
+type State struct {
+	Version   int64
+	Name      string
+	Status    string
+	Checksum  string
+	AppliedAt string
+}
+
+//Step 待执行的迁移步骤
+type Step struct {
+	Migration *global.Migration
+	SQLs      []string
+}
+
+//Migrator 数据库版本迁移
+type Migrator struct {
+	db         dbs.IDB
+	migrations []*global.Migration
+	*option
+}
+
+//New 构建数据库版本迁移程序
+func New(db dbs.IDB, migrations []*global.Migration, opts ...Option) *Migrator {
+	m := &Migrator{db: db, option: &option{}}
+	m.migrations = make([]*global.Migration, len(migrations))
+	copy(m.migrations, migrations)
+	sort.Slice(m.migrations, func(i, j int) bool {
+		return m.migrations[i].Version < m.migrations[j].Version
+	})
+	for _, opt := range opts {
+		opt(m.option)
+	}
+	return m
+}
+
+//Status 获取所有迁移脚本的执行状态
+func (m *Migrator) Status() ([]*State, error) {
+	records, err := m.getRecords()
+	if err != nil {
+		return nil, err
+	}
+	states := make([]*State, 0, len(m.migrations)+len(records))
+	for _, mg := range m.migrations {
+		s := &State{Version: mg.Version, Name: mg.Name, Status: StatusPending, Checksum: Checksum(mg)}
+		if r, ok := records[mg.Version]; ok {
+			s.Status = StatusApplied
+			s.AppliedAt = r.AppliedAt
+			if r.Checksum != s.Checksum {
+				s.Status = StatusModified
+			}
+			delete(records, mg.Version)
+		}
+		states = append(states, s)
+	}
+	for _, r := range records {
+		r.Status = StatusMissing
+		states = append(states, r)
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Version < states[j].Version
+	})
+	return states, nil
+}
+
+//Up 执行版本号不大于target的所有待执行脚本，target为0时执行所有待执行脚本
+func (m *Migrator) Up(target int64) ([]*Step, error) {
+	states, err := m.Status()
+	if err != nil {
+		return nil, err
+	}
+	steps := make([]*Step, 0, len(states))
+	for _, s := range states {
+		if s.Status == StatusModified && !m.ignoreChecksum {
+			return nil, fmt.Errorf("迁移脚本[%d.%s]已执行，但脚本内容已变更", s.Version, s.Name)
+		}
+		if s.Status != StatusPending || (target > 0 && s.Version > target) {
+			continue
+		}
+		mg := m.get(s.Version)
+		steps = append(steps, &Step{Migration: mg, SQLs: mg.GetUpSQLs()})
+	}
+	if m.dryRun {
+		return steps, nil
+	}
+	for i, step := range steps {
+		if err := m.apply(step, true); err != nil {
+			return steps[:i], err
+		}
+	}
+	return steps, nil
+}
+
+//Down 按版本号从大到小回滚count个已执行脚本，target大于0时回滚所有版本号大于target的脚本
+func (m *Migrator) Down(count int, target int64) ([]*Step, error) {
+	states, err := m.Status()
+	if err != nil {
+		return nil, err
+	}
+	steps := make([]*Step, 0, count)
+	for i := len(states) - 1; i >= 0; i-- {
+		s := states[i]
+		if s.Status == StatusPending {
+			continue
+		}
+		if target > 0 && s.Version <= target {
+			break
+		}
+		if target <= 0 && len(steps) >= count {
+			break
+		}
+		if s.Status == StatusMissing {
+			return nil, fmt.Errorf("迁移脚本[%d.%s]在程序中不存在，无法回滚", s.Version, s.Name)
+		}
+		if s.Status == StatusModified && !m.ignoreChecksum {
+			return nil, fmt.Errorf("迁移脚本[%d.%s]已执行，但脚本内容已变更，无法回滚", s.Version, s.Name)
+		}
+		mg := m.get(s.Version)
+		sqls := mg.GetDownSQLs()
+		if len(sqls) == 0 {
+			return nil, fmt.Errorf("迁移脚本[%d.%s]未指定回滚语句", s.Version, s.Name)
+		}
+		steps = append(steps, &Step{Migration: mg, SQLs: sqls})
+	}
+	if m.dryRun {
+		return steps, nil
+	}
+	for i, step := range steps {
+		if err := m.apply(step, false); err != nil {
+			return steps[:i], err
+		}
+	}
+	return steps, nil
+}
+
+//apply 在同一事务中执行脚本并更新迁移记录
+func (m *Migrator) apply(step *Step, up bool) (err error) {
+	trans, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			trans.Rollback()
+		}
+	}()
+	for _, sql := range step.SQLs {
+		if _, err = trans.Execute(sql, nil); err != nil {
+			return fmt.Errorf("迁移脚本[%d.%s]执行失败:%w", step.Migration.Version, step.Migration.Name, err)
+		}
+	}
+	input := map[string]interface{}{
+		"version":    fmt.Sprint(step.Migration.Version),
+		"name":       step.Migration.Name,
+		"checksum":   Checksum(step.Migration),
+		"applied_at": time.Now().Format("2006-01-02 15:04:05"),
+	}
+	sql := sqlDeleteRecord
+	if up {
+		sql = sqlInsertRecord
+	}
+	if _, err = trans.Execute(sql, input); err != nil {
+		return fmt.Errorf("迁移记录[%d.%s]保存失败:%w", step.Migration.Version, step.Migration.Name, err)
+	}
+	return trans.Commit()
+}
+
+//getRecords 获取已执行的迁移记录，记录表不存在时自动创建
+func (m *Migrator) getRecords() (map[int64]*State, error) {
+	records := make(map[int64]*State)
+	if _, err := m.db.Scalar(sqlCheckTable, nil); err != nil {
+		if !dbs.IsTableNotFound(err) {
+			return nil, fmt.Errorf("查询迁移记录表%s失败:%w", TableName, err)
+		}
+		if m.dryRun {
+			return records, nil
+		}
+		if _, err := m.db.Execute(sqlCreateTable, nil); err != nil {
+			return nil, fmt.Errorf("创建迁移记录表%s失败:%w", TableName, err)
+		}
+	}
+	rows, err := m.db.Query(sqlQueryRecords, nil)
+	if err != nil {
+		return nil, fmt.Errorf("查询迁移记录失败:%w", err)
+	}
+	for _, row := range rows {
+		version := types.GetInt64(getValue(row, "version"))
+		records[version] = &State{
+			Version:   version,
+			Name:      getValue(row, "name"),
+			Checksum:  getValue(row, "checksum"),
+			AppliedAt: getValue(row, "applied_at"),
+		}
+	}
+	return records, nil
+}
+
+func (m *Migrator) get(version int64) *global.Migration {
+	for _, mg := range m.migrations {
+		if mg.Version == version {
+			return mg
+		}
+	}
+	return nil
+}
+
+//Checksum 计算升级脚本的校验码
+func Checksum(m *global.Migration) string {
+	return md5.Encrypt(strings.Join(m.GetUpSQLs(), ";"))
+}
+
+//getValue 获取字段值，兼容返回大写列名的数据库
+func getValue(row types.XMap, name string) string {
+	if v, ok := row.Get(name); ok {
+		return types.GetString(v)
+	}
+	return row.GetString(strings.ToUpper(name))
+}
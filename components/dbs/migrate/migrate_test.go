@@ -0,0 +1,190 @@
+package migrate
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/micro-plat/hydra/components/dbs"
+	"github.com/micro-plat/hydra/global"
+	"github.com/micro-plat/lib4go/assert"
+	"github.com/micro-plat/lib4go/db"
+)
+
+//memDB 记录执行语句并在内存中维护迁移记录表
+type memDB struct {
+	hasTable  bool
+	scalarErr error
+	records   map[string]map[string]interface{}
+	executed  []string
+	failOn    string
+}
+
+func newMemDB() *memDB {
+	return &memDB{records: make(map[string]map[string]interface{})}
+}
+
+func (m *memDB) Query(sql string, input map[string]interface{}) (db.QueryRows, error) {
+	rows := db.NewQueryRows()
+	for _, r := range m.records {
+		row := db.NewQueryRow()
+		row.MergeMap(r)
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+func (m *memDB) Scalar(sql string, input map[string]interface{}) (interface{}, error) {
+	if m.scalarErr != nil {
+		return nil, m.scalarErr
+	}
+	if !m.hasTable {
+		return nil, fmt.Errorf("Error 1146: Table 'hydra.schema_migrations' doesn't exist")
+	}
+	return len(m.records), nil
+}
+func (m *memDB) Execute(sql string, input map[string]interface{}) (int64, error) {
+	switch {
+	case sql == sqlCreateTable:
+		m.hasTable = true
+	case sql == sqlInsertRecord:
+		m.records[input["version"].(string)] = input
+	case sql == sqlDeleteRecord:
+		delete(m.records, input["version"].(string))
+	case m.failOn != "" && strings.Contains(sql, m.failOn):
+		return 0, fmt.Errorf("执行失败")
+	default:
+		m.executed = append(m.executed, sql)
+	}
+	return 1, nil
+}
+func (m *memDB) Executes(sql string, input map[string]interface{}) (int64, int64, error) {
+	row, err := m.Execute(sql, input)
+	return 0, row, err
+}
+func (m *memDB) ExecuteSP(procName string, input map[string]interface{}, output ...interface{}) (int64, error) {
+	return 0, nil
+}
+func (m *memDB) Begin() (db.IDBTrans, error) {
+	return &memTrans{memDB: m}, nil
+}
+func (m *memDB) Close() {}
+func (m *memDB) GetDialect() dbs.IDialect {
+	d, _ := dbs.GetDialect("mysql")
+	return d
+}
+
+type memTrans struct {
+	*memDB
+}
+
+func (t *memTrans) Rollback() error { return nil }
+func (t *memTrans) Commit() error   { return nil }
+
+var migrations = []*global.Migration{
+	{Version: 2, Name: "add_index", Up: "create index idx_a on t(a)", Down: "drop index idx_a"},
+	{Version: 1, Name: "create_t", Up: "create table t(a int);insert into t values(1)", Down: "drop table t"},
+	{Version: 3, Name: "add_column", Up: "alter table t add b int"},
+}
+
+func TestMigrator_Up(t *testing.T) {
+	mdb := newMemDB()
+	steps, err := New(mdb, migrations).Up(2)
+	assert.Equal(t, nil, err, "执行到版本2")
+	assert.Equal(t, 2, len(steps), "执行到版本2")
+	assert.Equal(t, []string{"create table t(a int)", "insert into t values(1)", "create index idx_a on t(a)"}, mdb.executed, "按版本顺序执行")
+
+	steps, err = New(mdb, migrations).Up(0)
+	assert.Equal(t, nil, err, "执行剩余版本")
+	assert.Equal(t, 1, len(steps), "执行剩余版本")
+	assert.Equal(t, int64(3), steps[0].Migration.Version, "执行剩余版本")
+
+	steps, err = New(mdb, migrations).Up(0)
+	assert.Equal(t, nil, err, "无待执行版本")
+	assert.Equal(t, 0, len(steps), "无待执行版本")
+}
+
+func TestMigrator_DryRun(t *testing.T) {
+	mdb := newMemDB()
+	steps, err := New(mdb, migrations, WithDryRun()).Up(0)
+	assert.Equal(t, nil, err, "dry-run")
+	assert.Equal(t, 3, len(steps), "dry-run返回所有待执行脚本")
+	assert.Equal(t, false, mdb.hasTable, "dry-run不创建记录表")
+	assert.Equal(t, 0, len(mdb.executed), "dry-run不执行脚本")
+}
+
+func TestMigrator_Status(t *testing.T) {
+	mdb := newMemDB()
+	_, err := New(mdb, migrations).Up(2)
+	assert.Equal(t, nil, err, "执行到版本2")
+
+	changed := []*global.Migration{
+		{Version: 2, Name: "add_index", Up: "create index idx_b on t(b)"},
+		{Version: 3, Name: "add_column", Up: "alter table t add b int"},
+	}
+	states, err := New(mdb, changed).Status()
+	assert.Equal(t, nil, err, "查询状态")
+	assert.Equal(t, 3, len(states), "查询状态")
+	assert.Equal(t, StatusMissing, states[0].Status, "程序中已删除的脚本")
+	assert.Equal(t, StatusModified, states[1].Status, "内容变更的脚本")
+	assert.Equal(t, StatusPending, states[2].Status, "待执行的脚本")
+
+	_, err = New(mdb, changed).Up(0)
+	assert.NotEqual(t, nil, err, "存在内容变更的脚本时不能执行")
+}
+
+func TestMigrator_Down(t *testing.T) {
+	mdb := newMemDB()
+	_, err := New(mdb, migrations).Up(2)
+	assert.Equal(t, nil, err, "执行到版本2")
+
+	steps, err := New(mdb, migrations).Down(1, 0)
+	assert.Equal(t, nil, err, "回滚一个版本")
+	assert.Equal(t, 1, len(steps), "回滚一个版本")
+	assert.Equal(t, int64(2), steps[0].Migration.Version, "回滚一个版本")
+	assert.Equal(t, 1, len(mdb.records), "回滚一个版本")
+
+	_, err = New(mdb, migrations).Up(0)
+	assert.Equal(t, nil, err, "执行所有版本")
+	_, err = New(mdb, migrations).Down(1, 0)
+	assert.NotEqual(t, nil, err, "未指定回滚语句")
+
+	steps, err = New(mdb, migrations[:2]).Down(0, 1)
+	assert.NotEqual(t, nil, err, "程序中不存在的版本不能回滚")
+	assert.Equal(t, 0, len(steps), "程序中不存在的版本不能回滚")
+}
+
+func TestMigrator_DownModified(t *testing.T) {
+	mdb := newMemDB()
+	_, err := New(mdb, migrations).Up(2)
+	assert.Equal(t, nil, err, "执行到版本2")
+
+	changed := []*global.Migration{
+		migrations[1],
+		{Version: 2, Name: "add_index", Up: "create index idx_b on t(b)", Down: "drop index idx_b"},
+	}
+	steps, err := New(mdb, changed).Down(1, 0)
+	assert.NotEqual(t, nil, err, "内容变更的脚本不能回滚")
+	assert.Equal(t, 0, len(steps), "内容变更的脚本不能回滚")
+	assert.Equal(t, 2, len(mdb.records), "内容变更的脚本不能回滚")
+
+	steps, err = New(mdb, changed, WithIgnoreChecksum()).Down(1, 0)
+	assert.Equal(t, nil, err, "忽略内容变更")
+	assert.Equal(t, 1, len(steps), "忽略内容变更")
+}
+
+func TestMigrator_QueryFailed(t *testing.T) {
+	mdb := newMemDB()
+	mdb.scalarErr = fmt.Errorf("dial tcp 127.0.0.1:3306: connect: connection refused")
+	_, err := New(mdb, migrations).Status()
+	assert.NotEqual(t, nil, err, "非表不存在的错误直接返回")
+	assert.Equal(t, false, mdb.hasTable, "非表不存在的错误不创建记录表")
+}
+
+func TestMigrator_UpFailed(t *testing.T) {
+	mdb := newMemDB()
+	mdb.failOn = "idx_a"
+	steps, err := New(mdb, migrations).Up(0)
+	assert.NotEqual(t, nil, err, "执行失败")
+	assert.Equal(t, 1, len(steps), "返回已执行成功的脚本")
+	assert.Equal(t, 1, len(mdb.records), "失败的脚本不记录")
+}
@@ -0,0 +1,23 @@
+package migrate
+
+type option struct {
+	dryRun         bool
+	ignoreChecksum bool
+}
+
+//Option 配置选项
+type Option func(*option)
+
+//WithDryRun 只计算待执行的脚本，不修改数据库
+func WithDryRun() Option {
+	return func(o *option) {
+		o.dryRun = true
+	}
+}
+
+//WithIgnoreChecksum 忽略已执行脚本的内容变更
+func WithIgnoreChecksum() Option {
+	return func(o *option) {
+		o.ignoreChecksum = true
+	}
+}
@@ -0,0 +1,58 @@
+package dbs
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/micro-plat/lib4go/db/tpl"
+)
+
+func init() {
+	tpl.Register("sqlserver", sqlserverTPL{prefix: "@p"})
+}
+
+//sqlserverTPL sql server参数模板，使用@p1,@p2...作为占位符
+type sqlserverTPL struct {
+	prefix string
+}
+
+//GetSQLContext 获取查询串
+func (o sqlserverTPL) GetSQLContext(t string, input map[string]interface{}) (sql string, args []interface{}) {
+	index := 0
+	f := func() string {
+		index++
+		return fmt.Sprint(o.prefix, index)
+	}
+	sql, args, _ = tpl.AnalyzeTPL(t, input, f, o.like)
+	return sql, args
+}
+
+//GetSPContext 获取存储过程
+func (o sqlserverTPL) GetSPContext(t string, input map[string]interface{}) (sql string, args []interface{}) {
+	q, args := o.GetSQLContext(strings.TrimSpace(strings.Trim(t, ";")), input)
+	if i := strings.Index(q, "("); i > 0 && strings.HasSuffix(q, ")") {
+		q = fmt.Sprintf("%s %s", q[:i], q[i+1:len(q)-1])
+	}
+	return fmt.Sprintf("exec %s", q), args
+}
+
+func (o sqlserverTPL) like(key string, prefix func() string) string {
+	return fmt.Sprintf("'%%'+%s+'%%'", prefix())
+}
+
+//Replace 替换SQL中的占位符
+func (o sqlserverTPL) Replace(sql string, args []interface{}) (r string) {
+	if sql == "" || args == nil {
+		return sql
+	}
+	word := regexp.MustCompile(fmt.Sprintf(`%s(\d+)`, o.prefix))
+	return word.ReplaceAllStringFunc(sql, func(s string) string {
+		k, err := strconv.Atoi(s[len(o.prefix):])
+		if err != nil || k < 1 || len(args) < k {
+			return "NULL"
+		}
+		return fmt.Sprintf("'%v'", args[k-1])
+	})
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/micro-plat/hydra/registry"
 	"github.com/micro-plat/lib4go/logger"
+	"github.com/micro-plat/lib4go/types"
 )
 
 //DLock 分布式锁
@@ -20,6 +21,7 @@ type DLock struct {
 	done      bool
 	closeChan chan struct{}
 	master    bool
+	own       bool
 }
 
 //NewLock 构建分布式锁
@@ -28,10 +30,12 @@ func NewLock(lockName string, registryAddr string, l logger.ILogging) (lk *DLock
 	if err != nil {
 		return nil, err
 	}
-	return NewLockByRegistry(lockName, r), nil
+	lk = NewLockByRegistry(lockName, r)
+	lk.own = true
+	return lk, nil
 }
 
-//NewLockByRegistry 根据当前注册中心创建分布式锁
+//NewLockByRegistry 根据当前注册中心创建分布式锁，释放锁时不关闭注册中心
 func NewLockByRegistry(lockName string, r registry.IRegistry) (lk *DLock) {
 	lk = &DLock{name: lockName, registry: r, closeChan: make(chan struct{})}
 	return lk
@@ -126,7 +130,17 @@ func (d *DLock) Unlock() {
 	d.done = true
 	close(d.closeChan)
 	d.registry.Delete(d.path)
-	d.registry.Close()
+	if d.own {
+		d.registry.Close()
+	}
+}
+
+//GetToken 获取防护令牌，使用注册中心顺序节点的序号
+func (d *DLock) GetToken() int64 {
+	if d.path == "" {
+		return 0
+	}
+	return types.GetInt64(strings.TrimLeft(d.path[strings.LastIndex(d.path, "dlock_")+len("dlock_"):], "0"))
 }
 
 func isMaster(path string, root string, cldrs []string) bool {
@@ -7,4 +7,12 @@ type ILock interface {
 	TryLock() (err error)
 	Lock(timeout ...time.Duration) (err error)
 	Unlock()
+
+	//GetToken 获取防护令牌，每次获取锁时单调递增
+	GetToken() int64
+}
+
+//IComponentDLock Component DLock
+type IComponentDLock interface {
+	GetLock(name string, opts ...Option) (ILock, error)
 }
@@ -0,0 +1,41 @@
+package dlock
+
+import (
+	"fmt"
+
+	"github.com/micro-plat/hydra/components/container"
+	"github.com/micro-plat/hydra/components/pkgs/redis"
+	"github.com/micro-plat/hydra/conf"
+	varredis "github.com/micro-plat/hydra/conf/vars/redis"
+	"github.com/micro-plat/hydra/context"
+	"github.com/micro-plat/hydra/global"
+	"github.com/micro-plat/hydra/registry"
+)
+
+//StandardDLock dlock
+type StandardDLock struct {
+	c container.IContainer
+}
+
+//NewStandardDLock 创建分布式锁组件
+func NewStandardDLock(c container.IContainer) *StandardDLock {
+	return &StandardDLock{c: c}
+}
+
+//GetLock 获取分布式锁，指定WithRedis时使用redis实现，否则使用注册中心实现
+func (s *StandardDLock) GetLock(name string, opts ...Option) (ILock, error) {
+	o := newOption(opts...)
+	if o.redis == "" {
+		return NewLock(registry.Join(global.Def.PlatName, "dlock", name), global.Def.RegistryAddr, context.Current().Log())
+	}
+	obj, err := s.c.GetOrCreate(varredis.TypeNodeName, o.redis, func(conf *conf.RawConf, keys ...string) (interface{}, error) {
+		if conf.IsEmpty() {
+			return nil, fmt.Errorf("节点/%s/%s未配置，或不可用", varredis.TypeNodeName, o.redis)
+		}
+		return redis.NewByConfig(varredis.NewByRaw(string(conf.GetRaw())))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return NewRLock(name, obj.(*redis.Client), opts...), nil
+}
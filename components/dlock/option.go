@@ -0,0 +1,47 @@
+package dlock
+
+import "time"
+
+//minTTL redis锁的最小租约时长
+const minTTL = time.Second
+
+type option struct {
+	redis string
+	ttl   time.Duration
+	retry time.Duration
+}
+
+func newOption(opts ...Option) *option {
+	o := &option{ttl: 30 * time.Second, retry: 100 * time.Millisecond}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.ttl < minTTL {
+		o.ttl = minTTL
+	}
+	return o
+}
+
+//Option 配置选项
+type Option func(*option)
+
+//WithRedis 使用redis实现分布式锁，name为/var/redis下的配置名称
+func WithRedis(name string) Option {
+	return func(o *option) {
+		o.redis = name
+	}
+}
+
+//WithTTL 设置redis锁的租约时长，持有期间每隔1/3租约时长自动续期，小于1秒时使用1秒
+func WithTTL(ttl time.Duration) Option {
+	return func(o *option) {
+		o.ttl = ttl
+	}
+}
+
+//WithRetry 设置redis锁获取失败后的重试间隔
+func WithRetry(retry time.Duration) Option {
+	return func(o *option) {
+		o.retry = retry
+	}
+}
@@ -0,0 +1,207 @@
+package dlock
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/micro-plat/hydra/global"
+)
+
+//释放锁，仅持有者可删除
+const releaseScript = `
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0`
+
+//延长租约，仅持有者可延长
+const renewScript = `
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0`
+
+//redisClient 分布式锁使用的redis命令
+type redisClient interface {
+	SetNX(key string, value interface{}, expiration time.Duration) *redis.BoolCmd
+	Incr(key string) *redis.IntCmd
+	Eval(script string, keys []string, args ...interface{}) *redis.Cmd
+}
+
+//holder 当前进程中锁的持有信息，同一上下文重复获取时只增加计数
+type holder struct {
+	count    int
+	token    int64
+	done     chan struct{}
+	lost     chan struct{}
+	lostOnce sync.Once
+}
+
+//setLost 标记租约已失效
+func (h *holder) setLost() {
+	h.lostOnce.Do(func() { close(h.lost) })
+}
+
+var holders = map[string]*holder{}
+var holderLock sync.Mutex
+
+//RLock 基于redis的分布式锁，支持租约自动续期、防护令牌与同一上下文重入
+type RLock struct {
+	key    string
+	owner  string
+	client redisClient
+	ttl    time.Duration
+	retry  time.Duration
+	holder *holder
+}
+
+//NewRLock 构建基于redis的分布式锁
+func NewRLock(lockName string, client redisClient, opts ...Option) *RLock {
+	o := newOption(opts...)
+	return &RLock{
+		key:    fmt.Sprintf("%s:dlock:%s", global.Def.PlatName, lockName),
+		owner:  fmt.Sprintf("%s:%s", global.LocalIP(), global.RID.GetXRequestID()),
+		client: client,
+		ttl:    o.ttl,
+		retry:  o.retry,
+	}
+}
+
+//TryLock 偿试获取分布式锁，访问redis时不持有进程内的锁，避免redis较慢时阻塞其它分布式锁
+func (d *RLock) TryLock() (err error) {
+	holderLock.Lock()
+	h, ok := holders[d.holderKey()]
+	holderLock.Unlock()
+
+	//当前上下文已持有锁，租约有效时增加计数
+	if ok {
+		select {
+		case <-h.lost:
+			return fmt.Errorf("分布式锁%s的租约已失效", d.key)
+		default:
+		}
+		n, err := d.client.Eval(renewScript, []string{d.key}, d.owner, int64(d.ttl/time.Millisecond)).Int64()
+		if err != nil {
+			return fmt.Errorf("检查分布式锁%s的租约失败:%w", d.key, err)
+		}
+		if n == 0 {
+			h.setLost()
+			return fmt.Errorf("分布式锁%s的租约已失效", d.key)
+		}
+		holderLock.Lock()
+		defer holderLock.Unlock()
+		if holders[d.holderKey()] != h {
+			return fmt.Errorf("分布式锁%s已释放", d.key)
+		}
+		h.count++
+		d.holder = h
+		return nil
+	}
+
+	ok, err = d.client.SetNX(d.key, d.owner, d.ttl).Result()
+	if err != nil {
+		return fmt.Errorf("获取分布式锁%s失败:%w", d.key, err)
+	}
+	if !ok {
+		return fmt.Errorf("未获取到分布式锁")
+	}
+	token, err := d.client.Incr(d.key + ":fence").Result()
+	if err != nil {
+		d.client.Eval(releaseScript, []string{d.key}, d.owner)
+		return fmt.Errorf("获取分布式锁%s的防护令牌失败:%w", d.key, err)
+	}
+	h = &holder{count: 1, token: token, done: make(chan struct{}), lost: make(chan struct{})}
+	holderLock.Lock()
+	d.holder = h
+	holders[d.holderKey()] = h
+	holderLock.Unlock()
+	go d.watchdog(h)
+	return nil
+}
+
+//Lock 以独占方式获取分布式锁
+func (d *RLock) Lock(timeout ...time.Duration) (err error) {
+	deadline := time.Minute
+	if len(timeout) > 0 {
+		deadline = timeout[0]
+	}
+	expire := time.After(deadline)
+	for {
+		if err = d.TryLock(); err == nil {
+			return nil
+		}
+		select {
+		case <-expire:
+			return fmt.Errorf("超时未获取到分布式锁:%w", err)
+		case <-global.Def.ClosingNotify():
+			return fmt.Errorf("服务关闭，未获取到分布式锁")
+		case <-time.After(d.retry):
+		}
+	}
+}
+
+//Unlock 释放分布式锁，重入的锁在最后一次释放时删除
+func (d *RLock) Unlock() {
+	holderLock.Lock()
+	if d.holder == nil {
+		holderLock.Unlock()
+		return
+	}
+	h := d.holder
+	d.holder = nil
+	h.count--
+	if h.count > 0 {
+		holderLock.Unlock()
+		return
+	}
+	delete(holders, d.holderKey())
+	close(h.done)
+	holderLock.Unlock()
+	d.client.Eval(releaseScript, []string{d.key}, d.owner)
+}
+
+//GetToken 获取防护令牌，每次获取锁时单调递增，下游写入时可用于拒绝过期持有者的请求
+func (d *RLock) GetToken() int64 {
+	holderLock.Lock()
+	defer holderLock.Unlock()
+	if d.holder == nil {
+		return 0
+	}
+	return d.holder.token
+}
+
+//Lost 租约续期失败(锁已被其它持有者获取)时关闭
+func (d *RLock) Lost() <-chan struct{} {
+	holderLock.Lock()
+	defer holderLock.Unlock()
+	if d.holder == nil {
+		ch := make(chan struct{})
+		close(ch)
+		return ch
+	}
+	return d.holder.lost
+}
+
+//watchdog 持有锁期间定时延长租约
+func (d *RLock) watchdog(h *holder) {
+	tk := time.NewTicker(d.ttl / 3)
+	defer tk.Stop()
+	for {
+		select {
+		case <-h.done:
+			return
+		case <-tk.C:
+			n, err := d.client.Eval(renewScript, []string{d.key}, d.owner, int64(d.ttl/time.Millisecond)).Int64()
+			if err == nil && n == 0 {
+				h.setLost()
+				return
+			}
+		}
+	}
+}
+
+func (d *RLock) holderKey() string {
+	return d.key + "|" + d.owner
+}
@@ -0,0 +1,143 @@
+package dlock
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/micro-plat/lib4go/assert"
+)
+
+//memRedis 内存中模拟分布式锁使用的redis命令
+type memRedis struct {
+	lock   sync.Mutex
+	values map[string]string
+	fence  int64
+	renews int
+}
+
+func newMemRedis() *memRedis {
+	return &memRedis{values: make(map[string]string)}
+}
+
+func (m *memRedis) SetNX(key string, value interface{}, expiration time.Duration) *redis.BoolCmd {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if _, ok := m.values[key]; ok {
+		return redis.NewBoolResult(false, nil)
+	}
+	m.values[key] = value.(string)
+	return redis.NewBoolResult(true, nil)
+}
+
+func (m *memRedis) Incr(key string) *redis.IntCmd {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.fence++
+	return redis.NewIntResult(m.fence, nil)
+}
+
+func (m *memRedis) Eval(script string, keys []string, args ...interface{}) *redis.Cmd {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.values[keys[0]] != args[0].(string) {
+		return redis.NewCmdResult(int64(0), nil)
+	}
+	switch script {
+	case releaseScript:
+		delete(m.values, keys[0])
+	case renewScript:
+		m.renews++
+	}
+	return redis.NewCmdResult(int64(1), nil)
+}
+
+func TestRLock_Reentrant(t *testing.T) {
+	client := newMemRedis()
+	l1 := NewRLock("order", client)
+	l2 := NewRLock("order", client)
+
+	assert.Equal(t, nil, l1.TryLock(), "首次获取锁")
+	assert.Equal(t, nil, l2.TryLock(), "同一上下文重入")
+	assert.Equal(t, int64(1), l1.GetToken(), "防护令牌")
+	assert.Equal(t, l1.GetToken(), l2.GetToken(), "重入时令牌不变")
+
+	l2.Unlock()
+	assert.Equal(t, 1, len(client.values), "重入的锁未全部释放")
+	l1.Unlock()
+	assert.Equal(t, 0, len(client.values), "全部释放后删除")
+	assert.Equal(t, int64(0), l1.GetToken(), "释放后令牌清空")
+}
+
+func TestRLock_ReentrantLost(t *testing.T) {
+	client := newMemRedis()
+	l1 := NewRLock("order", client)
+	l2 := NewRLock("order", client)
+	l3 := NewRLock("order", client)
+	assert.Equal(t, nil, l1.TryLock(), "首次获取锁")
+
+	//租约过期后被其它持有者获取
+	client.lock.Lock()
+	client.values[l1.key] = "other"
+	client.lock.Unlock()
+	assert.NotEqual(t, nil, l2.TryLock(), "租约失效后不能重入")
+	assert.Equal(t, int64(0), l2.GetToken(), "重入失败时未持有锁")
+	select {
+	case <-l1.Lost():
+	default:
+		t.Error("租约失效时未通知")
+	}
+	assert.NotEqual(t, nil, l3.TryLock(), "已标记失效后不能重入")
+	l1.Unlock()
+}
+
+func TestRLock_Exclusive(t *testing.T) {
+	client := newMemRedis()
+	l1 := NewRLock("order", client)
+	assert.Equal(t, nil, l1.TryLock(), "获取锁")
+
+	var other *RLock
+	var err error
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		other = NewRLock("order", client, WithRetry(10*time.Millisecond))
+		assert.NotEqual(t, nil, other.TryLock(), "其它上下文无法获取")
+		err = other.Lock(time.Second)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	l1.Unlock()
+	<-done
+
+	assert.Equal(t, nil, err, "释放后其它上下文获取成功")
+	assert.Equal(t, int64(2), other.GetToken(), "令牌单调递增")
+	other.Unlock()
+}
+
+func TestRLock_Watchdog(t *testing.T) {
+	client := newMemRedis()
+	l := NewRLock("order", client)
+	l.ttl = 30 * time.Millisecond
+	assert.Equal(t, nil, l.TryLock(), "获取锁")
+	time.Sleep(50 * time.Millisecond)
+
+	client.lock.Lock()
+	assert.Equal(t, true, client.renews > 0, "持有期间自动续期")
+	delete(client.values, l.key)
+	client.lock.Unlock()
+
+	select {
+	case <-l.Lost():
+	case <-time.After(time.Second):
+		t.Error("续期失败时未通知")
+	}
+	l.Unlock()
+}
+
+func TestWithTTL(t *testing.T) {
+	assert.Equal(t, 30*time.Second, newOption().ttl, "默认租约时长")
+	assert.Equal(t, 5*time.Second, newOption(WithTTL(5*time.Second)).ttl, "设置租约时长")
+	assert.Equal(t, minTTL, newOption(WithTTL(0)).ttl, "租约时长为0")
+	assert.Equal(t, minTTL, newOption(WithTTL(time.Millisecond)).ttl, "租约时长小于1秒")
+}
@@ -1,6 +1,7 @@
 package pkgs
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"reflect"
@@ -43,6 +44,23 @@ func GetStringByHeader(name string, content interface{}, hd ...string) string {
 	return string(out.Marshal())
 }
 
+//GetDataByHeader 获取GetStringByHeader封装的消息内容，未封装时返回原消息
+func GetDataByHeader(message string) string {
+	input := make(map[string]interface{})
+	if err := json.Unmarshal(types.StringToBytes(message), &input); err != nil {
+		return message
+	}
+	v, ok := input["__data__"].(string)
+	if !ok {
+		return message
+	}
+	buff, err := base64.StdEncoding.DecodeString(v)
+	if err != nil {
+		return message
+	}
+	return string(buff)
+}
+
 //GetString 将任意类型转换为字符串，map,struct等转换为json
 func GetString(content interface{}) string {
 	vtpKind := getTypeKind(content)
@@ -64,8 +64,9 @@ func (c *Client) Request(method string, url string, params string, charset strin
 	return
 }
 
+//getCert 构建tls配置，设置ca证书或启用验证时验证服务器证书
 func getCert(c *varhttp.HTTPConf) (*tls.Config, error) {
-	ssl := &tls.Config{InsecureSkipVerify: true}
+	ssl := &tls.Config{InsecureSkipVerify: !c.Verify && c.Ca == "", ServerName: c.ServerName}
 	if len(c.Certs) == 2 {
 		cert, err := tls.LoadX509KeyPair(c.Certs[0], c.Certs[1])
 		if err != nil {
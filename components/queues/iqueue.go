@@ -1,6 +1,8 @@
 package queues
 
 import (
+	r "context"
+
 	"github.com/micro-plat/hydra/components/pkgs"
 	"github.com/micro-plat/hydra/components/queues/mq"
 	"github.com/micro-plat/hydra/context"
@@ -10,6 +12,10 @@ import (
 //IQueue 消息队列
 type IQueue interface {
 	Send(key string, value interface{}, requestID ...string) error
+
+	//Subscribe 订阅队列消息直到ctx结束，结束后关闭通道，用于向sse等长连接推送消息。
+	//当前节点的每个订阅都会收到全部消息，多个节点之间仍为竞争消费；订阅者处理不及时(缓冲已满)时丢弃消息
+	Subscribe(ctx r.Context, key string) (<-chan string, error)
 }
 
 //IComponentQueue Component Queue
@@ -20,17 +26,25 @@ type IComponentQueue interface {
 
 //queue 对输入KEY进行封装处理
 type queue struct {
-	q mq.IMQP
+	q       mq.IMQP
+	proto   string
+	confRaw string
 }
 
 func newQueue(proto string, confRaw string) (q *queue, err error) {
-	q = &queue{}
+	q = &queue{proto: proto, confRaw: confRaw}
 	q.q, err = mq.NewMQP(proto, confRaw)
 	return q, err
 }
 
 //Send 发送消息
-func (q *queue) Send(key string, value interface{}, requestID ...string) error {
+func (q *queue) Send(key string, value interface{}, requestID ...string) (err error) {
+	name := global.MQConf.GetQueueName(key)
+	span := context.StartSpan("queue", "queue.send")
+	span.SetTag("mq.broker", q.proto)
+	span.SetTag("mq.queue", name)
+	defer func() { span.SetError(err); span.End() }()
+
 	hd := make([]string, 0, 2)
 	if len(requestID) > 0 {
 		hd = append(hd, context.XRequestID, requestID[0])
@@ -39,7 +53,12 @@ func (q *queue) Send(key string, value interface{}, requestID ...string) error {
 			hd = append(hd, context.XRequestID, ctx.User().GetTraceID())
 		}
 	}
-	return q.q.Push(global.MQConf.GetQueueName(key), pkgs.GetStringByHeader(key, value, hd...))
+	return q.q.Push(name, pkgs.GetStringByHeader(key, value, hd...))
+}
+
+//Subscribe 订阅消息，当前节点的所有订阅共用一个消费者，每条消息分发给所有订阅者
+func (q *queue) Subscribe(ctx r.Context, key string) (<-chan string, error) {
+	return subscribe(ctx, q.proto, q.confRaw, global.MQConf.GetQueueName(key))
 }
 
 func (q *queue) Close() error {
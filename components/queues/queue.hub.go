@@ -0,0 +1,96 @@
+package queues
+
+import (
+	r "context"
+	"fmt"
+	"sync"
+
+	"github.com/micro-plat/hydra/components/pkgs"
+	"github.com/micro-plat/hydra/components/queues/mq"
+)
+
+//subBuffer 每个订阅者的消息缓冲数
+const subBuffer = 64
+
+//hub 队列订阅中心，使用一个消费者消费队列消息并分发给所有订阅者
+type hub struct {
+	key      string
+	name     string
+	consumer mq.IMQC
+	subs     map[chan string]struct{}
+	lock     sync.Mutex
+}
+
+var hubs = make(map[string]*hub)
+var hubLock sync.Mutex
+
+//subscribe 订阅队列消息，首个订阅者创建消费者，最后一个订阅者退出时关闭消费者
+func subscribe(ctx r.Context, proto string, confRaw string, name string) (<-chan string, error) {
+	key := fmt.Sprintf("%s:%s:%s", proto, confRaw, name)
+	hubLock.Lock()
+	defer hubLock.Unlock()
+	h, ok := hubs[key]
+	if !ok {
+		var err error
+		if h, err = newHub(key, proto, confRaw, name); err != nil {
+			return nil, err
+		}
+		hubs[key] = h
+	}
+	ch := make(chan string, subBuffer)
+	h.lock.Lock()
+	h.subs[ch] = struct{}{}
+	h.lock.Unlock()
+	go func() {
+		<-ctx.Done()
+		h.remove(ch)
+	}()
+	return ch, nil
+}
+
+func newHub(key string, proto string, confRaw string, name string) (*hub, error) {
+	consumer, err := mq.NewMQC(proto, confRaw)
+	if err != nil {
+		return nil, err
+	}
+	if err := consumer.Connect(); err != nil {
+		return nil, fmt.Errorf("连接消息队列失败:%w", err)
+	}
+	h := &hub{key: key, name: name, consumer: consumer, subs: make(map[chan string]struct{})}
+	if err := consumer.Consume(name, 1, h.dispatch); err != nil {
+		consumer.Close()
+		return nil, fmt.Errorf("订阅消息队列(%s)失败:%w", name, err)
+	}
+	return h, nil
+}
+
+//dispatch 将消息分发给所有订阅者，订阅者缓冲已满时丢弃
+func (h *hub) dispatch(m mq.IMQCMessage) {
+	msg := pkgs.GetDataByHeader(m.GetMessage())
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	for ch := range h.subs {
+		select {
+		case ch <- msg:
+		default:
+		}
+	}
+	m.Ack()
+}
+
+//remove 移除订阅者并关闭通道，无订阅者时关闭消费者
+func (h *hub) remove(ch chan string) {
+	hubLock.Lock()
+	defer hubLock.Unlock()
+	h.lock.Lock()
+	delete(h.subs, ch)
+	close(ch)
+	empty := len(h.subs) == 0
+	h.lock.Unlock()
+	if !empty {
+		return
+	}
+	delete(hubs, h.key)
+	h.consumer.UnConsume(h.name)
+	h.consumer.Close()
+}
@@ -0,0 +1,50 @@
+package queues
+
+import (
+	r "context"
+	"testing"
+	"time"
+
+	"github.com/micro-plat/hydra/components/queues/mq"
+	_ "github.com/micro-plat/hydra/components/queues/mq/lmq"
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func TestSubscribe_FanOut(t *testing.T) {
+	p, err := mq.NewMQP("lmq", "")
+	assert.Equal(t, nil, err, "创建生产者")
+
+	ctx1, cancel1 := r.WithCancel(r.Background())
+	ctx2, cancel2 := r.WithCancel(r.Background())
+	ch1, err := subscribe(ctx1, "lmq", "", "hub:test")
+	assert.Equal(t, nil, err, "订阅")
+	ch2, err := subscribe(ctx2, "lmq", "", "hub:test")
+	assert.Equal(t, nil, err, "订阅")
+	assert.Equal(t, 1, len(hubs), "共用消费者")
+
+	//每个订阅者都收到消息
+	assert.Equal(t, nil, p.Push("hub:test", "hello"), "发送消息")
+	for _, ch := range []<-chan string{ch1, ch2} {
+		select {
+		case msg := <-ch:
+			assert.Equal(t, "hello", msg, "收到消息")
+		case <-time.After(time.Second):
+			t.Fatal("未收到消息")
+		}
+	}
+
+	//全部退出后关闭消费者
+	cancel1()
+	cancel2()
+	for _, ch := range []<-chan string{ch1, ch2} {
+		select {
+		case _, ok := <-ch:
+			assert.Equal(t, false, ok, "通道关闭")
+		case <-time.After(time.Second):
+			t.Fatal("通道未关闭")
+		}
+	}
+	hubLock.Lock()
+	assert.Equal(t, 0, len(hubs), "关闭消费者")
+	hubLock.Unlock()
+}
@@ -3,6 +3,7 @@ package rpcs
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	"github.com/micro-plat/hydra/components/pkgs"
 	"github.com/micro-plat/hydra/components/rpcs/rpc"
@@ -10,6 +11,7 @@ import (
 	rc "github.com/micro-plat/hydra/context"
 	"github.com/micro-plat/hydra/global"
 	npkgs "github.com/micro-plat/hydra/pkgs"
+	"github.com/micro-plat/hydra/pkgs/codec"
 	"github.com/micro-plat/lib4go/concurrent/cmap"
 	"github.com/micro-plat/lib4go/types"
 )
@@ -27,6 +29,9 @@ type IRequest interface {
 
 	//RequestByCtx RPC请求，可通过context撤销请求
 	RequestByCtx(ctx context.Context, service string, input interface{}, opts ...rpc.RequestOption) (res *npkgs.Rspns, err error)
+
+	//Stream 发起流式请求，用于服务端流、客户端流与双向流的数据传输
+	Stream(ctx context.Context, service string, input interface{}, opts ...rpc.RequestOption) (*rpc.Stream, error)
 }
 
 //Request RPC Request
@@ -80,9 +85,60 @@ func (r *Request) Swap(service string, ctx rc.IContext) (res *npkgs.Rspns, err e
 
 //RequestByCtx RPC请求，可通过context撤销请求
 func (r *Request) RequestByCtx(ctx context.Context, service string, input interface{}, opts ...rpc.RequestOption) (res *npkgs.Rspns, err error) {
+	span := rc.StartSpan("rpc", "rpc.request")
+	span.SetTag("rpc.service", service)
+	defer func() {
+		if res != nil {
+			span.SetTag("status_code", strconv.Itoa(res.GetStatus()))
+			span.SetError(res.GetError())
+		}
+		span.SetError(err)
+		span.End()
+	}()
+
+	client, rservice, err := r.getClient(service)
+	if err != nil {
+		return nil, err
+	}
+	fm, err := encodeInput(input, rpc.GetContentType(opts...))
+	if err != nil {
+		return nil, err
+	}
+	return client.RequestByBytes(ctx, rservice, fm, getOptions(ctx, opts)...)
+}
+
+//Stream 发起流式请求，input作为首个消息的请求参数，ctx结束时关闭数据流
+func (r *Request) Stream(ctx context.Context, service string, input interface{}, opts ...rpc.RequestOption) (*rpc.Stream, error) {
+	client, rservice, err := r.getClient(service)
+	if err != nil {
+		return nil, err
+	}
+	fm := []byte("{}")
+	if input != nil {
+		if fm, err = encodeInput(input, rpc.GetContentType(opts...)); err != nil {
+			return nil, err
+		}
+	}
+	return client.Stream(ctx, rservice, fm, getOptions(ctx, opts)...)
+}
+
+//encodeInput 编码请求参数，[]byte直接发送，Content-Type为protobuf,msgpack等二进制格式时使用对应的编解码器编码，
+//其它类型转换为json
+func encodeInput(input interface{}, contentType string) ([]byte, error) {
+	if buff, ok := input.([]byte); ok {
+		return buff, nil
+	}
+	if c, ok := codec.Get(contentType); ok && codec.IsBinary(contentType) {
+		return c.Marshal(input)
+	}
+	return []byte(pkgs.GetString(input)), nil
+}
+
+//getClient 获取服务对应的rpc客户端
+func (r *Request) getClient(service string) (*rpc.Client, string, error) {
 	isip, rservice, platName, err := rpc.ResolvePath(service, global.Current().GetPlatName())
 	if err != nil {
-		return
+		return nil, "", err
 	}
 	//如果入参不是ip 通过注册中心去获取所请求平台的所有rpc服务子节点  再通过路由匹配获取真实的路由
 	_, c, err := requests.SetIfAbsentCb(fmt.Sprintf("%s@%s.%d", rservice, platName, r.version), func(i ...interface{}) (interface{}, error) {
@@ -93,21 +149,23 @@ func (r *Request) RequestByCtx(ctx context.Context, service string, input interf
 		return rpc.NewClientByConf(global.Def.RegistryAddr, platName, rservice, r.conf)
 	})
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
+	return c.(*rpc.Client), rservice, nil
+}
 
-	client := c.(*rpc.Client)
+//getOptions 添加链路跟踪编号
+func getOptions(ctx context.Context, opts []rpc.RequestOption) []rpc.RequestOption {
 	nopts := make([]rpc.RequestOption, 0, len(opts)+1)
 	nopts = append(nopts, opts...)
 	if reqid := types.GetString(ctx.Value(rc.XRequestID)); reqid != "" {
 		nopts = append(nopts, rpc.WithTraceID(reqid))
 	} else {
 		if ctx, ok := rc.GetContext(); ok {
-			nopts = append(opts, rpc.WithTraceID(ctx.User().GetTraceID()))
+			nopts = append(nopts, rpc.WithTraceID(ctx.User().GetTraceID()))
 		}
 	}
-	fm := pkgs.GetString(input)
-	return client.RequestByString(ctx, rservice, fm, nopts...)
+	return nopts
 }
 
 //Close 关闭RPC连接
@@ -0,0 +1,53 @@
+package pb
+
+import "unicode/utf8"
+
+//BinaryHeader 客户端支持通过body接收二进制内容时在请求头中设置的标识，
+//仅支持字符串的旧版本客户端不设置该标识，服务端不向其返回二进制内容，而是返回406错误
+const BinaryHeader = "X-Rpc-Binary"
+
+//AcceptBinary 请求头中是否声明支持接收二进制内容
+func AcceptBinary(header map[string]string) bool {
+	return header[BinaryHeader] == "true"
+}
+
+//IsBinary 内容是否需要通过body传输(非utf-8文本)
+func IsBinary(buff []byte) bool {
+	return !utf8.Valid(buff)
+}
+
+//SetPayload 设置请求参数，utf-8文本通过input传输以兼容仅支持字符串的服务，其它二进制内容通过body传输，
+//仅支持字符串的旧版本服务端不读取body，收到的请求参数为空，二进制请求需服务端同时升级
+func (m *RequestContext) SetPayload(buff []byte) {
+	if !IsBinary(buff) {
+		m.Input, m.Body = string(buff), nil
+		return
+	}
+	m.Input, m.Body = "", buff
+}
+
+//GetPayload 获取请求参数，body不为空时优先使用body
+func (m *RequestContext) GetPayload() []byte {
+	if len(m.GetBody()) > 0 {
+		return m.GetBody()
+	}
+	return []byte(m.GetInput())
+}
+
+//SetPayload 设置返回结果，utf-8文本通过result传输以兼容仅支持字符串的客户端，其它二进制内容通过body传输，
+//设置二进制内容前应通过AcceptBinary检查客户端是否支持
+func (m *ResponseContext) SetPayload(buff []byte) {
+	if !IsBinary(buff) {
+		m.Result, m.Body = string(buff), nil
+		return
+	}
+	m.Result, m.Body = "", buff
+}
+
+//GetPayload 获取返回结果，body不为空时优先使用body
+func (m *ResponseContext) GetPayload() []byte {
+	if len(m.GetBody()) > 0 {
+		return m.GetBody()
+	}
+	return []byte(m.GetResult())
+}
@@ -39,6 +39,7 @@ type RequestContext struct {
 	Method  string `protobuf:"bytes,2,opt,name=method" json:"method,omitempty"`
 	Header  string `protobuf:"bytes,3,opt,name=header" json:"header,omitempty"`
 	Input   string `protobuf:"bytes,4,opt,name=input" json:"input,omitempty"`
+	Body    []byte `protobuf:"bytes,5,opt,name=body,proto3" json:"body,omitempty"`
 }
 
 func (m *RequestContext) Reset()                    { *m = RequestContext{} }
@@ -74,10 +75,19 @@ func (m *RequestContext) GetInput() string {
 	return ""
 }
 
+func (m *RequestContext) GetBody() []byte {
+	if m != nil {
+		return m.Body
+	}
+	return nil
+}
+
 type ResponseContext struct {
 	Status int32  `protobuf:"varint,1,opt,name=status" json:"status,omitempty"`
 	Header string `protobuf:"bytes,2,opt,name=header" json:"header,omitempty"`
 	Result string `protobuf:"bytes,3,opt,name=result" json:"result,omitempty"`
+	Stream bool   `protobuf:"varint,4,opt,name=stream" json:"stream,omitempty"`
+	Body   []byte `protobuf:"bytes,5,opt,name=body,proto3" json:"body,omitempty"`
 }
 
 func (m *ResponseContext) Reset()                    { *m = ResponseContext{} }
@@ -106,6 +116,20 @@ func (m *ResponseContext) GetResult() string {
 	return ""
 }
 
+func (m *ResponseContext) GetStream() bool {
+	if m != nil {
+		return m.Stream
+	}
+	return false
+}
+
+func (m *ResponseContext) GetBody() []byte {
+	if m != nil {
+		return m.Body
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*RequestContext)(nil), "pb.RequestContext")
 	proto.RegisterType((*ResponseContext)(nil), "pb.ResponseContext")
@@ -123,6 +147,7 @@ const _ = grpc.SupportPackageIsVersion4
 
 type RPCClient interface {
 	Request(ctx context.Context, in *RequestContext, opts ...grpc.CallOption) (*ResponseContext, error)
+	Stream(ctx context.Context, opts ...grpc.CallOption) (RPC_StreamClient, error)
 }
 
 type rPCClient struct {
@@ -142,10 +167,42 @@ func (c *rPCClient) Request(ctx context.Context, in *RequestContext, opts ...grp
 	return out, nil
 }
 
+func (c *rPCClient) Stream(ctx context.Context, opts ...grpc.CallOption) (RPC_StreamClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_RPC_serviceDesc.Streams[0], c.cc, "/pb.RPC/Stream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &rPCStreamClient{stream}
+	return x, nil
+}
+
+type RPC_StreamClient interface {
+	Send(*RequestContext) error
+	Recv() (*ResponseContext, error)
+	grpc.ClientStream
+}
+
+type rPCStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *rPCStreamClient) Send(m *RequestContext) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *rPCStreamClient) Recv() (*ResponseContext, error) {
+	m := new(ResponseContext)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // Server API for RPC service
 
 type RPCServer interface {
 	Request(context.Context, *RequestContext) (*ResponseContext, error)
+	Stream(RPC_StreamServer) error
 }
 
 func RegisterRPCServer(s *grpc.Server, srv RPCServer) {
@@ -170,6 +227,32 @@ func _RPC_Request_Handler(srv interface{}, ctx context.Context, dec func(interfa
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RPC_Stream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(RPCServer).Stream(&rPCStreamServer{stream})
+}
+
+type RPC_StreamServer interface {
+	Send(*ResponseContext) error
+	Recv() (*RequestContext, error)
+	grpc.ServerStream
+}
+
+type rPCStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *rPCStreamServer) Send(m *ResponseContext) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *rPCStreamServer) Recv() (*RequestContext, error) {
+	m := new(RequestContext)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 var _RPC_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "pb.RPC",
 	HandlerType: (*RPCServer)(nil),
@@ -179,25 +262,34 @@ var _RPC_serviceDesc = grpc.ServiceDesc{
 			Handler:    _RPC_Request_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Stream",
+			Handler:       _RPC_Stream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
 	Metadata: "rpc.proto",
 }
 
 func init() { proto.RegisterFile("rpc.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 198 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x54, 0x90, 0xcd, 0x8a, 0xc2, 0x30,
-	0x14, 0x85, 0xa7, 0xed, 0x34, 0xa5, 0x77, 0x31, 0x03, 0x99, 0xa1, 0x04, 0x57, 0xd2, 0x95, 0xab,
-	0x2e, 0xd4, 0x9d, 0xcb, 0xbe, 0x80, 0x64, 0xe7, 0xb2, 0x3f, 0x17, 0x5a, 0xd0, 0x26, 0x26, 0x37,
-	0xe2, 0xe3, 0x8b, 0x49, 0x0b, 0x76, 0xf9, 0x7d, 0x81, 0x73, 0x72, 0x2e, 0xe4, 0x46, 0x77, 0x95,
-	0x36, 0x8a, 0x14, 0x8f, 0x75, 0x5b, 0x6a, 0xf8, 0x91, 0x78, 0x77, 0x68, 0xa9, 0x56, 0x13, 0xe1,
-	0x93, 0xb8, 0x80, 0xcc, 0xa2, 0x79, 0x8c, 0x1d, 0x8a, 0x68, 0x1b, 0xed, 0x72, 0xb9, 0x20, 0x2f,
-	0x80, 0xdd, 0x90, 0x06, 0xd5, 0x8b, 0xd8, 0x3f, 0xcc, 0xf4, 0xf6, 0x03, 0x36, 0x3d, 0x1a, 0x91,
-	0x04, 0x1f, 0x88, 0xff, 0x43, 0x3a, 0x4e, 0xda, 0x91, 0xf8, 0xf6, 0x3a, 0x40, 0x79, 0x81, 0x5f,
-	0x89, 0x56, 0xab, 0xc9, 0xe2, 0x52, 0x59, 0x00, 0xb3, 0xd4, 0x90, 0xb3, 0xbe, 0x31, 0x95, 0x33,
-	0x7d, 0x04, 0xc7, 0xab, 0xe0, 0x02, 0x98, 0x41, 0xeb, 0xae, 0xb4, 0x14, 0x06, 0xda, 0x9f, 0x20,
-	0x91, 0xe7, 0x9a, 0x1f, 0x21, 0x9b, 0x37, 0x71, 0x5e, 0xe9, 0xb6, 0x5a, 0x0f, 0xdc, 0xfc, 0x05,
-	0xb7, 0xfa, 0x42, 0xf9, 0xd5, 0x32, 0x7f, 0x94, 0xc3, 0x2b, 0x00, 0x00, 0xff, 0xff, 0xf6, 0xfe,
-	0x8f, 0x11, 0x21, 0x01, 0x00, 0x00,
+	// 237 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x91, 0xc1, 0x4a, 0xc4, 0x30,
+	0x10, 0x86, 0x4d, 0x77, 0x9b, 0x75, 0x07, 0x51, 0x18, 0xa5, 0x04, 0x4f, 0x4b, 0x4f, 0x3d, 0x15,
+	0x51, 0xc1, 0x07, 0xd8, 0x17, 0x90, 0xf8, 0x04, 0xed, 0x76, 0x60, 0x17, 0xdc, 0x26, 0x26, 0x13,
+	0xd1, 0xbb, 0xbe, 0xb7, 0x98, 0xa4, 0x60, 0xc1, 0xc3, 0xde, 0xf2, 0xfd, 0x21, 0x33, 0x1f, 0x7f,
+	0x60, 0xed, 0xec, 0xae, 0xb5, 0xce, 0xb0, 0xc1, 0xc2, 0xf6, 0xf5, 0x97, 0x80, 0x4b, 0x4d, 0x6f,
+	0x81, 0x3c, 0x6f, 0xcd, 0xc8, 0xf4, 0xc1, 0xa8, 0x60, 0xe5, 0xc9, 0xbd, 0x1f, 0x76, 0xa4, 0xc4,
+	0x46, 0x34, 0x6b, 0x3d, 0x21, 0x56, 0x20, 0x8f, 0xc4, 0x7b, 0x33, 0xa8, 0x22, 0x5e, 0x64, 0xfa,
+	0xcd, 0xf7, 0xd4, 0x0d, 0xe4, 0xd4, 0x22, 0xe5, 0x89, 0xf0, 0x06, 0xca, 0xc3, 0x68, 0x03, 0xab,
+	0x65, 0x8c, 0x13, 0x20, 0xc2, 0xb2, 0x37, 0xc3, 0xa7, 0x2a, 0x37, 0xa2, 0xb9, 0xd0, 0xf1, 0x5c,
+	0x7f, 0x0b, 0xb8, 0xd2, 0xe4, 0xad, 0x19, 0x3d, 0x4d, 0x1e, 0x15, 0x48, 0xcf, 0x1d, 0x07, 0x1f,
+	0x35, 0x4a, 0x9d, 0xe9, 0xcf, 0xb6, 0x62, 0xb6, 0xad, 0x02, 0xe9, 0xc8, 0x87, 0x57, 0x9e, 0x2c,
+	0x12, 0xa5, 0x39, 0x8e, 0xba, 0x63, 0xd4, 0x38, 0xd7, 0x99, 0xfe, 0xf3, 0xb8, 0x67, 0x58, 0xe8,
+	0xe7, 0x2d, 0x3e, 0xc2, 0x2a, 0x97, 0x82, 0xd8, 0xda, 0xbe, 0x9d, 0x37, 0x74, 0x7b, 0x9d, 0xb2,
+	0x99, 0x6e, 0x7d, 0x86, 0x4f, 0x20, 0x5f, 0xf2, 0xe8, 0x93, 0x1f, 0x35, 0xe2, 0x4e, 0xf4, 0x32,
+	0xfe, 0xc7, 0xc3, 0xcf, 0x00, 0x38, 0xc5, 0x37, 0xae, 0x9c, 0x01, 0x00, 0x00,
 }
@@ -7,20 +7,27 @@ import (
 	"google.golang.org/grpc"
 )
 
-func (c *Client) clientRequest(ctx context.Context, o *requestOption, form string) (response *pb.ResponseContext, err error) {
+func (c *Client) clientRequest(ctx context.Context, o *requestOption, form []byte) (response *pb.ResponseContext, err error) {
 
+	o.headers[pb.BinaryHeader] = "true"
 	h, err := o.getData(o.headers)
 	if err != nil {
 		return nil, err
 	}
+	request := &pb.RequestContext{
+		Method:  o.method,
+		Service: o.service,
+		Header:  string(h),
+	}
+	request.SetPayload(form)
+	return c.client.Request(ctx, request, grpc.FailFast(o.failFast))
 
-	return c.client.Request(ctx,
-		&pb.RequestContext{
-			Method:  o.method,
-			Service: o.service,
-			Header:  string(h),
-			Input:   form,
-		},
-		grpc.FailFast(o.failFast))
+}
 
+//getResult 获取响应结果，二进制内容返回[]byte，文本内容返回字符串
+func getResult(response *pb.ResponseContext) interface{} {
+	if len(response.GetBody()) > 0 {
+		return response.GetBody()
+	}
+	return response.GetResult()
 }
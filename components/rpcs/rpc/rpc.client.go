@@ -1,8 +1,11 @@
 package rpc
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"time"
 
 	"github.com/micro-plat/hydra/pkgs"
@@ -15,6 +18,7 @@ import (
 
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 //Client rpc client, 用于构建基础的RPC调用,并提供基于服务器的限流工具，轮询、本地优先等多种负载算法
@@ -71,6 +75,12 @@ func (c *Client) Request(ctx context.Context, service string, form map[string]in
 
 //RequestByString 发送Request请求
 func (c *Client) RequestByString(ctx context.Context, service string, form string, opts ...RequestOption) (res *pkgs.Rspns, err error) {
+	return c.RequestByBytes(ctx, service, []byte(form), opts...)
+}
+
+//RequestByBytes 发送Request请求，请求参数为已编码的内容(如protobuf,msgpack或二进制文件)，
+//非utf-8内容通过body传输
+func (c *Client) RequestByBytes(ctx context.Context, service string, form []byte, opts ...RequestOption) (res *pkgs.Rspns, err error) {
 	//处理可选参数
 	o := newOption()
 	for _, opt := range opts {
@@ -81,7 +91,7 @@ func (c *Client) RequestByString(ctx context.Context, service string, form strin
 	if err != nil {
 		return pkgs.NewRspns(err), err
 	}
-	return pkgs.NewRspnsByHD(int(response.Status), response.GetHeader(), response.GetResult()), err
+	return pkgs.NewRspnsByHD(int(response.Status), response.GetHeader(), getResult(response)), err
 }
 
 //Close 关闭RPC客户端连接
@@ -112,10 +122,15 @@ func (c *Client) connect() (err error) {
 		return
 	}
 
+	security, err := c.getTransportSecurity()
+	if err != nil {
+		return
+	}
+
 	ctx, _ := context.WithTimeout(context.Background(), time.Duration(c.ConntTimeout)*time.Second)
 	c.conn, err = grpc.DialContext(ctx,
 		c.address+"/rpcsrv",
-		grpc.WithInsecure(),
+		security,
 		grpc.WithBalancerName(c.Balancer),
 		grpc.WithResolvers(c.balancerBuilder))
 
@@ -125,3 +140,29 @@ func (c *Client) connect() (err error) {
 	c.client = pb.NewRPCClient(c.conn)
 	return nil
 }
+
+//getTransportSecurity 设置了客户端证书或ca证书时使用tls连接
+func (c *Client) getTransportSecurity() (grpc.DialOption, error) {
+	if !c.IsTLS() {
+		return grpc.WithInsecure(), nil
+	}
+	cfg := &tls.Config{ServerName: c.ServerName}
+	if len(c.Tls) == 2 {
+		cert, err := tls.LoadX509KeyPair(c.Tls[0], c.Tls[1])
+		if err != nil {
+			return nil, fmt.Errorf("cert证书(pem:%s,key:%s),加载失败:%v", c.Tls[0], c.Tls[1], err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	if c.Ca != "" {
+		caData, err := ioutil.ReadFile(c.Ca)
+		if err != nil {
+			return nil, fmt.Errorf("ca证书(%s)读取错误:%v", c.Ca, err)
+		}
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(caData) {
+			return nil, fmt.Errorf("ca证书(%s)格式有误", c.Ca)
+		}
+	}
+	return grpc.WithTransportCredentials(credentials.NewTLS(cfg)), nil
+}
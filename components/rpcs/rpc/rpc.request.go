@@ -108,6 +108,15 @@ func WithContentType(m string) RequestOption {
 	}
 }
 
+//GetContentType 获取请求选项中设置的Content-Type
+func GetContentType(opts ...RequestOption) string {
+	o := newOption()
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o.headers["Content-Type"]
+}
+
 //WithOperationName 设置请求延迟时长
 func WithOperationName(name string) RequestOption {
 	return func(o *requestOption) {
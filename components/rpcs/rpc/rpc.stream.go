@@ -0,0 +1,112 @@
+package rpc
+
+import (
+	"io"
+
+	"github.com/micro-plat/hydra/components/rpcs/rpc/pb"
+	"github.com/micro-plat/hydra/pkgs"
+	"github.com/micro-plat/hydra/pkgs/codec"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+//Stream rpc流式请求，可用于服务端流、客户端流与双向流的数据传输
+type Stream struct {
+	stream      pb.RPC_StreamClient
+	contentType string
+	response    *pkgs.Rspns
+}
+
+//Stream 发起流式请求，form作为首个消息的请求参数，ctx结束时关闭数据流
+func (c *Client) Stream(ctx context.Context, service string, form []byte, opts ...RequestOption) (*Stream, error) {
+	o := newOption()
+	for _, opt := range opts {
+		opt(o)
+	}
+	o.service = service
+	o.headers[pb.BinaryHeader] = "true"
+	h, err := o.getData(o.headers)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := c.client.Stream(ctx, grpc.FailFast(o.failFast))
+	if err != nil {
+		return nil, err
+	}
+	request := &pb.RequestContext{
+		Method:  o.method,
+		Service: o.service,
+		Header:  string(h),
+	}
+	request.SetPayload(form)
+	if err = stream.Send(request); err != nil {
+		return nil, err
+	}
+	return &Stream{stream: stream, contentType: o.headers["Content-Type"]}, nil
+}
+
+//Send 向服务端发送消息，字符串与[]byte直接发送，其它类型使用请求Content-Type对应的编解码器编码，
+//未注册编解码器时转换为json发送
+func (s *Stream) Send(input interface{}) error {
+	var content []byte
+	switch v := input.(type) {
+	case string:
+		content = []byte(v)
+	case []byte:
+		content = v
+	default:
+		c, ok := codec.Get(s.contentType)
+		if !ok {
+			c, _ = codec.Get("application/json")
+		}
+		buff, err := c.Marshal(v)
+		if err != nil {
+			return err
+		}
+		content = buff
+	}
+	request := &pb.RequestContext{}
+	request.SetPayload(content)
+	return s.stream.Send(request)
+}
+
+//CloseSend 通知服务端消息已发送完成
+func (s *Stream) CloseSend() error {
+	return s.stream.CloseSend()
+}
+
+//Recv 接收服务端发送的下一条消息，服务执行完成时返回io.EOF，并可通过Response获取服务的最终响应
+func (s *Stream) Recv() (string, error) {
+	if s.response != nil {
+		return "", io.EOF
+	}
+	m, err := s.stream.Recv()
+	if err != nil {
+		return "", err
+	}
+	if m.GetStream() {
+		return string(m.GetPayload()), nil
+	}
+	s.response = pkgs.NewRspnsByHD(int(m.GetStatus()), m.GetHeader(), getResult(m))
+	return "", io.EOF
+}
+
+//CloseAndRecv 通知服务端消息已发送完成，并等待服务的最终响应，期间服务端发送的消息将被忽略
+func (s *Stream) CloseAndRecv() (*pkgs.Rspns, error) {
+	if err := s.CloseSend(); err != nil {
+		return nil, err
+	}
+	for {
+		if _, err := s.Recv(); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	return s.Response(), nil
+}
+
+//Response 获取服务的最终响应，Recv返回io.EOF前为nil
+func (s *Stream) Response() *pkgs.Rspns {
+	return s.response
+}
@@ -0,0 +1,112 @@
+package rpc
+
+import (
+	"io"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/micro-plat/hydra/components/rpcs/rpc/pb"
+	"github.com/micro-plat/lib4go/assert"
+	"google.golang.org/grpc"
+)
+
+type streamClientStub struct {
+	grpc.ClientStream
+	sent   []*pb.RequestContext
+	recv   []*pb.ResponseContext
+	closed bool
+}
+
+func (s *streamClientStub) Send(m *pb.RequestContext) error {
+	s.sent = append(s.sent, m)
+	return nil
+}
+
+func (s *streamClientStub) Recv() (*pb.ResponseContext, error) {
+	if len(s.recv) == 0 {
+		return nil, io.EOF
+	}
+	m := s.recv[0]
+	s.recv = s.recv[1:]
+	return m, nil
+}
+
+func (s *streamClientStub) CloseSend() error {
+	s.closed = true
+	return nil
+}
+
+func TestStream_Recv(t *testing.T) {
+	stub := &streamClientStub{recv: []*pb.ResponseContext{
+		{Status: 200, Result: "a", Stream: true},
+		{Status: 200, Result: "b", Stream: true},
+		{Status: 200, Header: `{"Content-Type":"text/plain"}`, Result: "done"},
+	}}
+	s := &Stream{stream: stub}
+	assert.Equal(t, nil, s.Send(map[string]interface{}{"id": 1}), "发送消息")
+	assert.Equal(t, `{"id":1}`, stub.sent[0].Input, "非字符串转换为json")
+
+	v, err := s.Recv()
+	assert.Equal(t, nil, err, "接收消息")
+	assert.Equal(t, "a", v, "第一条消息")
+	assert.Equal(t, true, s.Response() == nil, "未结束")
+	v, _ = s.Recv()
+	assert.Equal(t, "b", v, "第二条消息")
+	_, err = s.Recv()
+	assert.Equal(t, io.EOF, err, "服务执行完成")
+	assert.Equal(t, "done", s.Response().GetResult(), "最终响应")
+	_, err = s.Recv()
+	assert.Equal(t, io.EOF, err, "完成后继续接收")
+}
+
+func TestStream_CloseAndRecv(t *testing.T) {
+	stub := &streamClientStub{recv: []*pb.ResponseContext{
+		{Status: 200, Result: "a", Stream: true},
+		{Status: 200, Result: `{"count":2}`},
+	}}
+	s := &Stream{stream: stub}
+	s.Send("1")
+	s.Send([]byte("2"))
+	res, err := s.CloseAndRecv()
+	assert.Equal(t, nil, err, "等待最终响应")
+	assert.Equal(t, true, stub.closed, "已通知发送完成")
+	assert.Equal(t, 200, res.GetStatus(), "状态码")
+	assert.Equal(t, "2", stub.sent[1].Input, "[]byte直接发送")
+}
+
+func TestResponseContext_Stream(t *testing.T) {
+	buff, err := proto.Marshal(&pb.ResponseContext{Status: 200, Result: "a", Stream: true})
+	assert.Equal(t, nil, err, "序列化")
+	m := &pb.ResponseContext{}
+	assert.Equal(t, nil, proto.Unmarshal(buff, m), "反序列化")
+	assert.Equal(t, true, m.GetStream(), "流式消息标记")
+	assert.Equal(t, "a", m.GetResult(), "消息内容")
+}
+
+func TestStream_Binary(t *testing.T) {
+	stub := &streamClientStub{recv: []*pb.ResponseContext{
+		{Status: 200, Body: []byte{0x89, 0x50}, Stream: true},
+		{Status: 200, Header: `{"Content-Type":"application/octet-stream"}`, Body: []byte{0xff, 0x00}},
+	}}
+	s := &Stream{stream: stub, contentType: "application/x-msgpack"}
+	s.Send([]byte{0xff, 0xfe})
+	s.Send("text")
+	s.Send(map[string]interface{}{"id": 1})
+	assert.Equal(t, "", stub.sent[0].Input, "二进制内容不通过input发送")
+	assert.Equal(t, []byte{0xff, 0xfe}, stub.sent[0].Body, "二进制内容通过body发送")
+	assert.Equal(t, "text", stub.sent[1].Input, "文本通过input发送")
+	assert.Equal(t, []byte{0x81, 0xa2, 0x69, 0x64, 0x01}, stub.sent[2].GetPayload(), "使用msgpack编码")
+
+	v, _ := s.Recv()
+	assert.Equal(t, string([]byte{0x89, 0x50}), v, "接收二进制消息")
+	_, err := s.Recv()
+	assert.Equal(t, io.EOF, err, "服务执行完成")
+	assert.Equal(t, []byte{0xff, 0x00}, s.Response().GetBytes(), "二进制最终响应")
+}
+
+func TestAcceptBinary(t *testing.T) {
+	assert.Equal(t, false, pb.AcceptBinary(map[string]string{}), "旧版本客户端未声明支持二进制内容")
+	assert.Equal(t, true, pb.AcceptBinary(map[string]string{pb.BinaryHeader: "true"}), "声明支持二进制内容")
+	assert.Equal(t, true, pb.IsBinary([]byte{0xff, 0x00}), "非utf-8内容")
+	assert.Equal(t, false, pb.IsBinary([]byte("中文")), "utf-8文本")
+}
@@ -13,14 +13,20 @@ import (
 	"github.com/micro-plat/hydra/conf/server/auth/apikey"
 	"github.com/micro-plat/hydra/conf/server/auth/basic"
 	"github.com/micro-plat/hydra/conf/server/auth/jwt"
+	"github.com/micro-plat/hydra/conf/server/auth/oidc"
 	"github.com/micro-plat/hydra/conf/server/auth/ras"
+	"github.com/micro-plat/hydra/conf/server/auth/rbac"
+	"github.com/micro-plat/hydra/conf/server/compress"
 	"github.com/micro-plat/hydra/conf/server/header"
+	"github.com/micro-plat/hydra/conf/server/idempotent"
 	"github.com/micro-plat/hydra/conf/server/metric"
 	"github.com/micro-plat/hydra/conf/server/mqc"
 	"github.com/micro-plat/hydra/conf/server/queue"
 	"github.com/micro-plat/hydra/conf/server/render"
 	"github.com/micro-plat/hydra/conf/server/static"
 	"github.com/micro-plat/hydra/conf/server/task"
+	tlsconf "github.com/micro-plat/hydra/conf/server/tls"
+	"github.com/micro-plat/hydra/conf/server/ws"
 	"github.com/micro-plat/hydra/conf/vars"
 	"github.com/micro-plat/hydra/conf/vars/rlog"
 	"github.com/micro-plat/hydra/global"
@@ -50,6 +56,12 @@ type IAPPConf interface {
 	GetLimiterConf() (*limiter.Limiter, error)
 	GetProxyConf() (*proxy.Proxy, error)
 	GetAPMConf() (*apm.APM, error)
+	GetOIDCConf() (*oidc.OIDC, error)
+	GetRBACConf() (*rbac.RBAC, error)
+	GetWSConnConf() (*ws.Conn, error)
+	GetTLSConf() (*tlsconf.TLS, error)
+	GetCompressConf() (*compress.Compress, error)
+	GetIdempotentConf() (*idempotent.Idempotent, error)
 	//获取远程日志配置
 	GetRLogConf() (*rlog.Layout, error)
 	Close() error
@@ -0,0 +1,18 @@
+package app
+
+import (
+	"github.com/micro-plat/hydra/conf"
+	tlsconf "github.com/micro-plat/hydra/conf/server/tls"
+)
+
+//TLSGetter 从缓存中获取服务器最新的tls配置，用于证书动态加载
+func TLSGetter(serverType string) tlsconf.Getter {
+	return func() (*tlsconf.TLS, conf.IVarConf, error) {
+		c, err := Cache.GetAPPConf(serverType)
+		if err != nil {
+			return nil, nil, err
+		}
+		t, err := c.GetTLSConf()
+		return t, c.GetVarConf(), err
+	}
+}
@@ -0,0 +1,70 @@
+package conf
+
+import (
+	"net"
+	"strings"
+)
+
+//IPMatch IP地址匹配，支持单个IP(v4/v6)、CIDR网段(192.168.0.0/16,2001:db8::/32)与*通配(192.168.*.*,192.168.**)
+type IPMatch struct {
+	ips      map[string]bool
+	prefixes map[int]map[string]bool
+	lens     []int
+	pattern  *PathMatch
+}
+
+//NewIPMatch 构建IP地址匹配器
+func NewIPMatch(all ...string) *IPMatch {
+	m := &IPMatch{
+		ips:      make(map[string]bool),
+		prefixes: make(map[int]map[string]bool),
+	}
+	patterns := make([]string, 0, 1)
+	for _, v := range all {
+		v = strings.TrimSpace(v)
+		if ip := net.ParseIP(v); ip != nil {
+			m.ips[ip.String()] = true
+			continue
+		}
+		if _, network, err := net.ParseCIDR(v); err == nil {
+			ones, bits := network.Mask.Size()
+
+			//IPv4使用96+ones的IPv6前缀长度，统一按16字节匹配
+			if bits == 32 {
+				ones += 96
+			}
+			if _, ok := m.prefixes[ones]; !ok {
+				m.prefixes[ones] = make(map[string]bool)
+				m.lens = append(m.lens, ones)
+			}
+			m.prefixes[ones][string(network.IP.To16().Mask(net.CIDRMask(ones, 128)))] = true
+			continue
+		}
+		patterns = append(patterns, v)
+	}
+	m.pattern = NewPathMatch(patterns...)
+	return m
+}
+
+//Match 检查IP是否匹配，按前缀长度分组查找，查找次数与配置的网段数量无关
+func (m *IPMatch) Match(v string) bool {
+	ip := net.ParseIP(strings.TrimSpace(v))
+	if ip == nil {
+		ok, _ := m.pattern.Match(v, ".")
+		return ok
+	}
+	if m.ips[ip.String()] {
+		return true
+	}
+	ip16 := ip.To16()
+	for _, ones := range m.lens {
+		if m.prefixes[ones][string(ip16.Mask(net.CIDRMask(ones, 128)))] {
+			return true
+		}
+	}
+	if ip.To4() == nil {
+		return false
+	}
+	ok, _ := m.pattern.Match(ip.String(), ".")
+	return ok
+}
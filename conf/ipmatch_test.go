@@ -0,0 +1,30 @@
+package conf
+
+import (
+	"testing"
+
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func TestIPMatch(t *testing.T) {
+	m := NewIPMatch("192.168.1.10", "10.0.0.0/8", "172.16.**", "2001:db8::/32", "::1")
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"192.168.1.10", true},
+		{"192.168.1.11", false},
+		{"10.20.30.40", true},
+		{"11.0.0.1", false},
+		{"172.16.5.6", true},
+		{"172.17.5.6", false},
+		{"2001:db8:1::5", true},
+		{"2001:db9::5", false},
+		{"0:0:0:0:0:0:0:1", true},
+		{"::ffff:10.1.1.1", true},
+		{"", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, m.Match(tt.ip), tt.ip)
+	}
+}
@@ -19,8 +19,28 @@ const (
 //BlackList 黑名单配置
 type BlackList struct {
 	Disable bool     `json:"disable,omitempty" toml:"disable,omitempty"`
-	IPS     []string `json:"blackList,omitempty" toml:"blackList,omitempty"`
-	ipm     *conf.PathMatch
+	IPS     []string `json:"blackList,omitempty" toml:"blackList,omitempty"` //支持IP、CIDR网段与*通配
+
+	//Cache 动态黑名单使用的缓存名称，为空时不启用动态黑名单
+	Cache string `json:"cache,omitempty" valid:"ascii" toml:"cache,omitempty" label:"动态黑名单缓存"`
+
+	//BanTTL 自动加入动态黑名单的时长(秒)
+	BanTTL int `json:"banTTL,omitempty" toml:"banTTL,omitempty"`
+
+	//Window 统计失败次数的时间窗口(秒)
+	Window int `json:"window,omitempty" toml:"window,omitempty"`
+
+	//AuthFailures 时间窗口内认证失败(401,403)次数达到该值时自动加入黑名单，0为不检查
+	AuthFailures int `json:"authFailures,omitempty" toml:"authFailures,omitempty"`
+
+	//RateLimits 时间窗口内被限流次数达到该值时自动加入黑名单，0为不检查
+	RateLimits int `json:"rateLimits,omitempty" toml:"rateLimits,omitempty"`
+
+	//Requests 统计失败次数的请求路径，未设置时统计所有请求
+	Requests []string `json:"requests,omitempty" toml:"requests,omitempty"`
+
+	ipm *conf.IPMatch
+	rqm *conf.PathMatch
 }
 
 //New 黑名单配置
@@ -30,14 +50,14 @@ func New(opts ...Option) *BlackList {
 		opt(f)
 
 	}
-	f.ipm = conf.NewPathMatch(f.IPS...)
+	f.ipm = conf.NewIPMatch(f.IPS...)
+	f.rqm = conf.NewPathMatch(f.Requests...)
 	return f
 }
 
 //IsDeny 验证当前请求是否在黑名单中
 func (w *BlackList) IsDeny(ip string) bool {
-	ok, _ := w.ipm.Match(ip, ".")
-	return ok
+	return w.ipm.Match(ip)
 }
 
 //GetConf 获取BlackList
@@ -55,6 +75,7 @@ func GetConf(cnf conf.IServerConf) (*BlackList, error) {
 		return nil, fmt.Errorf("black list配置数据有误:%v", err)
 	}
 
-	ip.ipm = conf.NewPathMatch(ip.IPS...)
+	ip.ipm = conf.NewIPMatch(ip.IPS...)
+	ip.rqm = conf.NewPathMatch(ip.Requests...)
 	return &ip, nil
 }
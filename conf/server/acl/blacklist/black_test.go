@@ -0,0 +1,68 @@
+package blacklist
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/micro-plat/lib4go/assert"
+	"github.com/micro-plat/lib4go/types"
+)
+
+type memStore map[string]string
+
+func (m memStore) Add(key string, value string, expiresAt int) error {
+	if _, ok := m[key]; ok {
+		return fmt.Errorf("key:%s已存在", key)
+	}
+	m[key] = value
+	return nil
+}
+func (m memStore) Set(key string, value string, expiresAt int) error {
+	m[key] = value
+	return nil
+}
+func (m memStore) Increment(key string, delta int64) (int64, error) {
+	n := types.GetInt64(m[key]) + delta
+	m[key] = types.GetString(n)
+	return n, nil
+}
+func (m memStore) Exists(key string) bool {
+	_, ok := m[key]
+	return ok
+}
+func (m memStore) Delete(key string) error {
+	delete(m, key)
+	return nil
+}
+
+func TestBlackList_IsDeny(t *testing.T) {
+	b := New(WithIP("192.168.0.0/16", "2001:db8::/32", "10.1.1.1"))
+	assert.Equal(t, true, b.IsDeny("192.168.3.4"), "CIDR网段")
+	assert.Equal(t, true, b.IsDeny("2001:db8::1"), "IPv6网段")
+	assert.Equal(t, true, b.IsDeny("10.1.1.1"), "单个IP")
+	assert.Equal(t, false, b.IsDeny("10.1.1.2"), "不在黑名单")
+}
+
+func TestBlackList_Record(t *testing.T) {
+	store := memStore{}
+	b := New(WithCache("cache"), WithAutoBan(60, 3, 0, 600), WithRequests("/login"))
+
+	banned, err := b.Record("1.1.1.1", "/order", FailureAuth, store)
+	assert.Equal(t, nil, err, "不统计的路径")
+	assert.Equal(t, false, banned, "不统计的路径")
+	for i := 0; i < 2; i++ {
+		banned, _ = b.Record("1.1.1.1", "/login", FailureAuth, store)
+		assert.Equal(t, false, banned, "未达到阈值")
+	}
+	banned, _ = b.Record("1.1.1.1", "/login", FailureLimit, store)
+	assert.Equal(t, false, banned, "未启用限流检查")
+	banned, err = b.Record("1.1.1.1", "/login", FailureAuth, store)
+	assert.Equal(t, nil, err, "达到阈值")
+	assert.Equal(t, true, banned, "达到阈值")
+	assert.Equal(t, true, b.IsBanned("1.1.1.1", store), "已加入动态黑名单")
+	assert.Equal(t, false, b.IsBanned("1.1.1.2", store), "其它IP不受影响")
+
+	assert.Equal(t, nil, b.Unban("1.1.1.1", store), "移出黑名单")
+	assert.Equal(t, false, b.IsBanned("1.1.1.1", store), "已移出黑名单")
+	assert.NotEqual(t, nil, b.Ban("abc", 0, store), "IP格式有误")
+}
@@ -0,0 +1,106 @@
+package blacklist
+
+import (
+	"fmt"
+	"net"
+)
+
+//defBanTTL 默认加入动态黑名单的时长(秒)
+const defBanTTL = 600
+
+//defWindow 默认统计失败次数的时间窗口(秒)
+const defWindow = 60
+
+const (
+	//FailureAuth 认证失败
+	FailureAuth = "auth"
+	//FailureLimit 被限流
+	FailureLimit = "limit"
+)
+
+//IDenyStore 动态黑名单存储，一般使用缓存组件，集群共享
+type IDenyStore interface {
+	Add(key string, value string, expiresAt int) error
+	Set(key string, value string, expiresAt int) error
+	Increment(key string, delta int64) (int64, error)
+	Exists(key string) bool
+	Delete(key string) error
+}
+
+//IsBanned 检查IP是否在动态黑名单中
+func (w *BlackList) IsBanned(ip string, store IDenyStore) bool {
+	return store != nil && store.Exists(w.denyKey(ip))
+}
+
+//Ban 将IP加入动态黑名单，ttl为0时使用配置的时长
+func (w *BlackList) Ban(ip string, ttl int, store IDenyStore) error {
+	if net.ParseIP(ip) == nil {
+		return fmt.Errorf("ip地址(%s)格式有误", ip)
+	}
+	if ttl <= 0 {
+		ttl = w.getBanTTL()
+	}
+	if err := store.Set(w.denyKey(ip), "1", ttl); err != nil {
+		return fmt.Errorf("ip(%s)加入黑名单失败:%w", ip, err)
+	}
+	return nil
+}
+
+//Unban 将IP移出动态黑名单
+func (w *BlackList) Unban(ip string, store IDenyStore) error {
+	if err := store.Delete(w.denyKey(ip)); err != nil {
+		return fmt.Errorf("ip(%s)移出黑名单失败:%w", ip, err)
+	}
+	return nil
+}
+
+//Record 记录请求失败，时间窗口内失败次数达到阈值时自动加入动态黑名单并返回true
+func (w *BlackList) Record(ip string, path string, kind string, store IDenyStore) (bool, error) {
+	threshold := w.AuthFailures
+	if kind == FailureLimit {
+		threshold = w.RateLimits
+	}
+	if threshold <= 0 || store == nil || !w.IsWatched(path) {
+		return false, nil
+	}
+
+	//首次失败时创建计数并设置时间窗口，已存在时忽略
+	key := fmt.Sprintf("hydra:acl:failures:%s:%s", kind, ip)
+	store.Add(key, "0", w.getWindow())
+	n, err := store.Increment(key, 1)
+	if err != nil {
+		return false, fmt.Errorf("记录ip(%s)失败次数出错:%w", ip, err)
+	}
+	if n < int64(threshold) {
+		return false, nil
+	}
+	store.Delete(key)
+	return true, w.Ban(ip, 0, store)
+}
+
+//IsWatched 检查请求路径是否需要统计失败次数
+func (w *BlackList) IsWatched(path string) bool {
+	if len(w.Requests) == 0 {
+		return true
+	}
+	ok, _ := w.rqm.Match(path)
+	return ok
+}
+
+func (w *BlackList) denyKey(ip string) string {
+	return "hydra:acl:deny:" + ip
+}
+
+func (w *BlackList) getBanTTL() int {
+	if w.BanTTL <= 0 {
+		return defBanTTL
+	}
+	return w.BanTTL
+}
+
+func (w *BlackList) getWindow() int {
+	if w.Window <= 0 {
+		return defWindow
+	}
+	return w.Window
+}
@@ -23,3 +23,27 @@ func WithEnable() Option {
 		a.Disable = false
 	}
 }
+
+//WithCache 启用动态黑名单，name为保存黑名单的缓存名称
+func WithCache(name string) Option {
+	return func(a *BlackList) {
+		a.Cache = name
+	}
+}
+
+//WithAutoBan 时间窗口(秒)内认证失败或被限流次数达到阈值时自动加入黑名单ttl秒，阈值为0时不检查
+func WithAutoBan(window int, authFailures int, rateLimits int, ttl int) Option {
+	return func(a *BlackList) {
+		a.Window = window
+		a.AuthFailures = authFailures
+		a.RateLimits = rateLimits
+		a.BanTTL = ttl
+	}
+}
+
+//WithRequests 设置统计失败次数的请求路径
+func WithRequests(p ...string) Option {
+	return func(a *BlackList) {
+		a.Requests = p
+	}
+}
@@ -9,7 +9,7 @@ type Option func(*WhiteList)
 func WithIPList(list ...*IPList) Option {
 	return func(a *WhiteList) {
 		for _, ip := range list {
-			ip.ipm = conf.NewPathMatch(ip.IPS...)
+			ip.ipm = conf.NewIPMatch(ip.IPS...)
 			ip.rqm = conf.NewPathMatch(ip.Requests...)
 			a.WhiteList = append(a.WhiteList, ip)
 		}
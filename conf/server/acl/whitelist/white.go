@@ -19,8 +19,8 @@ const (
 //IPList ip列表
 type IPList struct {
 	Requests []string `json:"requests,omitempty" valid:"ascii,required" toml:"requests,omitempty" label:"白名单请求路径列表"`
-	IPS      []string `json:"ips,omitempty" valid:"ascii,required" toml:"ips,omitempty" label:"白名单请求ip列表"`
-	ipm      *conf.PathMatch
+	IPS      []string `json:"ips,omitempty" valid:"ascii,required" toml:"ips,omitempty" label:"白名单请求ip列表"` //支持IP、CIDR网段与*通配
+	ipm      *conf.IPMatch
 	rqm      *conf.PathMatch
 }
 
@@ -43,8 +43,7 @@ func New(opts ...Option) *WhiteList {
 func (w *WhiteList) IsAllow(path string, ip string) bool {
 	for _, cur := range w.WhiteList {
 		if ok, _ := cur.rqm.Match(path); ok {
-			ok, _ := cur.ipm.Match(ip, ".")
-			return ok
+			return cur.ipm.Match(ip)
 		}
 	}
 	return true
@@ -62,7 +61,7 @@ func GetConf(cnf conf.IServerConf) (*WhiteList, error) {
 	}
 
 	for _, i := range ip.WhiteList {
-		i.ipm = conf.NewPathMatch(i.IPS...)
+		i.ipm = conf.NewIPMatch(i.IPS...)
 		i.rqm = conf.NewPathMatch(i.Requests...)
 		if b, err := govalidator.ValidateStruct(i); !b {
 			return nil, fmt.Errorf("white list配置数据有误:%v", err)
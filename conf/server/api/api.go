@@ -8,6 +8,7 @@ import (
 
 	"github.com/asaskevich/govalidator"
 	"github.com/micro-plat/hydra/conf"
+	"github.com/micro-plat/hydra/conf/server/router"
 )
 
 const (
@@ -30,6 +31,9 @@ const (
 	//DefaultRHTimeOut 默认头读取超时时间
 	DefaultRHTimeOut = 30
 
+	//DefaultMaxHeaderBytes 默认请求头最大字节数
+	DefaultMaxHeaderBytes = 1 << 20
+
 	//StartStatus 开启服务
 	StartStatus = "start"
 
@@ -38,7 +42,7 @@ const (
 )
 
 //MainConfName 主配置中的关键配置名
-var MainConfName = []string{"address", "status", "rTimeout", "wTimeout", "rhTimeout", "dn"}
+var MainConfName = []string{"address", "status", "rTimeout", "wTimeout", "rhTimeout", "dn", "maxHeaderBytes"}
 
 //SubConfName 子配置中的关键配置名
 var SubConfName = []string{"router", "metric"}
@@ -54,6 +58,12 @@ type Server struct {
 	Domain    string `json:"dns,omitempty" valid:"dns" toml:"dns,omitempty" label:"域名"`
 	Name      string `json:"name,omitempty" toml:"name,omitempty" label:"服务器名称"`
 	Trace     bool   `json:"trace,omitempty" toml:"trace,omitempty"`
+
+	//MaxHeaderBytes 请求头最大字节数，超过时返回431
+	MaxHeaderBytes int `json:"maxHeaderBytes,omitempty" valid:"range(0|67108864)" toml:"maxHeaderBytes,omitempty"`
+
+	//Limits 全局的请求大小限制，可在路由中单独设置
+	router.Limits
 }
 
 //New 构建api server配置信息
@@ -119,6 +129,14 @@ func (s *Server) GetRHTimeout() int {
 	return s.RHTimeout
 }
 
+//GetMaxHeaderBytes 获取请求头最大字节数
+func (s *Server) GetMaxHeaderBytes() int {
+	if s.MaxHeaderBytes <= 0 {
+		return DefaultMaxHeaderBytes
+	}
+	return s.MaxHeaderBytes
+}
+
 //GetConf 获取主配置信息
 func GetConf(cnf conf.IServerConf) (s *Server, err error) {
 	if _, ok := validTypes[cnf.GetServerType()]; !ok {
@@ -33,6 +33,28 @@ func WithHeaderReadTimeout(htimeout int) Option {
 	}
 }
 
+//WithMaxHeaderBytes 设置请求头最大字节数
+func WithMaxHeaderBytes(size int) Option {
+	return func(a *Server) {
+		a.MaxHeaderBytes = size
+	}
+}
+
+//WithMaxBodySize 设置请求body最大字节数，超过时返回413
+func WithMaxBodySize(size int64) Option {
+	return func(a *Server) {
+		a.MaxBodySize = size
+	}
+}
+
+//WithMaxFiles 设置上传文件个数与单个文件的最大字节数，超过时返回413
+func WithMaxFiles(count int, size int64) Option {
+	return func(a *Server) {
+		a.MaxFiles = count
+		a.MaxFileSize = size
+	}
+}
+
 //WithDisable 禁用任务
 func WithDisable() Option {
 	return func(a *Server) {
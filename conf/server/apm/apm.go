@@ -3,6 +3,7 @@ package apm
 import (
 	"errors"
 	"fmt"
+	"math/rand"
 
 	"github.com/asaskevich/govalidator"
 	"github.com/micro-plat/hydra/conf"
@@ -20,6 +21,9 @@ type APM struct {
 	Address string `json:"address,omitempty" valid:"required" toml:"address,omitempty" label:"应用程序性能监控地址"`
 	Version int32  `json:"-"`
 	Disable bool   `json:"disable,omitempty" toml:"disable,omitempty"`
+
+	//Sampling 采样率(0-1]，未设置时全部采样
+	Sampling float64 `json:"sampling,omitempty" toml:"sampling,omitempty" label:"采样率"`
 }
 
 //New 构建api server配置信息
@@ -51,5 +55,22 @@ func GetConf(cnf conf.IServerConf) (apm *APM, err error) {
 	if b, err := govalidator.ValidateStruct(apm); !b {
 		return nil, fmt.Errorf("apm配置数据有误:%v", err)
 	}
+	if apm.Sampling < 0 || apm.Sampling > 1 {
+		return nil, fmt.Errorf("apm配置数据有误:采样率必须在0-1之间:%v", apm.Sampling)
+	}
 	return
 }
+
+//GetSampling 获取采样率，未设置时返回1
+func (a *APM) GetSampling() float64 {
+	if a.Sampling <= 0 {
+		return 1
+	}
+	return a.Sampling
+}
+
+//IsSampled 按采样率检查当前请求是否需要跟踪
+func (a *APM) IsSampled() bool {
+	rate := a.GetSampling()
+	return rate >= 1 || rand.Float64() < rate
+}
@@ -0,0 +1,23 @@
+package apm
+
+import (
+	"testing"
+
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func TestAPM_IsSampled(t *testing.T) {
+	c := New("192.168.0.1:11800")
+	assert.Equal(t, float64(1), c.GetSampling(), "未设置采样率")
+	assert.Equal(t, true, c.IsSampled(), "全部采样")
+
+	c = New("192.168.0.1:11800", WithSampling(0.2))
+	assert.Equal(t, 0.2, c.GetSampling(), "采样率")
+	n := 0
+	for i := 0; i < 10000; i++ {
+		if c.IsSampled() {
+			n++
+		}
+	}
+	assert.Equal(t, true, n > 1500 && n < 2500, "按采样率采样")
+}
@@ -16,3 +16,10 @@ func WithEnable() Option {
 		a.Disable = false
 	}
 }
+
+//WithSampling 设置采样率(0-1]
+func WithSampling(rate float64) Option {
+	return func(a *APM) {
+		a.Sampling = rate
+	}
+}
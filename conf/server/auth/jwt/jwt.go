@@ -10,8 +10,6 @@ import (
 	"github.com/asaskevich/govalidator"
 	"github.com/micro-plat/hydra/conf"
 	"github.com/micro-plat/hydra/registry"
-	"github.com/micro-plat/lib4go/errs"
-	"github.com/micro-plat/lib4go/security/jwt"
 	"github.com/micro-plat/lib4go/types"
 	"github.com/micro-plat/lib4go/utility"
 )
@@ -73,13 +71,24 @@ type JWTAuth struct {
 	Name            string   `json:"name,omitempty" valid:"ascii,required" toml:"name,omitempty" label:"jwt名称"`
 	ExpireAt        int64    `json:"expireAt,omitzero" valid:"required" toml:"expireAt,omitzero" label:"jwt过期时间"`
 	Mode            string   `json:"mode,omitempty" valid:"in(HS256|HS384|HS512|RS256|ES256|ES384|ES512|RS384|RS512|PS256|PS384|PS512),required" toml:"mode,omitempty" label:"jwt认证方式"`
-	Secret          string   `json:"secret,omitempty" valid:"ascii,required" toml:"secret,omitempty"  label:"jwt密钥"`
+	Secret          string   `json:"secret,omitempty" valid:"ascii" toml:"secret,omitempty"  label:"jwt密钥"`
 	Source          string   `json:"source,omitempty" valid:"in(header|cookie|HEADER|COOKIE|H)" toml:"source,omitempty" label:"jwt存储方式"`
 	Excludes        []string `json:"excludes,omitempty" toml:"exclude,omitempty"`
 	Domain          string   `json:"domain,omitempty" toml:"domain,omitempty"`
 	AuthURL         string   `json:"authURL,omitempty" valid:"ascii" toml:"authURL,omitempty" label:"jwt认证跳转地址"`
 	Disable         bool     `json:"disable,omitempty" toml:"disable,omitempty"`
+	Keys            []*Key   `json:"keys,omitempty" toml:"keys,omitempty"`
+	Kid             string   `json:"kid,omitempty" valid:"ascii" toml:"kid,omitempty" label:"签发jwt使用的密钥编号"`
+	JWKSURL         string   `json:"jwksURL,omitempty" valid:"url" toml:"jwksURL,omitempty" label:"jwt公钥集地址"`
+	JWKSRefresh     int      `json:"jwksRefresh,omitempty" toml:"jwksRefresh,omitempty" label:"jwt公钥集刷新周期"`
+	Issuer          string   `json:"issuer,omitempty" toml:"issuer,omitempty" label:"jwt签发者"`
+	Audience        string   `json:"audience,omitempty" toml:"audience,omitempty" label:"jwt接收者"`
+	RefreshExpireAt int64    `json:"refreshExpireAt,omitzero" toml:"refreshExpireAt,omitzero" label:"刷新令牌过期时间"`
+	RefreshName     string   `json:"refreshName,omitempty" valid:"ascii" toml:"refreshName,omitempty" label:"刷新令牌名称"`
+	Cache           string   `json:"cache,omitempty" valid:"ascii" toml:"cache,omitempty" label:"jwt吊销信息缓存"`
+	UserKey         string   `json:"userKey,omitempty" valid:"ascii" toml:"userKey,omitempty" label:"用户标识字段"`
 	*conf.PathMatch `json:"-"`
+	store           *keyStore
 }
 
 //NewJWT 构建JWT配置参数
@@ -98,50 +107,64 @@ func NewJWT(opts ...Option) *JWTAuth {
 	return jwt
 }
 
-//CheckJWT 检查jwt合法性
+//CheckJWT 检查jwt合法性，不检查是否被吊销
 func (j *JWTAuth) CheckJWT(token string) (data interface{}, err error) {
-	if token == "" {
-		return nil, errs.NewError(JWTStatusTokenError, fmt.Errorf("未传入jwt.token(%s %s值为空)", j.Source, j.Name))
-	}
-	if !strings.HasPrefix(token, TokenBearerPrefix) {
-		return nil, errs.NewError(JWTStatusTokenError, fmt.Errorf("jwt.token格式错误(%s)", token))
+	claims, err := j.Verify(token, nil)
+	if err != nil {
+		return nil, err
 	}
-	token = token[len(TokenBearerPrefix):]
+	return GetData(claims), nil
+}
 
-	//2. 解密jwt判断是否有效，是否过期
-	data, er := jwt.Decrypt(token, j.Secret)
-	if er != nil {
-		if strings.Contains(er.Error(), "Token is expired") {
-			return nil, errs.NewError(JWTStatusTokenExpired, er)
+//Sign 使用当前密钥签发jwt
+func (j *JWTAuth) Sign(data interface{}) (string, error) {
+	access, _, err := j.Issue(data, nil)
+	return access, err
+}
+
+//verifyAudience 检查aud声明(字符串或数组)中是否包含指定的接收者
+func verifyAudience(aud interface{}, cmp string) bool {
+	switch v := aud.(type) {
+	case string:
+		return v == cmp
+	case []interface{}:
+		for _, a := range v {
+			if s, ok := a.(string); ok && s == cmp {
+				return true
+			}
 		}
-		return data, errs.NewError(JWTStatusTokenError, fmt.Errorf("jwt.token值(%s)有误 %w", token, er))
 	}
-
-	return data, nil
+	return false
 }
 
 //GetJWTForRspns 获取jwt响应参数值
 func (j *JWTAuth) GetJWTForRspns(token string, expired ...bool) (string, string, bool) {
 
-	isExpired := types.GetBoolByIndex(expired, 0, false)
+	return j.getRspns(j.Name, token, j.ExpireAt, types.GetBoolByIndex(expired, 0, false))
+}
+
+func (j *JWTAuth) getRspns(name string, token string, expireAt int64, isExpired bool) (string, string, bool) {
 	token = TokenBearerPrefix + token
 	switch strings.ToUpper(j.Source) {
 	case SourceHeader, SourceHeaderShort: //"HEADER", "H":
-		return AuthorizationHeader, token, isExpired == false
+		if name == j.Name {
+			name = AuthorizationHeader
+		}
+		return name, token, isExpired == false
 	default:
-		expireVal := j.getExpireTime(isExpired)
+		expireVal := getExpireTime(expireAt, isExpired)
 		if j.Domain != "" {
-			return "Set-Cookie", fmt.Sprintf("%s=%s;domain=%s;path=/;expires=%s;HttpOnly", j.Name, token, j.Domain, expireVal), true
+			return "Set-Cookie", fmt.Sprintf("%s=%s;domain=%s;path=/;expires=%s;HttpOnly", name, token, j.Domain, expireVal), true
 		}
-		return "Set-Cookie", fmt.Sprintf("%s=%s;path=/;expires=%s;HttpOnly", j.Name, token, expireVal), true
+		return "Set-Cookie", fmt.Sprintf("%s=%s;path=/;expires=%s;HttpOnly", name, token, expireVal), true
 	}
 }
 
 //getExpireTime 获取jwt的超时时间
-func (j *JWTAuth) getExpireTime(expired bool) string {
+func getExpireTime(expireAt int64, expired bool) string {
 	expireTime := time.Now().Add(time.Hour * -24)
 	if !expired {
-		expireTime = time.Now().Add(time.Duration(time.Duration(j.ExpireAt)*time.Second - 8*60*60*time.Second))
+		expireTime = time.Now().Add(time.Duration(time.Duration(expireAt)*time.Second - 8*60*60*time.Second))
 	}
 	return expireTime.Format("Mon, 02 Jan 2006 15:04:05 GMT")
 }
@@ -159,6 +182,12 @@ func GetConf(cnf conf.IServerConf) (*JWTAuth, error) {
 	if b, err := govalidator.ValidateStruct(&jwt); !b {
 		return nil, fmt.Errorf("jwt配置数据有误:%v", err)
 	}
+	if jwt.Secret == "" && len(jwt.Keys) == 0 && jwt.JWKSURL == "" {
+		return nil, fmt.Errorf("jwt配置数据有误:未指定secret,keys或jwksURL")
+	}
+	if jwt.RefreshExpireAt > 0 && jwt.RefreshExpireAt <= jwt.ExpireAt {
+		return nil, fmt.Errorf("jwt配置数据有误:刷新令牌过期时间(%d)应大于jwt过期时间(%d)", jwt.RefreshExpireAt, jwt.ExpireAt)
+	}
 	jwt.PathMatch = conf.NewPathMatch(jwt.Excludes...)
 
 	return &jwt, nil
@@ -0,0 +1,261 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/micro-plat/lib4go/assert"
+	"github.com/micro-plat/lib4go/errs"
+	jwt "github.com/zkfy/jwt-go"
+)
+
+func newRSAKey(t *testing.T) (*rsa.PrivateKey, string) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Equal(t, nil, err, "生成RSA密钥")
+	buff := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(priv)})
+	return priv, string(buff)
+}
+
+func TestJWTAuth_Rotation(t *testing.T) {
+	old := NewJWT(WithSecret(""), WithKey("k1", ModeHS256, "secret1"), WithActiveKey("k1"))
+	token, err := old.Sign(map[string]interface{}{"uid": "1"})
+	assert.Equal(t, nil, err, "使用k1签发")
+
+	rotated := NewJWT(WithSecret(""), WithKey("k1", ModeHS256, "secret1"), WithKey("k2", ModeHS512, "secret2"), WithActiveKey("k2"))
+	data, err := rotated.CheckJWT(TokenBearerPrefix + token)
+	assert.Equal(t, nil, err, "轮换后旧密钥签发的jwt仍有效")
+	assert.Equal(t, "1", data.(map[string]interface{})["uid"], "轮换后旧密钥签发的jwt仍有效")
+
+	token, err = rotated.Sign("2")
+	assert.Equal(t, nil, err, "使用k2签发")
+	_, err = old.CheckJWT(TokenBearerPrefix + token)
+	assert.NotEqual(t, nil, err, "未配置k2时验证失败")
+}
+
+func TestJWTAuth_RSA(t *testing.T) {
+	_, privPEM := newRSAKey(t)
+	j := NewJWT(WithMode(ModeRS256), WithSecret(privPEM), WithIssuer("hydra"), WithAudience("api"))
+	token, err := j.Sign("data")
+	assert.Equal(t, nil, err, "使用RSA私钥签发")
+	data, err := j.CheckJWT(TokenBearerPrefix + token)
+	assert.Equal(t, nil, err, "使用RSA公钥验证")
+	assert.Equal(t, "data", data, "使用RSA公钥验证")
+
+	other := NewJWT(WithMode(ModeRS256), WithSecret(privPEM), WithIssuer("other"))
+	_, err = other.CheckJWT(TokenBearerPrefix + token)
+	assert.NotEqual(t, nil, err, "签发者不一致")
+
+	hs := NewJWT(WithMode(ModeHS256), WithSecret(privPEM))
+	_, err = hs.CheckJWT(TokenBearerPrefix + token)
+	assert.NotEqual(t, nil, err, "签名算法不一致")
+}
+
+func TestJWTAuth_JWKS(t *testing.T) {
+	priv, _ := newRSAKey(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+			"kty": "RSA", "kid": "idp1", "alg": ModeRS256, "use": "sig",
+			"n": base64.RawURLEncoding.EncodeToString(priv.N.Bytes()),
+			"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(priv.E)).Bytes()),
+		}}})
+	}))
+	defer server.Close()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"sub": "u1", "aud": []string{"api", "web"}})
+	token.Header["kid"] = "idp1"
+	raw, err := token.SignedString(priv)
+	assert.Equal(t, nil, err, "模拟签发者签发")
+
+	j := NewJWT(WithSecret(""), WithJWKS(server.URL), WithAudience("api"))
+	data, err := j.CheckJWT(TokenBearerPrefix + raw)
+	assert.Equal(t, nil, err, "使用JWKS公钥验证")
+	assert.Equal(t, "u1", data.(map[string]interface{})["sub"], "返回所有声明")
+
+	j = NewJWT(WithSecret(""), WithJWKS(server.URL), WithAudience("admin"))
+	_, err = j.CheckJWT(TokenBearerPrefix + raw)
+	assert.NotEqual(t, nil, err, "接收者不一致")
+}
+
+func TestJWTAuth_JWKSRefresh(t *testing.T) {
+	priv, _ := newRSAKey(t)
+	var count int32
+	block := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&count, 1) > 1 {
+			<-block
+		}
+		time.Sleep(20 * time.Millisecond)
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+			"kty": "RSA", "kid": "idp1", "alg": ModeRS256,
+			"n": base64.RawURLEncoding.EncodeToString(priv.N.Bytes()),
+			"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(priv.E)).Bytes()),
+		}}})
+	}))
+	defer server.Close()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"sub": "u1"})
+	token.Header["kid"] = "idp1"
+	raw, _ := token.SignedString(priv)
+
+	//并发的首次验证共用同一次拉取
+	j := NewJWT(WithSecret(""), WithJWKS(server.URL))
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := j.CheckJWT(TokenBearerPrefix + raw)
+			assert.Equal(t, nil, err, "使用JWKS公钥验证")
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&count), "只拉取一次JWKS")
+
+	//过期后在后台刷新，刷新期间继续使用缓存的公钥
+	store, _ := j.getStore()
+	store.lock.Lock()
+	store.fetched = time.Now().Add(-2 * time.Hour)
+	store.lock.Unlock()
+	_, err := j.CheckJWT(TokenBearerPrefix + raw)
+	assert.Equal(t, nil, err, "刷新期间使用缓存的公钥")
+	_, err = j.CheckJWT(TokenBearerPrefix + raw)
+	assert.Equal(t, nil, err, "刷新期间使用缓存的公钥")
+	close(block)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&count), "后台只刷新一次")
+}
+
+type memStore map[string]string
+
+func (m memStore) Get(key string) (string, error) {
+	return m[key], nil
+}
+func (m memStore) Set(key string, value string, expiresAt int) error {
+	m[key] = value
+	return nil
+}
+func (m memStore) Add(key string, value string, expiresAt int) error {
+	if _, ok := m[key]; ok {
+		return fmt.Errorf("key:%s已存在", key)
+	}
+	m[key] = value
+	return nil
+}
+func (m memStore) Exists(key string) bool {
+	_, ok := m[key]
+	return ok
+}
+
+func TestJWTAuth_Refresh(t *testing.T) {
+	store := memStore{}
+	j := NewJWT(WithExpireAt(60), WithRefresh(3600), WithRevokeCache("cache"))
+	access, refresh, err := j.Issue(map[string]interface{}{"uid": "u1"}, store)
+	assert.Equal(t, nil, err, "签发令牌")
+	assert.NotEqual(t, "", refresh, "签发刷新令牌")
+
+	_, err = j.Verify(TokenBearerPrefix+refresh, store)
+	assert.NotEqual(t, nil, err, "刷新令牌不能用于访问")
+	_, err = j.Refresh(TokenBearerPrefix+access, store)
+	assert.NotEqual(t, nil, err, "访问令牌不能用于刷新")
+
+	claims, err := j.Refresh(TokenBearerPrefix+refresh, store)
+	assert.Equal(t, nil, err, "使用刷新令牌")
+	assert.Equal(t, "u1", GetData(claims).(map[string]interface{})["uid"], "使用刷新令牌")
+	_, err = j.Refresh(TokenBearerPrefix+refresh, store)
+	assert.NotEqual(t, nil, err, "刷新令牌只能使用一次")
+}
+
+type syncStore struct {
+	sync.Mutex
+	memStore
+}
+
+func (m *syncStore) Add(key string, value string, expiresAt int) error {
+	m.Lock()
+	defer m.Unlock()
+	return m.memStore.Add(key, value, expiresAt)
+}
+
+func (m *syncStore) Exists(key string) bool {
+	m.Lock()
+	defer m.Unlock()
+	return m.memStore.Exists(key)
+}
+
+func TestJWTAuth_RefreshOnce(t *testing.T) {
+	store := &syncStore{memStore: memStore{}}
+	j := NewJWT(WithExpireAt(60), WithRefresh(3600))
+	_, refresh, err := j.Issue("u1", nil)
+	assert.Equal(t, nil, err, "签发令牌")
+
+	//并发使用同一个刷新令牌只有一个成功
+	var wg sync.WaitGroup
+	var success int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := j.Refresh(TokenBearerPrefix+refresh, store); err == nil {
+				atomic.AddInt32(&success, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), success, "刷新令牌只能使用一次")
+}
+
+func TestIsExpired(t *testing.T) {
+	j := NewJWT(WithExpireAt(-10))
+	access, _, err := j.Issue("u1", nil)
+	assert.Equal(t, nil, err, "签发令牌")
+	_, err = j.Verify(TokenBearerPrefix+access, nil)
+	assert.Equal(t, true, IsExpired(err), "令牌已过期")
+	assert.Equal(t, JWTStatusTokenExpired, errs.GetCode(err), "令牌已过期")
+
+	_, err = j.Verify(TokenBearerPrefix+access+"x", nil)
+	assert.Equal(t, false, IsExpired(err), "签名错误")
+}
+
+func TestJWTAuth_Revoke(t *testing.T) {
+	store := memStore{}
+	j := NewJWT(WithExpireAt(60), WithRefresh(3600), WithUserKey("id"))
+	access, refresh, err := j.Issue(map[string]interface{}{"id": 1}, store)
+	assert.Equal(t, nil, err, "签发令牌")
+	claims, err := j.Verify(TokenBearerPrefix+access, store)
+	assert.Equal(t, nil, err, "验证令牌")
+	assert.Equal(t, "1", claims["sub"], "用户标识")
+
+	assert.Equal(t, nil, j.Revoke(claims, store), "吊销令牌")
+	_, err = j.Verify(TokenBearerPrefix+access, store)
+	assert.NotEqual(t, nil, err, "令牌已吊销")
+	_, err = j.Refresh(TokenBearerPrefix+refresh, store)
+	assert.NotEqual(t, nil, err, "关联的刷新令牌已吊销")
+	_, err = j.Verify(TokenBearerPrefix+access, nil)
+	assert.Equal(t, nil, err, "未配置存储时不检查吊销")
+
+	a1, r1, _ := j.Issue(map[string]interface{}{"id": 1}, store)
+	a2, _, _ := j.Issue(map[string]interface{}{"id": 2}, store)
+	assert.Equal(t, nil, j.RevokeUser("1", store), "吊销用户所有令牌")
+	_, err = j.Verify(TokenBearerPrefix+a1, store)
+	assert.NotEqual(t, nil, err, "用户令牌已吊销")
+	_, err = j.Refresh(TokenBearerPrefix+r1, store)
+	assert.NotEqual(t, nil, err, "用户刷新令牌已吊销")
+	_, err = j.Verify(TokenBearerPrefix+a2, store)
+	assert.Equal(t, nil, err, "其它用户不受影响")
+
+	a3, _, _ := j.Issue(map[string]interface{}{"id": 1}, store)
+	_, err = j.Verify(TokenBearerPrefix+a3, store)
+	assert.Equal(t, nil, err, "吊销后重新登录")
+}
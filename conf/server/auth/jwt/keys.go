@@ -0,0 +1,350 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	jwt "github.com/zkfy/jwt-go"
+)
+
+//defJWKSRefresh JWKS默认刷新周期(秒)
+const defJWKSRefresh = 3600
+
+//minJWKSRefresh 未找到kid时重新拉取JWKS的最小间隔
+const minJWKSRefresh = time.Minute
+
+//Key jwt密钥，通过kid区分，用于密钥轮换
+type Key struct {
+
+	//Kid 密钥编号，签发时写入jwt头的kid中
+	Kid string `json:"kid" valid:"ascii,required" toml:"kid" label:"jwt密钥编号"`
+
+	//Mode 加密模式，未指定时使用jwt配置的加密模式
+	Mode string `json:"mode,omitempty" valid:"in(HS256|HS384|HS512|RS256|ES256|ES384|ES512|RS384|RS512|PS256|PS384|PS512)" toml:"mode,omitempty" label:"jwt密钥加密模式"`
+
+	//Secret HS模式为密钥，其它模式为PEM格式的私钥或公钥，只有公钥时不能用于签发
+	Secret string `json:"secret,omitempty" toml:"secret,omitempty"`
+
+	//File PEM格式的私钥或公钥文件
+	File string `json:"file,omitempty" toml:"file,omitempty"`
+}
+
+//signKey 签名或验证使用的密钥
+type signKey struct {
+	kid    string
+	mode   string
+	sign   interface{}
+	verify interface{}
+}
+
+//keyStore 密钥集合，包括配置的密钥与从JWKS地址拉取的公钥
+type keyStore struct {
+	auth    *JWTAuth
+	lock    sync.Mutex
+	keys    map[string]*signKey
+	remote  map[string]*signKey
+	fetched time.Time
+	loading chan struct{}
+	lastErr error
+	err     error
+}
+
+var storeLock sync.Mutex
+
+//getStore 获取jwt配置对应的密钥集合，首次使用时加载
+func (j *JWTAuth) getStore() (*keyStore, error) {
+	storeLock.Lock()
+	defer storeLock.Unlock()
+	if j.store != nil {
+		return j.store, j.store.err
+	}
+	j.store = &keyStore{auth: j, keys: make(map[string]*signKey)}
+	j.store.err = j.store.load()
+	return j.store, j.store.err
+}
+
+func (s *keyStore) load() error {
+	if s.auth.Secret != "" {
+		key, err := parseKey("", s.auth.Mode, []byte(s.auth.Secret))
+		if err != nil {
+			return err
+		}
+		s.keys[""] = key
+	}
+	for _, k := range s.auth.Keys {
+		material := []byte(k.Secret)
+		if k.File != "" {
+			buff, err := ioutil.ReadFile(k.File)
+			if err != nil {
+				return fmt.Errorf("读取jwt密钥文件%s失败:%w", k.File, err)
+			}
+			material = buff
+		}
+		if len(material) == 0 {
+			return fmt.Errorf("jwt密钥(kid:%s)未指定secret或file", k.Kid)
+		}
+		mode := k.Mode
+		if mode == "" {
+			mode = s.auth.Mode
+		}
+		key, err := parseKey(k.Kid, mode, material)
+		if err != nil {
+			return err
+		}
+		s.keys[k.Kid] = key
+	}
+	if _, ok := s.keys[s.auth.Kid]; !ok && s.auth.Kid != "" {
+		return fmt.Errorf("未找到签发jwt使用的密钥:%s", s.auth.Kid)
+	}
+	return nil
+}
+
+//signer 获取签发jwt使用的密钥
+func (s *keyStore) signer() (*signKey, error) {
+	key, ok := s.keys[s.auth.Kid]
+	if !ok || key.sign == nil {
+		return nil, fmt.Errorf("未配置签发jwt使用的私钥或密钥(kid:%s)", s.auth.Kid)
+	}
+	return key, nil
+}
+
+//keyFunc 根据jwt头中的kid与alg获取验证密钥
+func (s *keyStore) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, err := s.find(kid)
+	if err != nil {
+		return nil, err
+	}
+	if key.mode != token.Method.Alg() {
+		return nil, fmt.Errorf("jwt签名算法%s与密钥(kid:%s)的算法%s不一致", token.Method.Alg(), kid, key.mode)
+	}
+	return key.verify, nil
+}
+
+func (s *keyStore) find(kid string) (*signKey, error) {
+	if key, ok := s.keys[kid]; ok {
+		return key, nil
+	}
+	if kid == "" {
+		if key, ok := s.keys[s.auth.Kid]; ok {
+			return key, nil
+		}
+	}
+	if s.auth.JWKSURL == "" {
+		return nil, fmt.Errorf("未找到jwt验证密钥(kid:%s)", kid)
+	}
+
+	s.lock.Lock()
+	key := s.lookup(kid)
+	since := time.Since(s.fetched)
+	if key != nil {
+
+		//缓存的公钥过期时在后台刷新，刷新期间继续使用缓存的公钥
+		if since > s.period() {
+			s.refresh()
+		}
+		s.lock.Unlock()
+		return key, nil
+	}
+	if !s.fetched.IsZero() && since <= minJWKSRefresh && s.loading == nil {
+		s.lock.Unlock()
+		return nil, fmt.Errorf("未找到jwt验证密钥(kid:%s)", kid)
+	}
+
+	//首次使用或未找到kid时等待拉取完成，并发请求共用同一次拉取
+	wait := s.refresh()
+	s.lock.Unlock()
+	<-wait
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if key := s.lookup(kid); key != nil {
+		return key, nil
+	}
+	if s.remote == nil && s.lastErr != nil {
+		return nil, s.lastErr
+	}
+	return nil, fmt.Errorf("未找到jwt验证密钥(kid:%s)", kid)
+}
+
+//period JWKS刷新周期
+func (s *keyStore) period() time.Duration {
+	if s.auth.JWKSRefresh <= 0 {
+		return defJWKSRefresh * time.Second
+	}
+	return time.Duration(s.auth.JWKSRefresh) * time.Second
+}
+
+//refresh 在后台拉取JWKS，已在拉取时返回当前拉取的完成通知，调用时需持有s.lock
+func (s *keyStore) refresh() <-chan struct{} {
+	if s.loading != nil {
+		return s.loading
+	}
+	done := make(chan struct{})
+	s.loading = done
+	go func() {
+		remote, err := fetchJWKS(s.auth.JWKSURL)
+		s.lock.Lock()
+		s.fetched = time.Now()
+		s.lastErr = err
+		if err == nil {
+			s.remote = remote
+		}
+		s.loading = nil
+		s.lock.Unlock()
+		close(done)
+	}()
+	return done
+}
+
+func (s *keyStore) lookup(kid string) *signKey {
+	if key, ok := s.remote[kid]; ok {
+		return key
+	}
+	if kid == "" && len(s.remote) == 1 {
+		for _, key := range s.remote {
+			return key
+		}
+	}
+	return nil
+}
+
+//parseKey 根据加密模式解析密钥，HS模式使用原始密钥，其它模式解析PEM格式的私钥或公钥
+func parseKey(kid string, mode string, material []byte) (*signKey, error) {
+	key := &signKey{kid: kid, mode: mode}
+	switch {
+	case strings.HasPrefix(mode, "HS"):
+		key.sign, key.verify = material, material
+		return key, nil
+	case strings.HasPrefix(mode, "RS"), strings.HasPrefix(mode, "PS"):
+		if priv, err := jwt.ParseRSAPrivateKeyFromPEM(material); err == nil {
+			key.sign, key.verify = priv, &priv.PublicKey
+			return key, nil
+		}
+		pub, err := jwt.ParseRSAPublicKeyFromPEM(material)
+		if err != nil {
+			return nil, fmt.Errorf("jwt密钥(kid:%s)不是有效的RSA私钥或公钥:%w", kid, err)
+		}
+		key.verify = pub
+		return key, nil
+	case strings.HasPrefix(mode, "ES"):
+		if priv, err := jwt.ParseECPrivateKeyFromPEM(material); err == nil {
+			key.sign, key.verify = priv, &priv.PublicKey
+			return key, nil
+		}
+		pub, err := jwt.ParseECPublicKeyFromPEM(material)
+		if err != nil {
+			return nil, fmt.Errorf("jwt密钥(kid:%s)不是有效的EC私钥或公钥:%w", kid, err)
+		}
+		key.verify = pub
+		return key, nil
+	default:
+		return nil, fmt.Errorf("不支持的jwt加密模式:%s", mode)
+	}
+}
+
+//jwk JWKS中的公钥
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+var jwksClient = &http.Client{Timeout: 10 * time.Second}
+
+//fetchJWKS 拉取JWKS地址中用于签名验证的公钥
+func fetchJWKS(url string) (map[string]*signKey, error) {
+	resp, err := jwksClient.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("获取JWKS失败:%w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("获取JWKS失败:%s", resp.Status)
+	}
+	set := struct {
+		Keys []*jwk `json:"keys"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("JWKS格式有误:%w", err)
+	}
+	return parseJWKS(set.Keys)
+}
+
+func parseJWKS(list []*jwk) (map[string]*signKey, error) {
+	keys := make(map[string]*signKey, len(list))
+	for _, k := range list {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.parse()
+		if err != nil {
+			return nil, err
+		}
+		if key != nil {
+			keys[k.Kid] = key
+		}
+	}
+	return keys, nil
+}
+
+//parse 解析RSA或EC公钥，不支持的类型返回nil
+func (k *jwk) parse() (*signKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("JWKS公钥(kid:%s)的n有误:%w", k.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("JWKS公钥(kid:%s)的e有误:%w", k.Kid, err)
+		}
+		pub := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		return &signKey{kid: k.Kid, mode: getAlg(k.Alg, ModeRS256), verify: pub}, nil
+	case "EC":
+		curves := map[string]struct {
+			curve elliptic.Curve
+			mode  string
+		}{"P-256": {elliptic.P256(), ModeES256}, "P-384": {elliptic.P384(), ModeES384}, "P-521": {elliptic.P521(), ModeES512}}
+		c, ok := curves[k.Crv]
+		if !ok {
+			return nil, fmt.Errorf("JWKS公钥(kid:%s)的曲线%s不支持", k.Kid, k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("JWKS公钥(kid:%s)的x有误:%w", k.Kid, err)
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("JWKS公钥(kid:%s)的y有误:%w", k.Kid, err)
+		}
+		pub := &ecdsa.PublicKey{Curve: c.curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		return &signKey{kid: k.Kid, mode: getAlg(k.Alg, c.mode), verify: pub}, nil
+	default:
+		return nil, nil
+	}
+}
+
+func getAlg(alg string, def string) string {
+	if alg == "" {
+		return def
+	}
+	return alg
+}
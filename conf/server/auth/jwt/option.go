@@ -89,3 +89,72 @@ func WithDomain(domain string) Option {
 		a.Domain = domain
 	}
 }
+
+//WithKey 添加jwt密钥，HS模式为密钥，其它模式为PEM格式的私钥或公钥
+func WithKey(kid string, mode string, secret string) Option {
+	return func(a *JWTAuth) {
+		a.Keys = append(a.Keys, &Key{Kid: kid, Mode: mode, Secret: secret})
+	}
+}
+
+//WithKeyFile 添加PEM格式的私钥或公钥文件
+func WithKeyFile(kid string, mode string, file string) Option {
+	return func(a *JWTAuth) {
+		a.Keys = append(a.Keys, &Key{Kid: kid, Mode: mode, File: file})
+	}
+}
+
+//WithActiveKey 设置签发jwt使用的密钥编号
+func WithActiveKey(kid string) Option {
+	return func(a *JWTAuth) {
+		a.Kid = kid
+	}
+}
+
+//WithJWKS 设置用于验证jwt的公钥集地址，refresh为刷新周期(秒)
+func WithJWKS(url string, refresh ...int) Option {
+	return func(a *JWTAuth) {
+		a.JWKSURL = url
+		if len(refresh) > 0 {
+			a.JWKSRefresh = refresh[0]
+		}
+	}
+}
+
+//WithIssuer 设置签发者，签发时写入iss，验证时检查iss
+func WithIssuer(issuer string) Option {
+	return func(a *JWTAuth) {
+		a.Issuer = issuer
+	}
+}
+
+//WithAudience 设置接收者，签发时写入aud，验证时检查aud
+func WithAudience(audience string) Option {
+	return func(a *JWTAuth) {
+		a.Audience = audience
+	}
+}
+
+//WithRefresh 启用刷新令牌，expireAt为刷新令牌过期时间(秒)，name为刷新令牌的cookie或header名称
+func WithRefresh(expireAt int64, name ...string) Option {
+	return func(a *JWTAuth) {
+		a.RefreshExpireAt = expireAt
+		if len(name) > 0 {
+			a.RefreshName = name[0]
+		}
+	}
+}
+
+//WithRevokeCache 设置保存jwt吊销信息的缓存名称，设置后启用吊销检查
+func WithRevokeCache(name string) Option {
+	return func(a *JWTAuth) {
+		a.Cache = name
+	}
+}
+
+//WithUserKey 设置用户数据中用户标识的字段名，用于按用户吊销jwt
+func WithUserKey(key string) Option {
+	return func(a *JWTAuth) {
+		a.UserKey = key
+	}
+}
@@ -0,0 +1,353 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/micro-plat/lib4go/errs"
+	"github.com/micro-plat/lib4go/types"
+	"github.com/micro-plat/lib4go/utility"
+	jwt "github.com/zkfy/jwt-go"
+)
+
+//RefreshName 刷新令牌默认的cookie或header名称
+const RefreshName = "Authorization-Refresh"
+
+//TokenTypeRefresh 刷新令牌类型，只能用于换取新的访问令牌
+const TokenTypeRefresh = "refresh"
+
+//IRevokeStore jwt吊销信息存储，一般使用缓存组件
+type IRevokeStore interface {
+	Get(key string) (string, error)
+	Set(key string, value string, expiresAt int) error
+	Add(key string, value string, expiresAt int) error
+	Exists(key string) bool
+}
+
+//expiredError jwt已过期，保留错误码
+type expiredError struct {
+	err *errs.Error
+}
+
+func (e *expiredError) Error() string {
+	return e.err.Error()
+}
+
+//GetCode 获取错误码
+func (e *expiredError) GetCode() int {
+	return e.err.GetCode()
+}
+
+//GetError 获取错误
+func (e *expiredError) GetError() error {
+	return e
+}
+
+//CanIgnore 是否可忽略
+func (e *expiredError) CanIgnore() bool {
+	return e.err.CanIgnore()
+}
+
+//IsExpired 是否为jwt过期错误
+func IsExpired(err error) bool {
+	var e *expiredError
+	return errors.As(err, &e)
+}
+
+//Issue 签发访问令牌，启用刷新令牌时同时签发刷新令牌。
+//store不为空时写入用户当前的令牌版本，用于按用户吊销
+func (j *JWTAuth) Issue(data interface{}, store IRevokeStore) (access string, refresh string, err error) {
+	user := j.GetUser(data)
+	ver, err := j.getVersion(user, store)
+	if err != nil {
+		return "", "", err
+	}
+	claims := j.newClaims(data, user, ver, j.ExpireAt)
+	if j.RefreshExpireAt > 0 {
+		rclaims := j.newClaims(data, user, ver, j.RefreshExpireAt)
+		rclaims["typ"] = TokenTypeRefresh
+		claims["rid"] = rclaims["jti"]
+		if refresh, err = j.sign(rclaims); err != nil {
+			return "", "", err
+		}
+	}
+	if access, err = j.sign(claims); err != nil {
+		return "", "", err
+	}
+	return access, refresh, nil
+}
+
+//Verify 检查访问令牌的合法性，store不为空时检查令牌是否已被吊销
+func (j *JWTAuth) Verify(token string, store IRevokeStore) (jwt.MapClaims, error) {
+	claims, err := j.parse(token, j.Source, j.Name)
+	if err != nil {
+		return nil, err
+	}
+	if claims["typ"] == TokenTypeRefresh {
+		return nil, errs.NewError(JWTStatusTokenError, fmt.Errorf("刷新令牌不能用于访问"))
+	}
+	if err := j.checkRevoked(claims, store); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+//Refresh 检查刷新令牌的合法性并返回用户数据，刷新令牌只能使用一次，使用后立即吊销
+func (j *JWTAuth) Refresh(token string, store IRevokeStore) (jwt.MapClaims, error) {
+	if j.RefreshExpireAt <= 0 {
+		return nil, errs.NewError(JWTStatusTokenError, fmt.Errorf("未启用刷新令牌"))
+	}
+	claims, err := j.parse(token, j.Source, j.GetRefreshName())
+	if err != nil {
+		return nil, err
+	}
+	if claims["typ"] != TokenTypeRefresh {
+		return nil, errs.NewError(JWTStatusTokenError, fmt.Errorf("不是有效的刷新令牌"))
+	}
+	if err := j.checkRevoked(claims, store); err != nil {
+		return nil, err
+	}
+	if store != nil {
+		if err := j.use(claims, store); err != nil {
+			return nil, err
+		}
+	}
+	return claims, nil
+}
+
+//use 标记刷新令牌已使用，通过Add保证并发请求中只有一个能使用成功
+func (j *JWTAuth) use(claims jwt.MapClaims, store IRevokeStore) error {
+	jti := types.GetString(claims["jti"])
+	if jti == "" {
+		return errs.NewError(JWTStatusTokenError, fmt.Errorf("刷新令牌未包含jti"))
+	}
+	if err := store.Add(j.revokeKey("jti", jti), "1", getTTL(claims)); err != nil {
+		return errs.NewError(JWTStatusTokenError, fmt.Errorf("刷新令牌已被使用(jti:%s):%w", jti, err))
+	}
+	return nil
+}
+
+//Revoke 吊销指定的令牌，访问令牌关联的刷新令牌同时被吊销
+func (j *JWTAuth) Revoke(claims map[string]interface{}, store IRevokeStore) error {
+	if store == nil {
+		return fmt.Errorf("未配置jwt吊销信息存储的缓存")
+	}
+	jti := types.GetString(claims["jti"])
+	if jti == "" {
+		return fmt.Errorf("jwt未包含jti,无法吊销")
+	}
+	if err := store.Set(j.revokeKey("jti", jti), "1", getTTL(claims)); err != nil {
+		return fmt.Errorf("吊销jwt失败:%w", err)
+	}
+	if rid := types.GetString(claims["rid"]); rid != "" {
+		if err := store.Set(j.revokeKey("jti", rid), "1", int(j.RefreshExpireAt)); err != nil {
+			return fmt.Errorf("吊销刷新令牌失败:%w", err)
+		}
+	}
+	return nil
+}
+
+//RevokeUser 吊销用户已签发的所有令牌(所有设备退出登录)
+func (j *JWTAuth) RevokeUser(user string, store IRevokeStore) error {
+	if store == nil {
+		return fmt.Errorf("未配置jwt吊销信息存储的缓存")
+	}
+	if user == "" {
+		return fmt.Errorf("未指定需要吊销jwt的用户")
+	}
+	ver, err := j.getVersion(user, store)
+	if err != nil {
+		return err
+	}
+
+	//版本号需保留至该用户所有令牌过期
+	expire := int(j.ExpireAt)
+	if j.RefreshExpireAt > j.ExpireAt {
+		expire = int(j.RefreshExpireAt)
+	}
+	if j.ExpireAt == 0 {
+		expire = 0
+	}
+	//使用当前时间作为新版本号，版本信息过期后再次吊销时仍大于已签发令牌的版本
+	next := time.Now().UnixNano() / int64(time.Microsecond)
+	if next <= ver {
+		next = ver + 1
+	}
+	if err := store.Set(j.revokeKey("user", user), types.GetString(next), expire); err != nil {
+		return fmt.Errorf("吊销用户(%s)的jwt失败:%w", user, err)
+	}
+	return nil
+}
+
+//getTTL 获取令牌的剩余有效时长(秒)，吊销信息保留至令牌过期
+func getTTL(claims map[string]interface{}) int {
+	exp := types.GetInt64(claims["exp"])
+	if exp <= 0 {
+		return 0
+	}
+	if ttl := int(exp-time.Now().Unix()) + 1; ttl > 0 {
+		return ttl
+	}
+	return 1
+}
+
+//GetUser 获取用户数据中的用户标识，用于按用户吊销令牌
+func (j *JWTAuth) GetUser(data interface{}) string {
+	switch v := data.(type) {
+	case string:
+		return v
+	case int, int32, int64, float64:
+		return types.GetString(v)
+	case map[string]interface{}:
+		return types.GetString(v[j.getUserKey()])
+	case map[string]string:
+		return v[j.getUserKey()]
+	default:
+		return ""
+	}
+}
+
+//GetRefreshName 获取刷新令牌的cookie或header名称
+func (j *JWTAuth) GetRefreshName() string {
+	if j.RefreshName == "" {
+		return RefreshName
+	}
+	return j.RefreshName
+}
+
+//GetRefreshForRspns 获取刷新令牌的响应参数值
+func (j *JWTAuth) GetRefreshForRspns(token string, expired ...bool) (string, string, bool) {
+	return j.getRspns(j.GetRefreshName(), token, j.RefreshExpireAt, types.GetBoolByIndex(expired, 0, false))
+}
+
+//GetData 获取声明中的用户数据，hydra签发的jwt返回data，其它签发者返回所有声明
+func GetData(claims map[string]interface{}) interface{} {
+	if data, ok := claims["data"]; ok {
+		return data
+	}
+	return claims
+}
+
+//parse 解密jwt并检查签名、有效期、签发者与接收者
+func (j *JWTAuth) parse(token string, source string, name string) (jwt.MapClaims, error) {
+	if token == "" {
+		return nil, errs.NewError(JWTStatusTokenError, fmt.Errorf("未传入jwt.token(%s %s值为空)", source, name))
+	}
+	if !strings.HasPrefix(token, TokenBearerPrefix) {
+		return nil, errs.NewError(JWTStatusTokenError, fmt.Errorf("jwt.token格式错误(%s)", token))
+	}
+	token = token[len(TokenBearerPrefix):]
+
+	store, err := j.getStore()
+	if err != nil {
+		return nil, errs.NewError(JWTStatusConfDataError, err)
+	}
+	claims := jwt.MapClaims{}
+	if _, er := jwt.ParseWithClaims(token, claims, store.keyFunc); er != nil {
+		if strings.Contains(er.Error(), "Token is expired") {
+			return nil, &expiredError{errs.NewError(JWTStatusTokenExpired, er)}
+		}
+		return nil, errs.NewError(JWTStatusTokenError, fmt.Errorf("jwt.token值(%s)有误 %w", token, er))
+	}
+
+	if j.Issuer != "" && !claims.VerifyIssuer(j.Issuer, true) {
+		return nil, errs.NewError(JWTStatusTokenError, fmt.Errorf("jwt.token签发者(%v)有误", claims["iss"]))
+	}
+	if j.Audience != "" && !verifyAudience(claims["aud"], j.Audience) {
+		return nil, errs.NewError(JWTStatusTokenError, fmt.Errorf("jwt.token接收者(%v)有误", claims["aud"]))
+	}
+	return claims, nil
+}
+
+//checkRevoked 检查令牌是否被单独吊销，或签发后用户的令牌被全部吊销
+func (j *JWTAuth) checkRevoked(claims jwt.MapClaims, store IRevokeStore) error {
+	if store == nil {
+		return nil
+	}
+	if jti := types.GetString(claims["jti"]); jti != "" && store.Exists(j.revokeKey("jti", jti)) {
+		return errs.NewError(JWTStatusTokenError, fmt.Errorf("jwt.token已被吊销(jti:%s)", jti))
+	}
+	user := types.GetString(claims["sub"])
+	if user == "" {
+		return nil
+	}
+	ver, err := j.getVersion(user, store)
+	if err != nil {
+		return errs.NewError(JWTStatusConfDataError, err)
+	}
+	if types.GetInt64(claims["ver"]) < ver {
+		return errs.NewError(JWTStatusTokenError, fmt.Errorf("用户(%s)的jwt.token已被吊销", user))
+	}
+	return nil
+}
+
+//getVersion 获取用户当前的令牌版本，每次按用户吊销时递增
+func (j *JWTAuth) getVersion(user string, store IRevokeStore) (int64, error) {
+	if store == nil || user == "" {
+		return 0, nil
+	}
+	key := j.revokeKey("user", user)
+	if !store.Exists(key) {
+		return 0, nil
+	}
+	v, err := store.Get(key)
+	if err != nil {
+		return 0, fmt.Errorf("获取用户(%s)的jwt版本失败:%w", user, err)
+	}
+	return types.GetInt64(v), nil
+}
+
+func (j *JWTAuth) newClaims(data interface{}, user string, ver int64, expire int64) jwt.MapClaims {
+	now := time.Now().Unix()
+	expireAt := now + expire
+	if expire == 0 {
+		expireAt = 0
+	}
+	claims := jwt.MapClaims{"exp": expireAt, "iat": now, "jti": utility.GetGUID(), "data": data}
+	if user != "" {
+		claims["sub"] = user
+	}
+	if ver > 0 {
+		claims["ver"] = ver
+	}
+	return claims
+}
+
+//sign 使用当前密钥签发jwt
+func (j *JWTAuth) sign(claims jwt.MapClaims) (string, error) {
+	store, err := j.getStore()
+	if err != nil {
+		return "", err
+	}
+	key, err := store.signer()
+	if err != nil {
+		return "", err
+	}
+	if j.Issuer != "" {
+		claims["iss"] = j.Issuer
+	}
+	if j.Audience != "" {
+		claims["aud"] = j.Audience
+	}
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.mode), claims)
+	if key.kid != "" {
+		token.Header["kid"] = key.kid
+	}
+	return token.SignedString(key.sign)
+}
+
+func (j *JWTAuth) revokeKey(tp string, id string) string {
+	return fmt.Sprintf("hydra:jwt:%s:revoked:%s:%s", j.Name, tp, id)
+}
+
+func (j *JWTAuth) getUserKey() string {
+	if j.UserKey == "" {
+		return defUserKey
+	}
+	return j.UserKey
+}
+
+//defUserKey 用户数据中用户标识的默认字段名
+const defUserKey = "uid"
@@ -0,0 +1,131 @@
+package oidc
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/micro-plat/hydra/conf"
+	"github.com/micro-plat/hydra/registry"
+)
+
+const (
+	//ParNodeName auth-oidc配置父节点名
+	ParNodeName = "auth"
+	//SubNodeName auth-oidc配置子节点名
+	SubNodeName = "oidc"
+)
+
+//DefLoginPath 默认的登录地址，访问后跳转到认证服务器
+const DefLoginPath = "/oidc/login"
+
+//StateCookie 保存state,nonce与PKCE校验码的cookie名称
+const StateCookie = "hydra_oidc_state"
+
+//OIDC OpenID Connect认证配置(授权码+PKCE模式)，认证通过后签发hydra的jwt
+type OIDC struct {
+
+	//Issuer 认证服务器地址，通过{issuer}/.well-known/openid-configuration获取端点信息
+	Issuer string `json:"issuer,omitempty" valid:"url,required" toml:"issuer,omitempty" label:"oidc认证服务器"`
+
+	//ClientID 客户端编号
+	ClientID string `json:"clientID,omitempty" valid:"ascii,required" toml:"clientID,omitempty" label:"oidc客户端编号"`
+
+	//ClientSecret 客户端密钥，公开客户端可不设置，仅使用PKCE
+	ClientSecret string `json:"clientSecret,omitempty" toml:"clientSecret,omitempty"`
+
+	//RedirectURL 认证回调地址，其路径由oidc中间件处理
+	RedirectURL string `json:"redirectURL,omitempty" valid:"url,required" toml:"redirectURL,omitempty" label:"oidc回调地址"`
+
+	//LoginPath 登录地址，可通过redirect参数指定登录后的跳转地址
+	LoginPath string `json:"loginPath,omitempty" toml:"loginPath,omitempty"`
+
+	//SuccessURL 登录成功后默认的跳转地址
+	SuccessURL string `json:"successURL,omitempty" toml:"successURL,omitempty"`
+
+	//Scopes 请求的授权范围，默认为openid profile email
+	Scopes []string `json:"scopes,omitempty" toml:"scopes,omitempty"`
+
+	//Claims 用户信息字段与ID Token声明的映射，未设置时保留所有非标准声明，并将sub映射为uid
+	Claims map[string]string `json:"claims,omitempty" toml:"claims,omitempty"`
+
+	//AuthEndpoint 授权地址，未设置时从发现文档获取
+	AuthEndpoint string `json:"authEndpoint,omitempty" valid:"url" toml:"authEndpoint,omitempty"`
+
+	//TokenEndpoint 令牌地址，未设置时从发现文档获取
+	TokenEndpoint string `json:"tokenEndpoint,omitempty" valid:"url" toml:"tokenEndpoint,omitempty"`
+
+	//JWKSURL ID Token签名公钥集地址，未设置时从发现文档获取
+	JWKSURL string `json:"jwksURL,omitempty" valid:"url" toml:"jwksURL,omitempty"`
+
+	Disable bool `json:"disable,omitempty" toml:"disable,omitempty"`
+
+	provider *provider
+	lock     sync.Mutex
+}
+
+//New 构建oidc配置
+func New(issuer string, clientID string, redirectURL string, opts ...Option) *OIDC {
+	o := &OIDC{
+		Issuer:      issuer,
+		ClientID:    clientID,
+		RedirectURL: redirectURL,
+		LoginPath:   DefLoginPath,
+		SuccessURL:  "/",
+		Scopes:      []string{"openid", "profile", "email"},
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+//GetLoginPath 获取登录地址
+func (o *OIDC) GetLoginPath() string {
+	if o.LoginPath == "" {
+		return DefLoginPath
+	}
+	return o.LoginPath
+}
+
+//GetCallbackPath 获取回调地址的路径
+func (o *OIDC) GetCallbackPath() string {
+	u, err := url.Parse(o.RedirectURL)
+	if err != nil || u.Path == "" {
+		return "/"
+	}
+	return u.Path
+}
+
+//GetSuccessURL 获取登录成功后的跳转地址，只允许站内相对地址
+func (o *OIDC) GetSuccessURL(redirect string) string {
+	if strings.HasPrefix(redirect, "/") && !strings.HasPrefix(redirect, "//") && !strings.Contains(redirect, "\\") {
+		return redirect
+	}
+	if o.SuccessURL == "" {
+		return "/"
+	}
+	return o.SuccessURL
+}
+
+//GetConf 获取oidc配置
+func GetConf(cnf conf.IServerConf) (*OIDC, error) {
+	o := OIDC{}
+	_, err := cnf.GetSubObject(registry.Join(ParNodeName, SubNodeName), &o)
+	if errors.Is(err, conf.ErrNoSetting) {
+		return &OIDC{Disable: true}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("oidc配置格式有误:%v", err)
+	}
+	if b, err := govalidator.ValidateStruct(&o); !b {
+		return nil, fmt.Errorf("oidc配置数据有误:%v", err)
+	}
+	if len(o.Scopes) == 0 {
+		o.Scopes = []string{"openid", "profile", "email"}
+	}
+	return &o, nil
+}
@@ -0,0 +1,119 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/micro-plat/lib4go/assert"
+	jwt "github.com/zkfy/jwt-go"
+)
+
+//mockIDP 模拟认证服务器
+type mockIDP struct {
+	*httptest.Server
+	key       *rsa.PrivateKey
+	challenge string
+	nonce     string
+}
+
+func newMockIDP(t *testing.T) *mockIDP {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Equal(t, nil, err, "生成RSA密钥")
+	m := &mockIDP{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 m.URL,
+			"authorization_endpoint": m.URL + "/authorize",
+			"token_endpoint":         m.URL + "/token",
+			"jwks_uri":               m.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+			"kty": "RSA", "kid": "k1", "alg": "RS256", "use": "sig",
+			"n": base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+		if r.PostForm.Get("code") != "c1" || base64.RawURLEncoding.EncodeToString(sum[:]) != m.challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id_token": m.sign(r.PostForm.Get("client_id"), m.nonce)})
+	})
+	m.Server = httptest.NewServer(mux)
+	return m
+}
+
+func (m *mockIDP) sign(aud string, nonce string) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss": m.URL, "aud": aud, "sub": "u1", "name": "colin", "nonce": nonce,
+		"exp": time.Now().Add(time.Minute).Unix(), "iat": time.Now().Unix(),
+	})
+	token.Header["kid"] = "k1"
+	raw, _ := token.SignedString(m.key)
+	return raw
+}
+
+//authorize 模拟用户在认证服务器完成登录
+func (m *mockIDP) authorize(t *testing.T, authURL string) url.Values {
+	u, err := url.Parse(authURL)
+	assert.Equal(t, nil, err, "授权地址")
+	q := u.Query()
+	m.challenge = q.Get("code_challenge")
+	m.nonce = q.Get("nonce")
+	return q
+}
+
+func TestOIDC_Login(t *testing.T) {
+	idp := newMockIDP(t)
+	defer idp.Close()
+
+	o := New(idp.URL, "app", "http://localhost/oidc/callback", WithClaim("uid", "sub"), WithClaim("name", "name"))
+	state := NewState("/home")
+	authURL, err := o.AuthCodeURL(state)
+	assert.Equal(t, nil, err, "获取授权地址")
+	assert.Equal(t, true, strings.HasPrefix(authURL, idp.URL+"/authorize?"), "获取授权地址")
+	q := idp.authorize(t, authURL)
+	assert.Equal(t, "S256", q.Get("code_challenge_method"), "使用PKCE")
+	assert.Equal(t, state.State, q.Get("state"), "传入state")
+
+	cookie, err := DecodeState(state.Encode())
+	assert.Equal(t, nil, err, "解码登录状态")
+	assert.Equal(t, state, cookie, "解码登录状态")
+
+	_, err = o.Exchange("c1", NewState(""))
+	assert.NotEqual(t, nil, err, "PKCE校验码不一致")
+
+	claims, err := o.Exchange("c1", cookie)
+	assert.Equal(t, nil, err, "换取并验证id_token")
+	assert.Equal(t, map[string]interface{}{"uid": "u1", "name": "colin"}, o.MapClaims(claims), "映射用户信息")
+
+	_, err = o.VerifyIDToken(idp.sign("app", "other"), state.Nonce)
+	assert.NotEqual(t, nil, err, "nonce不一致")
+	_, err = o.VerifyIDToken(idp.sign("other", state.Nonce), state.Nonce)
+	assert.NotEqual(t, nil, err, "接收者不一致")
+}
+
+func TestOIDC_GetSuccessURL(t *testing.T) {
+	o := New("http://idp", "app", "http://localhost/cb", WithSuccessURL("/index"))
+	assert.Equal(t, "/cb", o.GetCallbackPath(), "回调路径")
+	assert.Equal(t, "/order?id=1", o.GetSuccessURL("/order?id=1"), "站内地址")
+	assert.Equal(t, "/index", o.GetSuccessURL("http://evil.com"), "禁止跳转到站外")
+	assert.Equal(t, "/index", o.GetSuccessURL("//evil.com"), "禁止跳转到站外")
+}
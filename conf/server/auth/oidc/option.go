@@ -0,0 +1,65 @@
+package oidc
+
+//Option oidc配置选项
+type Option func(*OIDC)
+
+//WithSecret 设置客户端密钥
+func WithSecret(secret string) Option {
+	return func(o *OIDC) {
+		o.ClientSecret = secret
+	}
+}
+
+//WithLoginPath 设置登录地址
+func WithLoginPath(path string) Option {
+	return func(o *OIDC) {
+		o.LoginPath = path
+	}
+}
+
+//WithSuccessURL 设置登录成功后默认的跳转地址
+func WithSuccessURL(url string) Option {
+	return func(o *OIDC) {
+		o.SuccessURL = url
+	}
+}
+
+//WithScopes 设置请求的授权范围
+func WithScopes(scopes ...string) Option {
+	return func(o *OIDC) {
+		o.Scopes = scopes
+	}
+}
+
+//WithClaim 添加用户信息字段与ID Token声明的映射
+func WithClaim(field string, claim string) Option {
+	return func(o *OIDC) {
+		if o.Claims == nil {
+			o.Claims = make(map[string]string)
+		}
+		o.Claims[field] = claim
+	}
+}
+
+//WithEndpoints 设置授权、令牌与公钥集地址，设置后不再获取发现文档
+func WithEndpoints(auth string, token string, jwks string) Option {
+	return func(o *OIDC) {
+		o.AuthEndpoint = auth
+		o.TokenEndpoint = token
+		o.JWKSURL = jwks
+	}
+}
+
+//WithDisable 禁用配置
+func WithDisable() Option {
+	return func(o *OIDC) {
+		o.Disable = true
+	}
+}
+
+//WithEnable 启用配置
+func WithEnable() Option {
+	return func(o *OIDC) {
+		o.Disable = false
+	}
+}
@@ -0,0 +1,240 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/micro-plat/hydra/conf/server/auth/jwt"
+)
+
+//stateExpire 登录状态cookie的有效期(秒)
+const stateExpire = 600
+
+//registered ID Token中的标准声明，未配置Claims映射时不写入用户信息
+var registered = map[string]bool{"iss": true, "aud": true, "exp": true, "iat": true, "nbf": true,
+	"nonce": true, "at_hash": true, "c_hash": true, "azp": true, "auth_time": true, "acr": true, "amr": true, "sid": true}
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+//provider 认证服务器端点信息
+type provider struct {
+	Issuer        string `json:"issuer"`
+	AuthEndpoint  string `json:"authorization_endpoint"`
+	TokenEndpoint string `json:"token_endpoint"`
+	JWKSURL       string `json:"jwks_uri"`
+	verifier      *jwt.JWTAuth
+}
+
+//State 一次登录请求的状态信息，保存在cookie中，回调时校验
+type State struct {
+	State    string `json:"s"`
+	Nonce    string `json:"n"`
+	Verifier string `json:"v"`
+	Redirect string `json:"r,omitempty"`
+}
+
+//NewState 创建登录状态，生成随机的state,nonce与PKCE校验码
+func NewState(redirect string) *State {
+	return &State{State: random(), Nonce: random(), Verifier: random(), Redirect: redirect}
+}
+
+//Encode 编码为cookie值
+func (s *State) Encode() string {
+	buff, _ := json.Marshal(s)
+	return base64.RawURLEncoding.EncodeToString(buff)
+}
+
+//GetChallenge 获取PKCE校验码对应的S256挑战码
+func (s *State) GetChallenge() string {
+	sum := sha256.Sum256([]byte(s.Verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+//DecodeState 解码cookie中的登录状态
+func DecodeState(v string) (*State, error) {
+	buff, err := base64.RawURLEncoding.DecodeString(v)
+	if err != nil {
+		return nil, fmt.Errorf("oidc登录状态格式有误:%w", err)
+	}
+	s := &State{}
+	if err := json.Unmarshal(buff, s); err != nil {
+		return nil, fmt.Errorf("oidc登录状态格式有误:%w", err)
+	}
+	if s.State == "" || s.Nonce == "" || s.Verifier == "" {
+		return nil, fmt.Errorf("oidc登录状态不完整")
+	}
+	return s, nil
+}
+
+//GetStateCookie 获取保存登录状态的Set-Cookie值，state为空时清除cookie
+func (o *OIDC) GetStateCookie(s *State) string {
+	if s == nil {
+		return fmt.Sprintf("%s=;path=/;max-age=-1;HttpOnly;SameSite=Lax", StateCookie)
+	}
+	return fmt.Sprintf("%s=%s;path=/;max-age=%d;HttpOnly;SameSite=Lax", StateCookie, s.Encode(), stateExpire)
+}
+
+//AuthCodeURL 获取认证服务器的授权地址
+func (o *OIDC) AuthCodeURL(s *State) (string, error) {
+	p, err := o.getProvider()
+	if err != nil {
+		return "", err
+	}
+	values := url.Values{}
+	values.Set("response_type", "code")
+	values.Set("client_id", o.ClientID)
+	values.Set("redirect_uri", o.RedirectURL)
+	values.Set("scope", strings.Join(o.Scopes, " "))
+	values.Set("state", s.State)
+	values.Set("nonce", s.Nonce)
+	values.Set("code_challenge", s.GetChallenge())
+	values.Set("code_challenge_method", "S256")
+	if strings.Contains(p.AuthEndpoint, "?") {
+		return p.AuthEndpoint + "&" + values.Encode(), nil
+	}
+	return p.AuthEndpoint + "?" + values.Encode(), nil
+}
+
+//Exchange 使用授权码换取ID Token，验证签名、签发者、接收者、有效期与nonce后返回所有声明
+func (o *OIDC) Exchange(code string, s *State) (map[string]interface{}, error) {
+	p, err := o.getProvider()
+	if err != nil {
+		return nil, err
+	}
+	values := url.Values{}
+	values.Set("grant_type", "authorization_code")
+	values.Set("code", code)
+	values.Set("redirect_uri", o.RedirectURL)
+	values.Set("client_id", o.ClientID)
+	values.Set("code_verifier", s.Verifier)
+	if o.ClientSecret != "" {
+		values.Set("client_secret", o.ClientSecret)
+	}
+	resp, err := httpClient.PostForm(p.TokenEndpoint, values)
+	if err != nil {
+		return nil, fmt.Errorf("oidc获取令牌失败:%w", err)
+	}
+	defer resp.Body.Close()
+	result := struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("oidc令牌响应格式有误(%s):%w", resp.Status, err)
+	}
+	if result.Error != "" {
+		return nil, fmt.Errorf("oidc获取令牌失败:%s %s", result.Error, result.ErrorDescription)
+	}
+	if resp.StatusCode != http.StatusOK || result.IDToken == "" {
+		return nil, fmt.Errorf("oidc获取令牌失败:%s,未返回id_token", resp.Status)
+	}
+	return o.VerifyIDToken(result.IDToken, s.Nonce)
+}
+
+//VerifyIDToken 验证ID Token并返回所有声明
+func (o *OIDC) VerifyIDToken(token string, nonce string) (map[string]interface{}, error) {
+	p, err := o.getProvider()
+	if err != nil {
+		return nil, err
+	}
+	claims, err := p.verifier.Verify(jwt.TokenBearerPrefix+token, nil)
+	if err != nil {
+		return nil, fmt.Errorf("oidc id_token验证失败:%w", err)
+	}
+	if claims["nonce"] != nonce {
+		return nil, fmt.Errorf("oidc id_token的nonce(%v)不一致", claims["nonce"])
+	}
+	if _, ok := claims["sub"].(string); !ok {
+		return nil, fmt.Errorf("oidc id_token未包含sub")
+	}
+	return claims, nil
+}
+
+//MapClaims 将ID Token声明转换为用户信息
+func (o *OIDC) MapClaims(claims map[string]interface{}) map[string]interface{} {
+	data := make(map[string]interface{})
+	if len(o.Claims) == 0 {
+		for k, v := range claims {
+			if !registered[k] {
+				data[k] = v
+			}
+		}
+		data["uid"] = claims["sub"]
+		return data
+	}
+	for field, claim := range o.Claims {
+		if v, ok := claims[claim]; ok {
+			data[field] = v
+		}
+	}
+	return data
+}
+
+//getProvider 获取认证服务器端点信息，未全部配置时从发现文档获取
+func (o *OIDC) getProvider() (*provider, error) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	if o.provider != nil {
+		return o.provider, nil
+	}
+	p := &provider{Issuer: o.Issuer, AuthEndpoint: o.AuthEndpoint, TokenEndpoint: o.TokenEndpoint, JWKSURL: o.JWKSURL}
+	if p.AuthEndpoint == "" || p.TokenEndpoint == "" || p.JWKSURL == "" {
+		d, err := discover(o.Issuer)
+		if err != nil {
+			return nil, err
+		}
+		if d.Issuer != "" && strings.TrimSuffix(d.Issuer, "/") != strings.TrimSuffix(o.Issuer, "/") {
+			return nil, fmt.Errorf("oidc发现文档的issuer(%s)与配置(%s)不一致", d.Issuer, o.Issuer)
+		}
+		p.Issuer = getValue(d.Issuer, p.Issuer)
+		p.AuthEndpoint = getValue(p.AuthEndpoint, d.AuthEndpoint)
+		p.TokenEndpoint = getValue(p.TokenEndpoint, d.TokenEndpoint)
+		p.JWKSURL = getValue(p.JWKSURL, d.JWKSURL)
+	}
+	if p.AuthEndpoint == "" || p.TokenEndpoint == "" || p.JWKSURL == "" {
+		return nil, fmt.Errorf("oidc未获取到授权、令牌或公钥集地址")
+	}
+	p.verifier = jwt.NewJWT(jwt.WithSecret(""), jwt.WithJWKS(p.JWKSURL), jwt.WithIssuer(p.Issuer), jwt.WithAudience(o.ClientID))
+	o.provider = p
+	return p, nil
+}
+
+//discover 获取认证服务器的发现文档
+func discover(issuer string) (*provider, error) {
+	resp, err := httpClient.Get(strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration")
+	if err != nil {
+		return nil, fmt.Errorf("oidc获取发现文档失败:%w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("oidc获取发现文档失败:%s", resp.Status)
+	}
+	p := &provider{}
+	if err := json.NewDecoder(resp.Body).Decode(p); err != nil {
+		return nil, fmt.Errorf("oidc发现文档格式有误:%w", err)
+	}
+	return p, nil
+}
+
+func getValue(v string, def string) string {
+	if v == "" {
+		return def
+	}
+	return v
+}
+
+func random() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
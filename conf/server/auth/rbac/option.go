@@ -0,0 +1,70 @@
+package rbac
+
+//Option 访问控制配置选项
+type Option func(*RBAC)
+
+//WithRule 添加路径访问规则
+func WithRule(rule *Rule) Option {
+	return func(r *RBAC) {
+		r.Rules = append(r.Rules, rule)
+	}
+}
+
+//WithRoles 添加路径访问规则，拥有任意一个角色即可访问
+func WithRoles(path string, roles ...string) Option {
+	return WithRule(&Rule{Path: path, Roles: roles})
+}
+
+//WithPermissions 添加路径访问规则，拥有所有权限才可访问
+func WithPermissions(path string, perms ...string) Option {
+	return WithRule(&Rule{Path: path, Permissions: perms})
+}
+
+//WithRole 设置角色拥有的权限
+func WithRole(role string, perms ...string) Option {
+	return func(r *RBAC) {
+		r.Roles[role] = append(r.Roles[role], perms...)
+	}
+}
+
+//WithRoleKey 设置用户认证信息中角色字段名
+func WithRoleKey(key string) Option {
+	return func(r *RBAC) {
+		r.RoleKey = key
+	}
+}
+
+//WithPermissionKey 设置用户认证信息中权限字段名
+func WithPermissionKey(key string) Option {
+	return func(r *RBAC) {
+		r.PermissionKey = key
+	}
+}
+
+//WithDeny 未匹配到规则的请求禁止访问
+func WithDeny() Option {
+	return func(r *RBAC) {
+		r.Deny = true
+	}
+}
+
+//WithExcludes 排除的服务或请求
+func WithExcludes(p ...string) Option {
+	return func(r *RBAC) {
+		r.Excludes = p
+	}
+}
+
+//WithDisable 禁用配置
+func WithDisable() Option {
+	return func(r *RBAC) {
+		r.Disable = true
+	}
+}
+
+//WithEnable 启用配置
+func WithEnable() Option {
+	return func(r *RBAC) {
+		r.Disable = false
+	}
+}
@@ -0,0 +1,211 @@
+package rbac
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/micro-plat/hydra/conf"
+	"github.com/micro-plat/hydra/registry"
+	"github.com/micro-plat/lib4go/types"
+)
+
+const (
+	//ParNodeName auth-rbac配置父节点名
+	ParNodeName = "auth"
+	//SubNodeName auth-rbac配置子节点名
+	SubNodeName = "rbac"
+)
+
+//All 拥有所有权限或匹配所有请求方式
+const All = "*"
+
+//Rule 路径访问规则
+type Rule struct {
+
+	//Path 请求路径，支持与excludes相同的模糊匹配，如/order/*,/order/**
+	Path string `json:"path,omitempty" valid:"ascii,required" toml:"path,omitempty" label:"授权规则路径"`
+
+	//Methods 请求方式，未设置时匹配所有方式
+	Methods []string `json:"methods,omitempty" toml:"methods,omitempty"`
+
+	//Roles 可访问的角色，拥有其中任意一个角色即可
+	Roles []string `json:"roles,omitempty" toml:"roles,omitempty"`
+
+	//Permissions 访问需要的权限，需拥有所有权限
+	Permissions []string `json:"permissions,omitempty" toml:"permissions,omitempty"`
+}
+
+//RBAC 基于角色与权限的访问控制配置
+type RBAC struct {
+
+	//Rules 路径访问规则
+	Rules []*Rule `json:"rules,omitempty" toml:"rules,omitempty"`
+
+	//Roles 角色拥有的权限，权限为*时拥有所有权限
+	Roles map[string][]string `json:"roles,omitempty" toml:"roles,omitempty"`
+
+	//RoleKey 用户认证信息中角色字段名，默认为roles
+	RoleKey string `json:"roleKey,omitempty" toml:"roleKey,omitempty"`
+
+	//PermissionKey 用户认证信息中权限字段名，默认为permissions
+	PermissionKey string `json:"permissionKey,omitempty" toml:"permissionKey,omitempty"`
+
+	//Deny 未匹配到规则的请求是否禁止访问
+	Deny bool `json:"deny,omitempty" toml:"deny,omitempty"`
+
+	//Excludes 排除不检查的路径
+	Excludes []string `json:"excludes,omitempty" toml:"excludes,omitempty"`
+
+	Disable bool `json:"disable,omitempty" toml:"disable,omitempty"`
+
+	*conf.PathMatch `json:"-"`
+	rules           *conf.PathMatch
+}
+
+//New 构建访问控制配置
+func New(opts ...Option) *RBAC {
+	r := &RBAC{
+		Rules: make([]*Rule, 0, 1),
+		Roles: make(map[string][]string),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	r.init()
+	return r
+}
+
+func (r *RBAC) init() {
+	r.PathMatch = conf.NewPathMatch(r.Excludes...)
+	paths := make([]string, 0, len(r.Rules))
+	for _, rule := range r.Rules {
+		paths = append(paths, rule.Path)
+	}
+	r.rules = conf.NewPathMatch(paths...)
+}
+
+//Authorize 检查用户是否可访问指定请求，required为注册服务时指定的权限
+func (r *RBAC) Authorize(path string, method string, user map[string]interface{}, required ...string) error {
+	roles := r.GetRoles(user)
+	perms := r.GetPermissions(user, roles)
+
+	//服务注册时指定的权限
+	if lack := lacks(perms, required); len(lack) > 0 {
+		return fmt.Errorf("缺少权限%v", lack)
+	}
+
+	//配置的访问规则
+	rule := r.match(path, method)
+	if rule == nil {
+		if r.Deny && len(required) == 0 {
+			return fmt.Errorf("未配置访问规则")
+		}
+		return nil
+	}
+	if len(rule.Roles) > 0 && !containsAny(roles, rule.Roles) {
+		return fmt.Errorf("需要角色%v之一,当前角色%v", rule.Roles, roles)
+	}
+	if lack := lacks(perms, rule.Permissions); len(lack) > 0 {
+		return fmt.Errorf("缺少权限%v", lack)
+	}
+	return nil
+}
+
+//GetRoles 获取用户认证信息中的角色
+func (r *RBAC) GetRoles(user map[string]interface{}) []string {
+	return getStrings(user[types.GetString(r.RoleKey, "roles")])
+}
+
+//GetPermissions 获取用户认证信息中的权限与角色拥有的权限
+func (r *RBAC) GetPermissions(user map[string]interface{}, roles []string) []string {
+	perms := getStrings(user[types.GetString(r.PermissionKey, "permissions")])
+	for _, role := range roles {
+		perms = append(perms, r.Roles[role]...)
+	}
+	return perms
+}
+
+//match 获取与请求路径、方式匹配的规则
+func (r *RBAC) match(path string, method string) *Rule {
+	ok, pattern := r.rules.Match(path)
+	if !ok {
+		return nil
+	}
+	for _, rule := range r.Rules {
+		if rule.Path != pattern {
+			continue
+		}
+		if len(rule.Methods) == 0 || containsAny(rule.Methods, []string{All, strings.ToUpper(method)}) {
+			return rule
+		}
+	}
+	return nil
+}
+
+//lacks 获取未拥有的权限
+func lacks(perms []string, required []string) []string {
+	if types.StringContains(perms, All) {
+		return nil
+	}
+	lack := make([]string, 0, len(required))
+	for _, p := range required {
+		if !types.StringContains(perms, p) {
+			lack = append(lack, p)
+		}
+	}
+	return lack
+}
+
+func containsAny(list []string, values []string) bool {
+	for _, v := range values {
+		if types.StringContains(list, v) {
+			return true
+		}
+	}
+	return false
+}
+
+//getStrings 转换角色或权限，支持数组与逗号分隔的字符串
+func getStrings(v interface{}) []string {
+	switch t := v.(type) {
+	case nil:
+		return nil
+	case []string:
+		return t
+	case []interface{}:
+		list := make([]string, 0, len(t))
+		for _, i := range t {
+			list = append(list, types.GetString(i))
+		}
+		return list
+	default:
+		s := types.GetString(t)
+		if s == "" {
+			return nil
+		}
+		list := strings.Split(s, ",")
+		for i := range list {
+			list[i] = strings.TrimSpace(list[i])
+		}
+		return list
+	}
+}
+
+//GetConf 获取访问控制配置，未配置时只检查注册服务时指定的权限
+func GetConf(cnf conf.IServerConf) (*RBAC, error) {
+	r := RBAC{}
+	_, err := cnf.GetSubObject(registry.Join(ParNodeName, SubNodeName), &r)
+	if errors.Is(err, conf.ErrNoSetting) {
+		return New(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("rbac配置格式有误:%v", err)
+	}
+	if b, err := govalidator.ValidateStruct(&r); !b {
+		return nil, fmt.Errorf("rbac配置数据有误:%v", err)
+	}
+	r.init()
+	return &r, nil
+}
@@ -0,0 +1,32 @@
+package rbac
+
+import (
+	"testing"
+
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func TestRBAC_Authorize(t *testing.T) {
+	r := New(WithRole("admin", All), WithRole("clerk", "order:view"),
+		WithRoles("/admin/**", "admin"),
+		WithRule(&Rule{Path: "/order/*", Methods: []string{"POST"}, Permissions: []string{"order:edit"}}),
+		WithPermissions("/order/*", "order:view"),
+	)
+	admin := map[string]interface{}{"roles": []interface{}{"admin"}}
+	clerk := map[string]interface{}{"roles": "clerk"}
+	guest := map[string]interface{}{}
+
+	assert.Equal(t, nil, r.Authorize("/admin/user/list", "GET", admin), "拥有角色")
+	assert.NotEqual(t, nil, r.Authorize("/admin/user/list", "GET", clerk), "缺少角色")
+	assert.Equal(t, nil, r.Authorize("/order/query", "GET", clerk), "角色拥有权限")
+	assert.NotEqual(t, nil, r.Authorize("/order/query", "POST", clerk), "按请求方式匹配规则")
+	assert.Equal(t, nil, r.Authorize("/order/query", "POST", admin), "拥有所有权限")
+	assert.Equal(t, nil, r.Authorize("/home", "GET", guest), "未匹配规则时允许访问")
+
+	assert.NotEqual(t, nil, r.Authorize("/order/refund", "GET", clerk, "order:refund"), "缺少服务注册的权限")
+	assert.Equal(t, nil, r.Authorize("/order/refund", "GET",
+		map[string]interface{}{"roles": "clerk", "permissions": "order:refund, order:cancel"}, "order:refund"), "用户拥有权限")
+
+	deny := New(WithDeny())
+	assert.NotEqual(t, nil, deny.Authorize("/home", "GET", admin), "未匹配规则时禁止访问")
+}
@@ -0,0 +1,148 @@
+package compress
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/micro-plat/hydra/conf"
+)
+
+//TypeNodeName 响应压缩配置节点名
+const TypeNodeName = "compress"
+
+const defMinSize = 1024
+
+//defEncodings 默认支持的压缩方式(按优先级)
+var defEncodings = []string{"br", "gzip", "deflate"}
+
+//defContentTypes 默认压缩的内容类型
+var defContentTypes = []string{
+	"text/*",
+	"application/json",
+	"application/xml",
+	"application/javascript",
+	"application/x-yaml",
+}
+
+//Compress 响应压缩配置
+type Compress struct {
+	Disable bool `json:"disable,omitempty" toml:"disable,omitempty"`
+
+	//Encodings 压缩方式(br,gzip,deflate)，按优先级排列，客户端同时支持时使用靠前的方式
+	Encodings []string `json:"encodings,omitempty" toml:"encodings,omitempty"`
+
+	//MinSize 最小压缩字节数，小于该值的响应不压缩
+	MinSize int `json:"minSize,omitempty" valid:"range(0|104857600)" toml:"minSize,omitempty"`
+
+	//Level 压缩级别(1-9)，未设置时使用默认级别
+	Level int `json:"level,omitempty" valid:"range(0|9)" toml:"level,omitempty"`
+
+	//ContentTypes 需要压缩的内容类型，支持type/*
+	ContentTypes []string `json:"contentTypes,omitempty" toml:"contentTypes,omitempty"`
+}
+
+//New 构建响应压缩配置
+func New(opts ...Option) *Compress {
+	c := &Compress{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+//GetMinSize 获取最小压缩字节数
+func (c *Compress) GetMinSize() int {
+	if c.MinSize <= 0 {
+		return defMinSize
+	}
+	return c.MinSize
+}
+
+//GetEncodings 获取压缩方式
+func (c *Compress) GetEncodings() []string {
+	if len(c.Encodings) == 0 {
+		return defEncodings
+	}
+	return c.Encodings
+}
+
+//IsAllowContentType 检查内容类型是否需要压缩
+func (c *Compress) IsAllowContentType(contentType string) bool {
+	ctp := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	if ctp == "" {
+		return false
+	}
+	types := c.ContentTypes
+	if len(types) == 0 {
+		types = defContentTypes
+	}
+	for _, t := range types {
+		t = strings.ToLower(t)
+		if t == ctp || (strings.HasSuffix(t, "/*") && strings.HasPrefix(ctp, strings.TrimSuffix(t, "*"))) {
+			return true
+		}
+	}
+	return false
+}
+
+//Negotiate 根据Accept-Encoding选择压缩方式，support为服务器已支持的压缩方式，无可用方式时返回空
+func (c *Compress) Negotiate(accept string, support func(string) bool) string {
+	if accept == "" {
+		return ""
+	}
+	weights := parseAccept(accept)
+	best, bestQ := "", 0.0
+	for _, e := range c.GetEncodings() {
+		q, ok := weights[e]
+		if !ok {
+			q, ok = weights["*"]
+		}
+		if !ok || q <= bestQ || !support(e) {
+			continue
+		}
+		best, bestQ = e, q
+	}
+	return best
+}
+
+//parseAccept 解析Accept-Encoding中各编码的权重
+func parseAccept(accept string) map[string]float64 {
+	weights := make(map[string]float64)
+	for _, v := range strings.Split(accept, ",") {
+		parts := strings.Split(strings.TrimSpace(v), ";")
+		name := strings.ToLower(strings.TrimSpace(parts[0]))
+		if name == "" {
+			continue
+		}
+		q := 1.0
+		for _, p := range parts[1:] {
+			p = strings.TrimSpace(p)
+			if strings.HasPrefix(p, "q=") {
+				if f, err := strconv.ParseFloat(strings.TrimPrefix(p, "q="), 64); err == nil {
+					q = f
+				}
+			}
+		}
+		weights[name] = q
+	}
+	return weights
+}
+
+//GetConf 获取响应压缩配置，未配置时不压缩
+func GetConf(cnf conf.IServerConf) (*Compress, error) {
+	c := Compress{}
+	_, err := cnf.GetSubObject(TypeNodeName, &c)
+	if errors.Is(err, conf.ErrNoSetting) {
+		return &Compress{Disable: true}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("compress配置格式有误:%v", err)
+	}
+	if b, err := govalidator.ValidateStruct(&c); !b {
+		return nil, fmt.Errorf("compress配置数据有误:%v", err)
+	}
+	return &c, nil
+}
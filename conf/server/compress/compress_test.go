@@ -0,0 +1,37 @@
+package compress
+
+import (
+	"testing"
+
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func TestCompress_Negotiate(t *testing.T) {
+	all := func(string) bool { return true }
+	noBr := func(e string) bool { return e != "br" }
+
+	c := New()
+	assert.Equal(t, "", c.Negotiate("", all), "未指定Accept-Encoding")
+	assert.Equal(t, "br", c.Negotiate("gzip, deflate, br", all), "按服务器优先级选择")
+	assert.Equal(t, "gzip", c.Negotiate("gzip, deflate, br", noBr), "跳过未支持的压缩方式")
+	assert.Equal(t, "deflate", c.Negotiate("gzip;q=0.5, deflate", noBr), "按客户端权重选择")
+	assert.Equal(t, "deflate", c.Negotiate("gzip;q=0, *", noBr), "q=0不使用")
+	assert.Equal(t, "", c.Negotiate("identity", all), "不支持压缩")
+
+	c = New(WithEncodings("deflate", "gzip"))
+	assert.Equal(t, "deflate", c.Negotiate("gzip, deflate, br", all), "自定义压缩方式")
+}
+
+func TestCompress_IsAllowContentType(t *testing.T) {
+	c := New()
+	assert.Equal(t, true, c.IsAllowContentType("application/json; charset=utf-8"), "json")
+	assert.Equal(t, true, c.IsAllowContentType("text/html;charset=utf-8"), "text/*")
+	assert.Equal(t, false, c.IsAllowContentType("image/png"), "图片不压缩")
+	assert.Equal(t, false, c.IsAllowContentType(""), "未知类型")
+	assert.Equal(t, 1024, c.GetMinSize(), "默认最小字节数")
+
+	c = New(WithContentTypes("image/*"), WithMinSize(10))
+	assert.Equal(t, true, c.IsAllowContentType("image/svg+xml"), "自定义类型")
+	assert.Equal(t, false, c.IsAllowContentType("application/json"), "不在列表中")
+	assert.Equal(t, 10, c.GetMinSize(), "最小字节数")
+}
@@ -0,0 +1,46 @@
+package compress
+
+//Option 配置选项
+type Option func(*Compress)
+
+//WithEncodings 设置压缩方式(br,gzip,deflate)，按优先级排列
+func WithEncodings(encodings ...string) Option {
+	return func(c *Compress) {
+		c.Encodings = encodings
+	}
+}
+
+//WithMinSize 设置最小压缩字节数
+func WithMinSize(size int) Option {
+	return func(c *Compress) {
+		c.MinSize = size
+	}
+}
+
+//WithLevel 设置压缩级别(1-9)
+func WithLevel(level int) Option {
+	return func(c *Compress) {
+		c.Level = level
+	}
+}
+
+//WithContentTypes 设置需要压缩的内容类型，支持type/*
+func WithContentTypes(contentTypes ...string) Option {
+	return func(c *Compress) {
+		c.ContentTypes = contentTypes
+	}
+}
+
+//WithDisable 禁用响应压缩
+func WithDisable() Option {
+	return func(c *Compress) {
+		c.Disable = true
+	}
+}
+
+//WithEnable 启用响应压缩
+func WithEnable() Option {
+	return func(c *Compress) {
+		c.Disable = false
+	}
+}
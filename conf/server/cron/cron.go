@@ -27,8 +27,21 @@ type Server struct {
 	Status   string `json:"status,omitempty" valid:"in(start|stop)" toml:"status,omitempty" label:"cron服务状态"`
 	Sharding int    `json:"sharding,omitempty" toml:"sharding,omitempty"`
 	Trace    bool   `json:"trace,omitempty" toml:"trace,omitempty"`
+
+	//History 执行历史存储方式，registry:注册中心，db:数据库，未设置时不记录
+	History     string `json:"history,omitempty" valid:"in(registry|db)" toml:"history,omitempty" label:"执行历史存储方式"`
+	HistoryDB   string `json:"historyDB,omitempty" toml:"historyDB,omitempty" label:"执行历史数据库"`
+	HistoryKeep int    `json:"historyKeep,omitempty" toml:"historyKeep,omitempty" label:"每个任务保留的历史记录数"`
 }
 
+const (
+	//HistoryRegistry 执行历史存储于注册中心
+	HistoryRegistry = "registry"
+
+	//HistoryDB 执行历史存储于数据库
+	HistoryDB = "db"
+)
+
 //New 构建cron server配置，默认为对等模式
 func New(opts ...Option) *Server {
 	s := &Server{
@@ -44,3 +44,21 @@ func WithEnable() Option {
 		a.Status = StartStatus
 	}
 }
+
+//WithHistory 将任务执行历史记录到注册中心，keep为每个任务保留的记录数
+func WithHistory(keep ...int) Option {
+	return func(a *Server) {
+		a.History = HistoryRegistry
+		if len(keep) > 0 {
+			a.HistoryKeep = keep[0]
+		}
+	}
+}
+
+//WithDBHistory 将任务执行历史记录到数据库，dbName为/var/db下的配置名称
+func WithDBHistory(dbName string) Option {
+	return func(a *Server) {
+		a.History = HistoryDB
+		a.HistoryDB = dbName
+	}
+}
@@ -64,6 +64,11 @@ func (h Headers) GetHeaderByOrigin(origin string) Headers {
 	return hd
 }
 
+//IsAllowOrigin 是否允许origin跨域访问
+func (h Headers) IsAllowOrigin(origin string) bool {
+	return h.hasCross(origin)
+}
+
 //hasCross 是否允许跨域访问
 func (h Headers) hasCross(origin string) bool {
 	value, ok := h[HeadeAllowOrigin]
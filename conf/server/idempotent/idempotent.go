@@ -0,0 +1,117 @@
+package idempotent
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/micro-plat/hydra/conf"
+)
+
+//TypeNodeName 幂等配置节点名
+const TypeNodeName = "idempotent"
+
+//DefHeader 默认的幂等键请求头
+const DefHeader = "Idempotency-Key"
+
+const (
+	defExpire = 86400
+	defLock   = 60
+)
+
+//Idempotent 幂等请求配置，请求头中包含幂等键时，首次处理结果将保存到缓存中，重试请求直接返回已保存的结果
+type Idempotent struct {
+	Disable bool `json:"disable,omitempty" toml:"disable,omitempty"`
+
+	//Requests 需要进行幂等处理的请求路径，支持*与**通配
+	Requests []string `json:"requests,omitempty" valid:"required" toml:"requests,omitempty" label:"幂等请求路径"`
+
+	//Header 幂等键请求头，默认为Idempotency-Key
+	Header string `json:"header,omitempty" toml:"header,omitempty"`
+
+	//Cache 保存处理结果的缓存名称，为空时使用默认缓存
+	Cache string `json:"cache,omitempty" valid:"ascii" toml:"cache,omitempty" label:"幂等缓存"`
+
+	//Expire 处理结果保存时长(秒)，默认24小时
+	Expire int `json:"expire,omitempty" valid:"range(0|2592000)" toml:"expire,omitempty"`
+
+	//Lock 请求处理中的锁定时长(秒)，超过后允许重新处理，默认60秒
+	Lock int `json:"lock,omitempty" valid:"range(0|3600)" toml:"lock,omitempty"`
+
+	//Wait 相同幂等键的请求正在处理时的最长等待时长(毫秒)，为0时直接返回409
+	Wait int `json:"wait,omitempty" valid:"range(0|60000)" toml:"wait,omitempty"`
+
+	rqm *conf.PathMatch
+}
+
+//New 构建幂等请求配置
+func New(requests []string, opts ...Option) *Idempotent {
+	c := &Idempotent{Requests: requests}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.rqm = conf.NewPathMatch(c.Requests...)
+	return c
+}
+
+//IsIdempotent 检查请求是否需要进行幂等处理，GET,HEAD,OPTIONS请求不处理
+func (c *Idempotent) IsIdempotent(method string, path string) bool {
+	if c.Disable || c.rqm == nil {
+		return false
+	}
+	switch strings.ToUpper(method) {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	ok, _ := c.rqm.Match(path)
+	return ok
+}
+
+//GetHeader 获取幂等键请求头
+func (c *Idempotent) GetHeader() string {
+	if c.Header == "" {
+		return DefHeader
+	}
+	return c.Header
+}
+
+//GetExpire 获取处理结果保存时长(秒)
+func (c *Idempotent) GetExpire() int {
+	if c.Expire <= 0 {
+		return defExpire
+	}
+	return c.Expire
+}
+
+//GetLock 获取请求处理中的锁定时长(秒)
+func (c *Idempotent) GetLock() int {
+	if c.Lock <= 0 {
+		return defLock
+	}
+	return c.Lock
+}
+
+//GetWait 获取相同幂等键请求的最长等待时长
+func (c *Idempotent) GetWait() time.Duration {
+	return time.Duration(c.Wait) * time.Millisecond
+}
+
+//GetConf 获取幂等请求配置，未配置时不启用
+func GetConf(cnf conf.IServerConf) (*Idempotent, error) {
+	c := Idempotent{}
+	_, err := cnf.GetSubObject(TypeNodeName, &c)
+	if errors.Is(err, conf.ErrNoSetting) {
+		return &Idempotent{Disable: true}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("idempotent配置格式有误:%v", err)
+	}
+	if b, err := govalidator.ValidateStruct(&c); !b {
+		return nil, fmt.Errorf("idempotent配置数据有误:%v", err)
+	}
+	c.rqm = conf.NewPathMatch(c.Requests...)
+	return &c, nil
+}
@@ -0,0 +1,33 @@
+package idempotent
+
+import (
+	"testing"
+	"time"
+
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func TestIdempotent_IsIdempotent(t *testing.T) {
+	c := New([]string{"/order/*", "/pay/**"})
+	assert.Equal(t, true, c.IsIdempotent("POST", "/order/create"), "匹配的请求路径")
+	assert.Equal(t, true, c.IsIdempotent("put", "/pay/wx/notify"), "**通配")
+	assert.Equal(t, false, c.IsIdempotent("GET", "/order/create"), "GET请求不处理")
+	assert.Equal(t, false, c.IsIdempotent("POST", "/user/create"), "未配置的请求路径")
+
+	c = New([]string{"/order/*"}, WithDisable())
+	assert.Equal(t, false, c.IsIdempotent("POST", "/order/create"), "已禁用")
+}
+
+func TestIdempotent_Default(t *testing.T) {
+	c := New([]string{"/order/*"})
+	assert.Equal(t, DefHeader, c.GetHeader(), "默认请求头")
+	assert.Equal(t, 86400, c.GetExpire(), "默认保存时长")
+	assert.Equal(t, 60, c.GetLock(), "默认锁定时长")
+	assert.Equal(t, time.Duration(0), c.GetWait(), "默认不等待")
+
+	c = New([]string{"/order/*"}, WithHeader("X-Request-Key"), WithExpire(60), WithLock(5), WithWait(500))
+	assert.Equal(t, "X-Request-Key", c.GetHeader(), "请求头")
+	assert.Equal(t, 60, c.GetExpire(), "保存时长")
+	assert.Equal(t, 5, c.GetLock(), "锁定时长")
+	assert.Equal(t, 500*time.Millisecond, c.GetWait(), "等待时长")
+}
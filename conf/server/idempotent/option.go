@@ -0,0 +1,53 @@
+package idempotent
+
+//Option 配置选项
+type Option func(*Idempotent)
+
+//WithHeader 设置幂等键请求头
+func WithHeader(header string) Option {
+	return func(c *Idempotent) {
+		c.Header = header
+	}
+}
+
+//WithCache 设置保存处理结果的缓存名称
+func WithCache(name string) Option {
+	return func(c *Idempotent) {
+		c.Cache = name
+	}
+}
+
+//WithExpire 设置处理结果保存时长(秒)
+func WithExpire(second int) Option {
+	return func(c *Idempotent) {
+		c.Expire = second
+	}
+}
+
+//WithLock 设置请求处理中的锁定时长(秒)
+func WithLock(second int) Option {
+	return func(c *Idempotent) {
+		c.Lock = second
+	}
+}
+
+//WithWait 设置相同幂等键的请求正在处理时的最长等待时长(毫秒)，未设置时直接返回409
+func WithWait(millisecond int) Option {
+	return func(c *Idempotent) {
+		c.Wait = millisecond
+	}
+}
+
+//WithDisable 禁用幂等处理
+func WithDisable() Option {
+	return func(c *Idempotent) {
+		c.Disable = true
+	}
+}
+
+//WithEnable 启用幂等处理
+func WithEnable() Option {
+	return func(c *Idempotent) {
+		c.Disable = false
+	}
+}
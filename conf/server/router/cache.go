@@ -0,0 +1,72 @@
+package router
+
+import "time"
+
+//Cache 响应缓存配置，只缓存GET,HEAD请求状态码为200的响应
+type Cache struct {
+	//TTL 缓存时长(秒)
+	TTL int `json:"ttl" toml:"ttl"`
+
+	//Stale 缓存过期后仍可使用的时长(秒)，期间由第一个请求重新执行服务刷新缓存，其它请求直接返回过期的缓存内容
+	Stale int `json:"stale,omitempty" toml:"stale,omitempty"`
+
+	//VaryQuery 参与缓存键计算的查询参数，未设置时使用全部查询参数
+	VaryQuery []string `json:"varyQuery,omitempty" toml:"varyQuery,omitempty"`
+
+	//VaryHeaders 参与缓存键计算的请求头
+	VaryHeaders []string `json:"varyHeaders,omitempty" toml:"varyHeaders,omitempty"`
+
+	//VaryUser 是否按已认证的用户(jwt sub,basic用户名或证书名称,静态密钥)分别缓存，未认证的请求不缓存
+	VaryUser bool `json:"varyUser,omitempty" toml:"varyUser,omitempty"`
+
+	//Name 缓存名称(var/cache中的配置名)，为空时使用默认缓存
+	Name string `json:"name,omitempty" toml:"name,omitempty"`
+}
+
+//GetTTL 获取缓存时长
+func (c *Cache) GetTTL() time.Duration {
+	return time.Duration(c.TTL) * time.Second
+}
+
+//GetExpire 获取缓存内容的保存时长(秒)，包含过期后仍可使用的时长
+func (c *Cache) GetExpire() int {
+	return c.TTL + c.Stale
+}
+
+//CacheOption 响应缓存配置选项
+type CacheOption func(*Cache)
+
+//WithStale 设置缓存过期后仍可使用的时长(秒)
+func WithStale(second int) CacheOption {
+	return func(c *Cache) {
+		c.Stale = second
+	}
+}
+
+//WithVaryQuery 设置参与缓存键计算的查询参数
+func WithVaryQuery(names ...string) CacheOption {
+	return func(c *Cache) {
+		c.VaryQuery = names
+	}
+}
+
+//WithVaryHeaders 设置参与缓存键计算的请求头
+func WithVaryHeaders(names ...string) CacheOption {
+	return func(c *Cache) {
+		c.VaryHeaders = names
+	}
+}
+
+//WithVaryUser 按已认证的用户分别缓存，未认证的请求不缓存
+func WithVaryUser() CacheOption {
+	return func(c *Cache) {
+		c.VaryUser = true
+	}
+}
+
+//WithCacheName 设置缓存名称
+func WithCacheName(name string) CacheOption {
+	return func(c *Cache) {
+		c.Name = name
+	}
+}
@@ -0,0 +1,18 @@
+package router
+
+import (
+	"testing"
+	"time"
+
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func TestWithCache(t *testing.T) {
+	r := NewRouter("/product/list", "/product/list", []string{"GET"}, WithCache(60, WithStale(30), WithVaryQuery("page"), WithVaryHeaders("Accept-Language"), WithVaryUser(), WithCacheName("redis")))
+	assert.Equal(t, &Cache{TTL: 60, Stale: 30, VaryQuery: []string{"page"}, VaryHeaders: []string{"Accept-Language"}, VaryUser: true, Name: "redis"}, r.Cache, "缓存配置")
+	assert.Equal(t, time.Minute, r.Cache.GetTTL(), "缓存时长")
+	assert.Equal(t, 90, r.Cache.GetExpire(), "保存时长")
+
+	r = NewRouter("/product/list", "/product/list", []string{"GET"})
+	assert.Equal(t, true, r.Cache == nil, "未配置缓存")
+}
@@ -0,0 +1,27 @@
+package router
+
+//Limits 请求大小限制，0表示不限制
+type Limits struct {
+	//MaxBodySize 请求body最大字节数
+	MaxBodySize int64 `json:"maxBodySize,omitempty" toml:"maxBodySize,omitempty"`
+
+	//MaxFileSize 单个上传文件最大字节数
+	MaxFileSize int64 `json:"maxFileSize,omitempty" toml:"maxFileSize,omitempty"`
+
+	//MaxFiles 上传文件最大个数
+	MaxFiles int `json:"maxFiles,omitempty" toml:"maxFiles,omitempty"`
+}
+
+//Merge 未设置的限制使用全局限制
+func (l Limits) Merge(global Limits) Limits {
+	if l.MaxBodySize == 0 {
+		l.MaxBodySize = global.MaxBodySize
+	}
+	if l.MaxFileSize == 0 {
+		l.MaxFileSize = global.MaxFileSize
+	}
+	if l.MaxFiles == 0 {
+		l.MaxFiles = global.MaxFiles
+	}
+	return l
+}
@@ -16,3 +16,73 @@ func WithEncoding(encoding string) Option {
 		a.Encoding = encoding
 	}
 }
+
+//WithPermissions 设置访问当前服务需要的权限
+func WithPermissions(p ...string) Option {
+	return func(a *Router) {
+		a.Permissions = p
+	}
+}
+
+//WithStream 设置为流式处理，请求body通过ctx.Request().GetBodyReader()读取，
+//响应通过ctx.Response().Stream()写入，请求参数只包含url中的参数，处理时长不受服务超时与服务器读写超时限制
+func WithStream() Option {
+	return func(a *Router) {
+		a.Stream = true
+	}
+}
+
+//WithMaxBodySize 设置请求body最大字节数，超过时返回413
+func WithMaxBodySize(size int64) Option {
+	return func(a *Router) {
+		a.MaxBodySize = size
+	}
+}
+
+//WithMaxFiles 设置上传文件个数与单个文件的最大字节数，超过时返回413
+func WithMaxFiles(count int, size int64) Option {
+	return func(a *Router) {
+		a.MaxFiles = count
+		a.MaxFileSize = size
+	}
+}
+
+//WithSchema 根据结构体的json,label,valid标签声明请求参数，执行服务前自动验证，
+//声明有误时由GetError返回，服务器启动时获取路由失败
+func WithSchema(obj interface{}) Option {
+	schema, err := NewSchema(obj)
+	return withSchema(schema, err)
+}
+
+//WithJSONSchema 根据JSON-Schema文档声明请求参数，执行服务前自动验证，
+//文档有误时由GetError返回，服务器启动时获取路由失败
+func WithJSONSchema(doc string) Option {
+	schema, err := NewJSONSchema(doc)
+	return withSchema(schema, err)
+}
+
+func withSchema(schema Schema, err error) Option {
+	if err != nil {
+		return func(a *Router) {
+			a.err = err
+		}
+	}
+	return WithFields(schema...)
+}
+
+//WithFields 声明请求参数，执行服务前自动验证
+func WithFields(fields ...*Field) Option {
+	return func(a *Router) {
+		a.Schema = append(a.Schema, fields...)
+	}
+}
+
+//WithCache 缓存GET,HEAD请求的响应内容，ttl为缓存时长(秒)
+func WithCache(ttl int, opts ...CacheOption) Option {
+	return func(a *Router) {
+		a.Cache = &Cache{TTL: ttl}
+		for _, opt := range opts {
+			opt(a.Cache)
+		}
+	}
+}
@@ -52,6 +52,23 @@ type Router struct {
 	Service  string   `json:"service,omitempty" valid:"ascii,required" toml:"service,omitempty"`
 	Encoding string   `json:"encoding,omitempty" toml:"encoding,omitempty"`
 	Pages    []string `json:"pages,omitempty" toml:"pages,omitempty"`
+
+	//Permissions 访问服务需要的权限，由rbac中间件检查
+	Permissions []string `json:"permissions,omitempty" toml:"permissions,omitempty"`
+
+	//Stream 是否流式处理请求与响应，不再将请求body读入内存，适用于大文件上传与下载
+	Stream bool `json:"stream,omitempty" toml:"stream,omitempty"`
+
+	//Schema 请求参数声明，执行服务前自动验证
+	Schema Schema `json:"schema,omitempty" toml:"schema,omitempty"`
+
+	//Cache 响应缓存配置，未设置时不缓存
+	Cache *Cache `json:"cache,omitempty" toml:"cache,omitempty"`
+
+	//Limits 请求大小限制，未设置时使用服务器的全局限制(流式处理的路由只使用路由的限制)
+	Limits
+
+	err error
 }
 
 //NewRouter 构建路径配置
@@ -67,6 +84,11 @@ func NewRouter(path string, service string, action []string, opts ...Option) *Ro
 	return r
 }
 
+//GetError 获取构建路由时选项返回的错误
+func (r *Router) GetError() error {
+	return r.err
+}
+
 //GetEncoding 获取encoding配置，未配置时返回utf-8
 func (r *Router) GetEncoding() string {
 	if r.Encoding != "" {
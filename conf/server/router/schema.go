@@ -0,0 +1,323 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/micro-plat/lib4go/types"
+)
+
+//字段类型
+const (
+	FieldString  = "string"
+	FieldInteger = "integer"
+	FieldNumber  = "number"
+	FieldBoolean = "boolean"
+	FieldArray   = "array"
+	FieldObject  = "object"
+)
+
+//Field 请求参数字段声明
+type Field struct {
+	//Name 参数名称
+	Name string `json:"name" toml:"name"`
+
+	//Label 参数显示名称，用于错误提示与接口文档
+	Label string `json:"label,omitempty" toml:"label,omitempty"`
+
+	//Type 参数类型(string,integer,number,boolean,array,object)
+	Type string `json:"type,omitempty" toml:"type,omitempty"`
+
+	//Required 是否必须
+	Required bool `json:"required,omitempty" toml:"required,omitempty"`
+
+	//Rule govalidator验证规则，如email,length(1|32),in(a|b)
+	Rule string `json:"rule,omitempty" toml:"rule,omitempty"`
+
+	//Pattern 参数需匹配的正则表达式，单独验证，不放入Rule中
+	Pattern string `json:"pattern,omitempty" toml:"pattern,omitempty"`
+
+	//Minimum 数字参数的最小值
+	Minimum *float64 `json:"minimum,omitempty" toml:"minimum,omitempty"`
+
+	//Maximum 数字参数的最大值
+	Maximum *float64 `json:"maximum,omitempty" toml:"maximum,omitempty"`
+
+	//Desc 参数说明
+	Desc string `json:"desc,omitempty" toml:"desc,omitempty"`
+}
+
+//GetLabel 获取参数显示名称，未设置时返回参数名称
+func (f *Field) GetLabel() string {
+	if f.Label == "" {
+		return f.Name
+	}
+	return f.Label
+}
+
+//Schema 服务的请求参数声明，在执行服务前自动验证，并可用于生成接口文档
+type Schema []*Field
+
+//FieldError 参数验证失败信息
+type FieldError struct {
+	Name    string `json:"name" xml:"name"`
+	Label   string `json:"label" xml:"label"`
+	Message string `json:"message" xml:"message"`
+}
+
+//FieldErrors 参数验证失败列表
+type FieldErrors []*FieldError
+
+func (e FieldErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, f := range e {
+		msgs = append(msgs, fmt.Sprintf("%s(%s):%s", f.Label, f.Name, f.Message))
+	}
+	return strings.Join(msgs, ";")
+}
+
+//Validate 验证输入参数，返回所有验证失败的字段
+func (s Schema) Validate(input map[string]interface{}) FieldErrors {
+	var result FieldErrors
+	for _, f := range s {
+		if msg := f.validate(input[f.Name]); msg != "" {
+			result = append(result, &FieldError{Name: f.Name, Label: f.GetLabel(), Message: msg})
+		}
+	}
+	return result
+}
+
+func (f *Field) validate(v interface{}) string {
+	//1. 检查是否为空
+	if v == nil || v == "" {
+		if f.Required {
+			return "不能为空"
+		}
+		return ""
+	}
+
+	//2. 检查参数类型与取值范围
+	value := fmt.Sprint(v)
+	switch f.Type {
+	case FieldInteger, FieldNumber:
+		value = types.GetString(v)
+		n, err := strconv.ParseFloat(value, 64)
+		if f.Type == FieldInteger && (err != nil || n != float64(int64(n))) {
+			return "必须是整数"
+		}
+		if err != nil {
+			return "必须是数字"
+		}
+		if f.Minimum != nil && n < *f.Minimum {
+			return fmt.Sprintf("不能小于%v", *f.Minimum)
+		}
+		if f.Maximum != nil && n > *f.Maximum {
+			return fmt.Sprintf("不能大于%v", *f.Maximum)
+		}
+	case FieldBoolean:
+		if _, err := strconv.ParseBool(types.GetString(v)); err != nil {
+			return "必须是true或false"
+		}
+	case FieldArray:
+		if k := reflect.ValueOf(v).Kind(); k != reflect.Slice && k != reflect.Array {
+			return "必须是数组"
+		}
+		return ""
+	case FieldObject:
+		if reflect.ValueOf(v).Kind() != reflect.Map {
+			return "必须是对象"
+		}
+		return ""
+	default:
+		if s, ok := v.(string); ok {
+			value = s
+		}
+	}
+
+	//3. 使用正则表达式与验证规则检查参数值
+	if f.Pattern != "" {
+		if ok, err := regexp.MatchString(f.Pattern, value); err != nil || !ok {
+			return "格式不正确"
+		}
+	}
+	if f.Rule == "" {
+		return ""
+	}
+	if _, err := govalidator.ValidateMap(map[string]interface{}{f.Name: value}, map[string]interface{}{f.Name: f.Rule}); err != nil {
+		if errs, ok := err.(govalidator.Errors); ok && len(errs) > 0 {
+			err = errs[0]
+		}
+		if e, ok := err.(govalidator.Error); ok {
+			return e.Err.Error()
+		}
+		return err.Error()
+	}
+	return ""
+}
+
+//NewSchema 根据结构体的json,label,valid标签构建参数声明，valid中包含required时为必须参数
+func NewSchema(obj interface{}) (Schema, error) {
+	t := reflect.TypeOf(obj)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("参数声明必须是结构体:%v", obj)
+	}
+	schema := make(Schema, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			sub, err := NewSchema(reflect.New(sf.Type).Interface())
+			if err != nil {
+				return nil, err
+			}
+			schema = append(schema, sub...)
+			continue
+		}
+		if sf.PkgPath != "" {
+			continue
+		}
+		name := strings.Split(sf.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		f := &Field{Name: name, Label: sf.Tag.Get("label"), Type: getFieldType(sf.Type), Desc: sf.Tag.Get("desc")}
+		rules := make([]string, 0, 1)
+		var err error
+		for _, r := range strings.Split(sf.Tag.Get("valid"), ",") {
+			switch r = strings.TrimSpace(r); r {
+			case "":
+			case "required":
+				f.Required = true
+			default:
+				if strings.HasPrefix(r, "matches(") && strings.HasSuffix(r, ")") {
+					if f.Pattern, err = getPattern(r[len("matches(") : len(r)-1]); err != nil {
+						return nil, fmt.Errorf("%s的验证规则有误:%w", name, err)
+					}
+					continue
+				}
+				rules = append(rules, r)
+			}
+		}
+		f.Rule = strings.Join(rules, ",")
+		schema = append(schema, f)
+	}
+	return schema, nil
+}
+
+func getFieldType(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return FieldInteger
+	case reflect.Float32, reflect.Float64:
+		return FieldNumber
+	case reflect.Bool:
+		return FieldBoolean
+	case reflect.Slice, reflect.Array:
+		return FieldArray
+	case reflect.Map, reflect.Struct:
+		return FieldObject
+	default:
+		return FieldString
+	}
+}
+
+//jsonSchema JSON-Schema文档中支持的属性
+type jsonSchema struct {
+	Type       string                 `json:"type"`
+	Title      string                 `json:"title"`
+	Desc       string                 `json:"description"`
+	Format     string                 `json:"format"`
+	Pattern    string                 `json:"pattern"`
+	Enum       []interface{}          `json:"enum"`
+	MinLength  *int                   `json:"minLength"`
+	MaxLength  *int                   `json:"maxLength"`
+	Minimum    *float64               `json:"minimum"`
+	Maximum    *float64               `json:"maximum"`
+	Required   []string               `json:"required"`
+	Properties map[string]*jsonSchema `json:"properties"`
+}
+
+//NewJSONSchema 根据JSON-Schema文档(type为object)构建参数声明，支持title,description,type,format,
+//pattern,enum,minLength,maxLength,minimum,maximum与required，pattern使用正则表达式单独验证
+func NewJSONSchema(doc string) (Schema, error) {
+	js := &jsonSchema{}
+	if err := json.Unmarshal([]byte(doc), js); err != nil {
+		return nil, fmt.Errorf("JSON-Schema格式有误:%w", err)
+	}
+	if js.Type != "" && js.Type != FieldObject {
+		return nil, fmt.Errorf("JSON-Schema的type必须是object:%s", js.Type)
+	}
+	required := make(map[string]bool)
+	for _, r := range js.Required {
+		required[r] = true
+	}
+	names := make([]string, 0, len(js.Properties))
+	for name := range js.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	schema := make(Schema, 0, len(names))
+	for _, name := range names {
+		p := js.Properties[name]
+		f := &Field{Name: name, Label: p.Title, Type: p.Type, Desc: p.Desc, Required: required[name], Minimum: p.Minimum, Maximum: p.Maximum}
+		rules := make([]string, 0, 1)
+		switch p.Format {
+		case "email", "url", "ipv4", "ipv6", "uuid":
+			rules = append(rules, p.Format)
+		case "date-time":
+			rules = append(rules, "rfc3339")
+		}
+		if p.MinLength != nil || p.MaxLength != nil {
+			rules = append(rules, fmt.Sprintf("runelength(%d|%d)", getInt(p.MinLength, 0), getInt(p.MaxLength, 1<<31-1)))
+		}
+		pattern, err := getPattern(p.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%s的pattern有误:%w", name, err)
+		}
+		f.Pattern = pattern
+		if len(p.Enum) > 0 {
+			enum := make([]string, 0, len(p.Enum))
+			for _, e := range p.Enum {
+				enum = append(enum, fmt.Sprint(e))
+			}
+			rules = append(rules, fmt.Sprintf("in(%s)", strings.Join(enum, "|")))
+		}
+		f.Rule = strings.Join(rules, ",")
+		schema = append(schema, f)
+	}
+	return schema, nil
+}
+
+//getPattern 检查正则表达式是否有效，正则表达式中可包含govalidator规则的分隔符(,|))
+func getPattern(pattern string) (string, error) {
+	if pattern == "" {
+		return "", nil
+	}
+	if _, err := regexp.Compile(pattern); err != nil {
+		return "", err
+	}
+	return pattern, nil
+}
+
+func getInt(v *int, def int) int {
+	if v == nil {
+		return def
+	}
+	return *v
+}
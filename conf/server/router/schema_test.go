@@ -0,0 +1,91 @@
+package router
+
+import (
+	"testing"
+
+	"github.com/micro-plat/lib4go/assert"
+)
+
+type orderInput struct {
+	OrderNo string  `json:"order_no" label:"订单编号" valid:"required,length(4|32)"`
+	Amount  float64 `json:"amount" label:"金额" valid:"required,range(1|10000)"`
+	Count   int     `json:"count" label:"数量"`
+	Email   string  `json:"email" valid:"email"`
+	Ignore  string  `json:"-"`
+}
+
+func TestNewSchema(t *testing.T) {
+	s, err := NewSchema(&orderInput{})
+	assert.Equal(t, nil, err, "构建参数声明")
+	assert.Equal(t, 4, len(s), "字段个数")
+	assert.Equal(t, &Field{Name: "order_no", Label: "订单编号", Type: FieldString, Required: true, Rule: "length(4|32)"}, s[0], "字符串字段")
+	assert.Equal(t, FieldNumber, s[1].Type, "数字字段")
+	assert.Equal(t, FieldInteger, s[2].Type, "整数字段")
+	assert.Equal(t, "email", s[3].GetLabel(), "未设置label")
+
+	_, err = NewSchema("abc")
+	assert.NotEqual(t, nil, err, "非结构体")
+}
+
+func TestSchema_Validate(t *testing.T) {
+	s, _ := NewSchema(orderInput{})
+	errs := s.Validate(map[string]interface{}{"order_no": "2020010101", "amount": 100.5, "count": "3"})
+	assert.Equal(t, 0, len(errs), "验证通过")
+
+	errs = s.Validate(map[string]interface{}{"order_no": "01", "count": "a", "email": "abc"})
+	assert.Equal(t, 4, len(errs), "返回所有失败的字段")
+	assert.Equal(t, "order_no", errs[0].Name, "长度不符")
+	assert.Equal(t, "金额", errs[1].Label, "必须字段")
+	assert.Equal(t, "不能为空", errs[1].Message, "必须字段")
+	assert.Equal(t, "必须是整数", errs[2].Message, "类型不符")
+	assert.Equal(t, "email", errs[3].Name, "格式不符")
+
+	errs = s.Validate(map[string]interface{}{"order_no": "2020010101", "amount": "0.5"})
+	assert.Equal(t, 1, len(errs), "超出范围")
+}
+
+func TestNewJSONSchema(t *testing.T) {
+	s, err := NewJSONSchema(`{
+		"type":"object",
+		"required":["name"],
+		"properties":{
+			"name":{"type":"string","title":"名称","minLength":2,"maxLength":8},
+			"age":{"type":"integer","minimum":1,"maximum":150},
+			"level":{"type":"string","enum":["a","b"]}
+		}}`)
+	assert.Equal(t, nil, err, "构建参数声明")
+	assert.Equal(t, 3, len(s), "字段个数")
+	assert.Equal(t, "age", s[0].Name, "按名称排序")
+	assert.Equal(t, "in(a|b)", s[1].Rule, "枚举")
+	assert.Equal(t, true, s[2].Required, "必须字段")
+
+	errs := s.Validate(map[string]interface{}{"name": "hydra", "age": 20, "level": "a"})
+	assert.Equal(t, 0, len(errs), "验证通过")
+	errs = s.Validate(map[string]interface{}{"name": "h", "age": 200, "level": "c"})
+	assert.Equal(t, 3, len(errs), "验证失败")
+
+	_, err = NewJSONSchema(`{"type":"array"}`)
+	assert.NotEqual(t, nil, err, "非object")
+}
+
+func TestNewJSONSchema_Pattern(t *testing.T) {
+	s, err := NewJSONSchema(`{"properties":{"code":{"type":"string","pattern":"^(a|b){1,3}$"}}}`)
+	assert.Equal(t, nil, err, "构建参数声明")
+	assert.Equal(t, "^(a|b){1,3}$", s[0].Pattern, "正则表达式单独验证")
+	assert.Equal(t, "", s[0].Rule, "不放入验证规则")
+	assert.Equal(t, 0, len(s.Validate(map[string]interface{}{"code": "abb"})), "匹配")
+	assert.Equal(t, 1, len(s.Validate(map[string]interface{}{"code": "abc"})), "不匹配")
+
+	_, err = NewJSONSchema(`{"properties":{"code":{"type":"string","pattern":"^(a"}}}`)
+	assert.NotEqual(t, nil, err, "正则表达式有误")
+}
+
+func TestWithSchema_Error(t *testing.T) {
+	r := NewRouter("/order", "/order", DefMethods, WithJSONSchema(`{"type":"array"}`))
+	assert.NotEqual(t, nil, r.GetError(), "声明有误时不panic")
+	assert.Equal(t, 0, len(r.Schema), "未添加参数声明")
+
+	r = NewRouter("/order", "/order", DefMethods, WithSchema(orderInput{}))
+	assert.Equal(t, nil, r.GetError(), "声明正确")
+	assert.Equal(t, 4, len(r.Schema), "添加参数声明")
+}
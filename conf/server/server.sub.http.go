@@ -10,28 +10,40 @@ import (
 	"github.com/micro-plat/hydra/conf/server/auth/apikey"
 	"github.com/micro-plat/hydra/conf/server/auth/basic"
 	"github.com/micro-plat/hydra/conf/server/auth/jwt"
+	"github.com/micro-plat/hydra/conf/server/auth/oidc"
 	"github.com/micro-plat/hydra/conf/server/auth/ras"
+	"github.com/micro-plat/hydra/conf/server/auth/rbac"
+	"github.com/micro-plat/hydra/conf/server/compress"
 	"github.com/micro-plat/hydra/conf/server/header"
+	"github.com/micro-plat/hydra/conf/server/idempotent"
 	"github.com/micro-plat/hydra/conf/server/metric"
 	"github.com/micro-plat/hydra/conf/server/render"
 	"github.com/micro-plat/hydra/conf/server/static"
+	tlsconf "github.com/micro-plat/hydra/conf/server/tls"
+	"github.com/micro-plat/hydra/conf/server/ws"
 )
 
 type HttpSub struct {
-	cnf       conf.IServerConf
-	header    *Loader
-	jwt       *Loader
-	metric    *Loader
-	static    *Loader
-	apikey    *Loader
-	ras       *Loader
-	basic     *Loader
-	render    *Loader
-	whiteList *Loader
-	blackList *Loader
-	limit     *Loader
-	proxy     *Loader
-	apm       *Loader
+	cnf        conf.IServerConf
+	header     *Loader
+	jwt        *Loader
+	metric     *Loader
+	static     *Loader
+	apikey     *Loader
+	ras        *Loader
+	basic      *Loader
+	render     *Loader
+	whiteList  *Loader
+	blackList  *Loader
+	limit      *Loader
+	proxy      *Loader
+	apm        *Loader
+	oidc       *Loader
+	rbac       *Loader
+	wsConn     *Loader
+	tls        *Loader
+	compress   *Loader
+	idempotent *Loader
 }
 
 func NewHttpSub(cnf conf.IServerConf) *HttpSub {
@@ -49,6 +61,12 @@ func NewHttpSub(cnf conf.IServerConf) *HttpSub {
 	s.limit = GetLoader(cnf, s.getLimiterFunc())
 	s.proxy = GetLoader(cnf, s.getProxyFunc())
 	s.apm = GetLoader(cnf, s.getAPMFunc())
+	s.oidc = GetLoader(cnf, s.getOIDCFunc())
+	s.rbac = GetLoader(cnf, s.getRBACFunc())
+	s.wsConn = GetLoader(cnf, s.getWSConnFunc())
+	s.tls = GetLoader(cnf, s.getTLSFunc())
+	s.compress = GetLoader(cnf, s.getCompressFunc())
+	s.idempotent = GetLoader(cnf, s.getIdempotentFunc())
 	return s
 }
 
@@ -143,6 +161,48 @@ func (s HttpSub) getAPMFunc() func(cnf conf.IServerConf) (interface{}, error) {
 	}
 }
 
+//getOIDCFunc 获取oidc配置信息
+func (s HttpSub) getOIDCFunc() func(cnf conf.IServerConf) (interface{}, error) {
+	return func(cnf conf.IServerConf) (interface{}, error) {
+		return oidc.GetConf(cnf)
+	}
+}
+
+//getRBACFunc 获取rbac配置信息
+func (s HttpSub) getRBACFunc() func(cnf conf.IServerConf) (interface{}, error) {
+	return func(cnf conf.IServerConf) (interface{}, error) {
+		return rbac.GetConf(cnf)
+	}
+}
+
+//getWSConnFunc 获取websocket连接配置信息
+func (s HttpSub) getWSConnFunc() func(cnf conf.IServerConf) (interface{}, error) {
+	return func(cnf conf.IServerConf) (interface{}, error) {
+		return ws.GetConnConf(cnf)
+	}
+}
+
+//getTLSFunc 获取tls配置信息
+func (s HttpSub) getTLSFunc() func(cnf conf.IServerConf) (interface{}, error) {
+	return func(cnf conf.IServerConf) (interface{}, error) {
+		return tlsconf.GetConf(cnf)
+	}
+}
+
+//getCompressFunc 获取响应压缩配置信息
+func (s HttpSub) getCompressFunc() func(cnf conf.IServerConf) (interface{}, error) {
+	return func(cnf conf.IServerConf) (interface{}, error) {
+		return compress.GetConf(cnf)
+	}
+}
+
+//getIdempotentFunc 获取幂等请求配置信息
+func (s HttpSub) getIdempotentFunc() func(cnf conf.IServerConf) (interface{}, error) {
+	return func(cnf conf.IServerConf) (interface{}, error) {
+		return idempotent.GetConf(cnf)
+	}
+}
+
 //GetHeaderConf 获取响应头配置
 func (s *HttpSub) GetHeaderConf() (header.Headers, error) {
 	headerObj, err := s.header.GetConf()
@@ -264,3 +324,57 @@ func (s *HttpSub) GetAPMConf() (*apm.APM, error) {
 	}
 	return apmc.(*apm.APM), nil
 }
+
+//GetOIDCConf 获取oidc认证配置
+func (s *HttpSub) GetOIDCConf() (*oidc.OIDC, error) {
+	oidcObj, err := s.oidc.GetConf()
+	if err != nil {
+		return nil, err
+	}
+	return oidcObj.(*oidc.OIDC), nil
+}
+
+//GetRBACConf 获取访问控制配置
+func (s *HttpSub) GetRBACConf() (*rbac.RBAC, error) {
+	rbacObj, err := s.rbac.GetConf()
+	if err != nil {
+		return nil, err
+	}
+	return rbacObj.(*rbac.RBAC), nil
+}
+
+//GetWSConnConf 获取websocket连接配置
+func (s *HttpSub) GetWSConnConf() (*ws.Conn, error) {
+	connObj, err := s.wsConn.GetConf()
+	if err != nil {
+		return nil, err
+	}
+	return connObj.(*ws.Conn), nil
+}
+
+//GetTLSConf 获取tls配置
+func (s *HttpSub) GetTLSConf() (*tlsconf.TLS, error) {
+	tlsObj, err := s.tls.GetConf()
+	if err != nil {
+		return nil, err
+	}
+	return tlsObj.(*tlsconf.TLS), nil
+}
+
+//GetCompressConf 获取响应压缩配置
+func (s *HttpSub) GetCompressConf() (*compress.Compress, error) {
+	compressObj, err := s.compress.GetConf()
+	if err != nil {
+		return nil, err
+	}
+	return compressObj.(*compress.Compress), nil
+}
+
+//GetIdempotentConf 获取幂等请求配置
+func (s *HttpSub) GetIdempotentConf() (*idempotent.Idempotent, error) {
+	idempotentObj, err := s.idempotent.GetConf()
+	if err != nil {
+		return nil, err
+	}
+	return idempotentObj.(*idempotent.Idempotent), nil
+}
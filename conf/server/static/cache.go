@@ -0,0 +1,42 @@
+package static
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+//GetCacheControl 获取文件的缓存策略，fpath为文件完整路径
+func (s *Static) GetCacheControl(fpath string) string {
+	if len(s.Caches) == 0 {
+		return ""
+	}
+	rel, err := filepath.Rel(filepath.Clean(s.Dir), filepath.Clean(fpath))
+	if err != nil {
+		rel = fpath
+	}
+	rel = "/" + strings.TrimPrefix(filepath.ToSlash(rel), "/")
+
+	//文件路径
+	if v, ok := s.Caches[rel]; ok {
+		return v
+	}
+
+	//最长前缀
+	prefix, value := "", ""
+	for k, v := range s.Caches {
+		if strings.HasSuffix(k, "/") && strings.HasPrefix(rel, k) && len(k) > len(prefix) {
+			prefix, value = k, v
+		}
+	}
+	if prefix != "" {
+		return value
+	}
+
+	//扩展名
+	if ext := filepath.Ext(rel); ext != "" {
+		if v, ok := s.Caches[ext]; ok {
+			return v
+		}
+	}
+	return s.Caches["*"]
+}
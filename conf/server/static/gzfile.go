@@ -1,12 +1,20 @@
 package static
 
 import (
+	"crypto/sha1"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"strings"
 )
 
+const (
+	gzSuffix = ".gz"
+	brSuffix = ".br"
+)
+
 //GetGzFile 获取gz 压缩包
 func (s *Static) GetGzFile(rPath string) string {
 
@@ -20,6 +28,25 @@ func (s *Static) GetGzFile(rPath string) string {
 	return fi.GzFile
 }
 
+//GetBrFile 获取br压缩文件，需预先生成与原文件同名的.br文件，服务器不实时进行brotli压缩
+func (s *Static) GetBrFile(rPath string) string {
+	fi, ok := s.FileMap[rPath]
+	if !ok || !fi.HasBr {
+		return ""
+	}
+	return fi.BrFile
+}
+
+//GetETag 获取扫描时根据文件内容计算的ETag，内容相同的文件在各节点与重新部署后ETag不变。
+//文件未扫描或扫描后已修改(修改时间或大小不一致)时根据当前的修改时间与大小生成弱ETag
+func (s *Static) GetETag(rPath string, finfo os.FileInfo) string {
+	fi, ok := s.FileMap[rPath]
+	if ok && fi.ETag != "" && fi.ModTime == finfo.ModTime().UnixNano() && fi.Size == finfo.Size() {
+		return fi.ETag
+	}
+	return fmt.Sprintf(`W/"%x-%x"`, finfo.ModTime().UnixNano(), finfo.Size())
+}
+
 //RereshData 刷新配置数据
 func (s *Static) RereshData() {
 	s.recursiveDir(strings.TrimPrefix(s.Dir, "./"))
@@ -32,7 +59,6 @@ func (s *Static) GetFileMap() map[string]FileInfo {
 
 func (s *Static) recursiveDir(dir string) {
 	children := []string{}
-	const suffix = ".gz"
 	list, err := ioutil.ReadDir(dir)
 	if err != nil {
 		return
@@ -41,13 +67,7 @@ func (s *Static) recursiveDir(dir string) {
 	for i := range list {
 		cur = list[i]
 		if !cur.IsDir() {
-			if strings.HasSuffix(cur.Name(), suffix) {
-				fpath := fmt.Sprintf("%s/%s", dir, strings.TrimSuffix(cur.Name(), suffix))
-				s.FileMap[fpath] = FileInfo{
-					HasGz:  true,
-					GzFile: fpath + suffix,
-				}
-			}
+			s.addFile(dir, cur.Name())
 			continue
 		}
 		children = append(children, fmt.Sprintf("%s/%s", dir, cur.Name()))
@@ -58,3 +78,46 @@ func (s *Static) recursiveDir(dir string) {
 	}
 	return
 }
+
+//addFile 记录预先生成的gz、br压缩文件与文件内容的ETag
+func (s *Static) addFile(dir string, name string) {
+	fpath := fmt.Sprintf("%s/%s", dir, name)
+	switch {
+	case strings.HasSuffix(name, gzSuffix):
+		fpath = strings.TrimSuffix(fpath, gzSuffix)
+		fi := s.FileMap[fpath]
+		fi.HasGz, fi.GzFile = true, fpath+gzSuffix
+		s.FileMap[fpath] = fi
+	case strings.HasSuffix(name, brSuffix):
+		fpath = strings.TrimSuffix(fpath, brSuffix)
+		fi := s.FileMap[fpath]
+		fi.HasBr, fi.BrFile = true, fpath+brSuffix
+		s.FileMap[fpath] = fi
+	default:
+		finfo, err := os.Stat(fpath)
+		if err != nil {
+			return
+		}
+		etag, err := getFileHash(fpath)
+		if err != nil {
+			return
+		}
+		fi := s.FileMap[fpath]
+		fi.ETag, fi.ModTime, fi.Size = etag, finfo.ModTime().UnixNano(), finfo.Size()
+		s.FileMap[fpath] = fi
+	}
+}
+
+//getFileHash 根据文件内容计算ETag
+func getFileHash(fpath string) (string, error) {
+	f, err := os.Open(fpath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha1.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(`"%s"`, hex.EncodeToString(h.Sum(nil))[:20]), nil
+}
@@ -113,6 +113,17 @@ func WithPrefix(prefix string) Option {
 	}
 }
 
+//WithCache 设置缓存策略，pattern为扩展名(.js)、路径前缀(/assets/)、文件路径(/index.html)或*，
+//cacheControl为Cache-Control响应头，如public, max-age=31536000, immutable
+func WithCache(pattern string, cacheControl string) Option {
+	return func(s *Static) {
+		if s.Caches == nil {
+			s.Caches = make(map[string]string)
+		}
+		s.Caches[pattern] = cacheControl
+	}
+}
+
 //WithDisable 禁用配置
 func WithDisable() Option {
 	return func(a *Static) {
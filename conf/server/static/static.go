@@ -38,14 +38,20 @@ type Static struct {
 	HomePage       string              `json:"homePage ,omitempty" valid:"ascii" toml:"homePage,omitempty" label:"静态文件首页"`
 	Rewriters      []string            `json:"rewriters,omitempty" valid:"ascii" toml:"rewriters,omitempty" label:"静态文件重写规则"`
 	Disable        bool                `json:"disable,omitempty" toml:"disable,omitempty"`
+	Caches         map[string]string   `json:"caches,omitempty" toml:"caches,omitempty"` //缓存策略，键为扩展名(.js)、路径前缀(/assets/)、文件路径(/index.html)或*，按文件路径、最长前缀、扩展名、*的顺序匹配
 	FileMap        map[string]FileInfo `json:"-"`
 	RewritersMatch *conf.PathMatch     `json:"-"`
 }
 
-//FileInfo 压缩文件保存
+//FileInfo 预先生成的gz、br压缩文件，扫描时根据文件内容计算的ETag及文件的修改时间与大小
 type FileInfo struct {
-	GzFile string
-	HasGz  bool
+	GzFile  string
+	HasGz   bool
+	BrFile  string
+	HasBr   bool
+	ETag    string
+	ModTime int64
+	Size    int64
 }
 
 //New 构建静态文件配置信息
@@ -0,0 +1,78 @@
+package static
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func TestStatic_FileMap(t *testing.T) {
+	dir, err := ioutil.TempDir("", "static")
+	assert.Equal(t, nil, err, "创建临时目录")
+	defer os.RemoveAll(dir)
+	os.MkdirAll(filepath.Join(dir, "assets"), 0755)
+	ioutil.WriteFile(filepath.Join(dir, "index.html"), []byte("<html></html>"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "assets", "app.js"), []byte("var a=1;"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "assets", "app.js.gz"), []byte("gz"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "assets", "app.js.br"), []byte("br"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "assets", "copy.js"), []byte("var a=1;"), 0644)
+
+	s := New(WithRoot(dir))
+	js := dir + "/assets/app.js"
+	assert.Equal(t, js+".gz", s.GetGzFile(js), "gz文件")
+	assert.Equal(t, js+".br", s.GetBrFile(js), "br文件")
+	assert.Equal(t, "", s.GetBrFile(dir+"/index.html"), "无br文件")
+}
+
+func TestStatic_GetETag(t *testing.T) {
+	dir, err := ioutil.TempDir("", "static")
+	assert.Equal(t, nil, err, "创建临时目录")
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(filepath.Join(dir, "app.js"), []byte("var a=1;"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "copy.js"), []byte("var a=1;"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "index.html"), []byte("<html></html>"), 0644)
+
+	s := New(WithRoot(dir))
+	js, copyjs := dir+"/app.js", dir+"/copy.js"
+	finfo, _ := os.Stat(js)
+	cinfo, _ := os.Stat(copyjs)
+	hinfo, _ := os.Stat(dir + "/index.html")
+	etag := s.GetETag(js, finfo)
+	assert.Equal(t, 22, len(etag), "根据内容计算ETag")
+	assert.Equal(t, etag, s.GetETag(copyjs, cinfo), "内容相同ETag相同")
+	assert.NotEqual(t, etag, s.GetETag(dir+"/index.html", hinfo), "内容不同ETag不同")
+
+	//扫描后修改文件，使用修改时间与大小生成弱ETag
+	ioutil.WriteFile(js, []byte("var a=12;"), 0644)
+	os.Chtimes(js, time.Now(), finfo.ModTime().Add(time.Second))
+	finfo, _ = os.Stat(js)
+	assert.Equal(t, "W/", s.GetETag(js, finfo)[:2], "文件已修改")
+	assert.NotEqual(t, etag, s.GetETag(js, finfo), "文件已修改")
+}
+
+func TestStatic_GetCacheControl(t *testing.T) {
+	s := New(WithRoot("./static"),
+		WithCache(".js", "public, max-age=3600"),
+		WithCache("/assets/", "public, max-age=31536000, immutable"),
+		WithCache("/assets/lib/", "public, max-age=600"),
+		WithCache("/index.html", "no-cache"),
+		WithCache("*", "public, max-age=60"))
+	tests := []struct {
+		fpath string
+		want  string
+	}{
+		{fpath: "static/index.html", want: "no-cache"},
+		{fpath: "static/assets/app.js", want: "public, max-age=31536000, immutable"},
+		{fpath: "static/assets/lib/vue.js", want: "public, max-age=600"},
+		{fpath: "static/js/main.js", want: "public, max-age=3600"},
+		{fpath: "static/favicon.ico", want: "public, max-age=60"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, s.GetCacheControl(tt.fpath), tt.fpath)
+	}
+	assert.Equal(t, "", New().GetCacheControl("static/index.html"), "未配置缓存策略")
+}
@@ -16,3 +16,17 @@ func WithEnable() Option {
 		a.Disable = false
 	}
 }
+
+//WithOverlap 设置上次执行未结束时的处理策略(allow,skip,queue)
+func WithOverlap(policy string) Option {
+	return func(a *Task) {
+		a.Overlap = policy
+	}
+}
+
+//WithCatchUp 设置服务重启后补偿执行的最大次数，需启用执行历史
+func WithCatchUp(n int) Option {
+	return func(a *Task) {
+		a.CatchUp = n
+	}
+}
@@ -13,11 +13,24 @@ const CronExecuteImmediately = "@immediately"
 //CronExecuteNow 立即执行
 const CronExecuteNow = "@now"
 
+const (
+	//OverlapAllow 上次执行未结束时允许并行执行
+	OverlapAllow = "allow"
+
+	//OverlapSkip 上次执行未结束时跳过本次执行
+	OverlapSkip = "skip"
+
+	//OverlapQueue 上次执行未结束时等待其结束后再执行(最多排队一次)
+	OverlapQueue = "queue"
+)
+
 //Task cron任务的task明细
 type Task struct {
 	Cron    string `json:"cron,omitempty" valid:"ascii,required" toml:"cron,omitempty" label:"任务名称"`
 	Service string `json:"service,omitempty" valid:"spath,required" toml:"service,omitempty" label:"任务服务"`
 	Disable bool   `json:"disable,omitempty" toml:"disable,omitempty"`
+	Overlap string `json:"overlap,omitempty" valid:"in(allow|skip|queue)" toml:"overlap,omitempty" label:"重叠执行策略"`
+	CatchUp int    `json:"catchup,omitempty" toml:"catchup,omitempty" label:"补偿执行次数"`
 }
 
 //NewTask 创建任务信息
@@ -38,6 +51,14 @@ func (t *Task) GetUNQ() string {
 	return md5.Encrypt(fmt.Sprintf("%s(%s)", t.Service, t.Cron))
 }
 
+//GetOverlap 获取重叠执行策略，未配置时允许并行执行
+func (t *Task) GetOverlap() string {
+	if t.Overlap == "" {
+		return OverlapAllow
+	}
+	return t.Overlap
+}
+
 //IsImmediately 是否立即
 func (t *Task) IsImmediately() bool {
 	return t.Cron == CronExecuteNow || t.Cron == CronExecuteImmediately
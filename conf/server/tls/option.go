@@ -0,0 +1,45 @@
+package tls
+
+//Option 配置选项
+type Option func(*TLS)
+
+//WithConfigName 使用注册中心中的证书配置(/platName/var/tls/name)
+func WithConfigName(name string) Option {
+	return func(t *TLS) {
+		t.ConfigName = name
+	}
+}
+
+//WithClientAuth 验证客户端证书，caFile为空时使用注册中心证书配置中的ca
+func WithClientAuth(require bool, caFile ...string) Option {
+	return func(t *TLS) {
+		t.ClientAuth = ClientAuthRequest
+		if require {
+			t.ClientAuth = ClientAuthRequire
+		}
+		if len(caFile) > 0 {
+			t.ClientCAFile = caFile[0]
+		}
+	}
+}
+
+//WithMinVersion 设置最低TLS版本(1.0,1.1,1.2,1.3)
+func WithMinVersion(v string) Option {
+	return func(t *TLS) {
+		t.MinVersion = v
+	}
+}
+
+//WithDisable 禁用tls
+func WithDisable() Option {
+	return func(t *TLS) {
+		t.Disable = true
+	}
+}
+
+//WithEnable 启用tls
+func WithEnable() Option {
+	return func(t *TLS) {
+		t.Disable = false
+	}
+}
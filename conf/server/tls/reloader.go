@@ -0,0 +1,86 @@
+package tls
+
+import (
+	xtls "crypto/tls"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/micro-plat/hydra/conf"
+)
+
+//reloadInterval 证书重新加载间隔，用于发现已轮换的证书文件或注册中心证书
+var reloadInterval = time.Minute
+
+//Getter 获取当前的tls配置与var配置
+type Getter func() (*TLS, conf.IVarConf, error)
+
+//reloader 证书动态加载器，配置变更或达到加载间隔时重新加载证书，加载失败时继续使用原证书
+type reloader struct {
+	get        Getter
+	nextProtos []string
+	current    *TLS
+	config     *xtls.Config
+	loadTime   time.Time
+	lock       sync.Mutex
+}
+
+//NewServerConfig 使用当前配置加载证书并构建服务器tls配置，之后每次握手时通过getter获取最新配置，配置变更或证书轮换后自动生效
+func NewServerConfig(t *TLS, varConf conf.IVarConf, get Getter, nextProtos ...string) (*xtls.Config, error) {
+	r := &reloader{get: get, nextProtos: nextProtos}
+	if _, err := r.load(t, varConf); err != nil {
+		return nil, err
+	}
+	return &xtls.Config{
+		NextProtos: nextProtos,
+		GetConfigForClient: func(*xtls.ClientHelloInfo) (*xtls.Config, error) {
+			return r.getConfig()
+		},
+		GetCertificate: func(*xtls.ClientHelloInfo) (*xtls.Certificate, error) {
+			cfg, err := r.getConfig()
+			if err != nil {
+				return nil, err
+			}
+			return &cfg.Certificates[0], nil
+		},
+	}, nil
+}
+
+func (r *reloader) getConfig() (*xtls.Config, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	t, varConf, err := r.get()
+	if err != nil || !t.IsEnabled() {
+		return r.keep(err)
+	}
+	if t == r.current && time.Since(r.loadTime) < reloadInterval {
+		return r.config, nil
+	}
+	cfg, err := r.load(t, varConf)
+	if err != nil {
+		return r.keep(err)
+	}
+	return cfg, nil
+}
+
+func (r *reloader) load(t *TLS, varConf conf.IVarConf) (*xtls.Config, error) {
+	r.loadTime = time.Now()
+	cfg, err := t.Load(varConf)
+	if err != nil {
+		return nil, err
+	}
+	cfg.NextProtos = r.nextProtos
+	r.current, r.config = t, cfg
+	return cfg, nil
+}
+
+//keep 获取新证书失败时继续使用原证书
+func (r *reloader) keep(err error) (*xtls.Config, error) {
+	if r.config != nil {
+		return r.config, nil
+	}
+	if err == nil {
+		err = fmt.Errorf("tls未启用或未配置证书")
+	}
+	return nil, err
+}
@@ -0,0 +1,168 @@
+package tls
+
+import (
+	xtls "crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/micro-plat/hydra/conf"
+)
+
+//TypeNodeName tls配置节点名
+const TypeNodeName = "tls"
+
+//VarTypeNode 证书在var配置中的类型名称，内容为{"cert":"","key":"","ca":""}(pem格式)
+const VarTypeNode = "tls"
+
+const (
+	//ClientAuthNone 不验证客户端证书
+	ClientAuthNone = "none"
+
+	//ClientAuthRequest 客户端提供证书时进行验证
+	ClientAuthRequest = "request"
+
+	//ClientAuthRequire 客户端必须提供有效证书
+	ClientAuthRequire = "require"
+)
+
+var versions = map[string]uint16{
+	"1.0": xtls.VersionTLS10,
+	"1.1": xtls.VersionTLS11,
+	"1.2": xtls.VersionTLS12,
+	"1.3": xtls.VersionTLS13,
+}
+
+//TLS 服务器证书配置，证书可来自本地文件或注册中心var配置
+type TLS struct {
+	Disable bool `json:"disable,omitempty" toml:"disable,omitempty"`
+
+	//CertFile 证书文件
+	CertFile string `json:"certFile,omitempty" toml:"certFile,omitempty"`
+
+	//KeyFile 私钥文件
+	KeyFile string `json:"keyFile,omitempty" toml:"keyFile,omitempty"`
+
+	//ConfigName 注册中心中的证书配置名(/platName/var/tls/configName)，未指定证书文件时使用
+	ConfigName string `json:"configName,omitempty" toml:"configName,omitempty"`
+
+	//ClientAuth 客户端证书验证方式
+	ClientAuth string `json:"clientAuth,omitempty" valid:"in(none|request|require)" toml:"clientAuth,omitempty"`
+
+	//ClientCAFile 验证客户端证书的CA文件，未指定时使用ConfigName中的ca
+	ClientCAFile string `json:"clientCAFile,omitempty" toml:"clientCAFile,omitempty"`
+
+	//MinVersion 最低TLS版本
+	MinVersion string `json:"minVersion,omitempty" valid:"in(1.0|1.1|1.2|1.3)" toml:"minVersion,omitempty"`
+}
+
+//New 构建tls配置，证书文件为空时需通过WithConfigName指定注册中心中的证书
+func New(certFile string, keyFile string, opts ...Option) *TLS {
+	t := &TLS{CertFile: certFile, KeyFile: keyFile}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+//IsEnabled 是否启用tls
+func (t *TLS) IsEnabled() bool {
+	return !t.Disable && (t.CertFile != "" || t.ConfigName != "")
+}
+
+//GetClientAuth 获取客户端证书验证方式
+func (t *TLS) GetClientAuth() xtls.ClientAuthType {
+	switch t.ClientAuth {
+	case ClientAuthRequest:
+		return xtls.VerifyClientCertIfGiven
+	case ClientAuthRequire:
+		return xtls.RequireAndVerifyClientCert
+	default:
+		return xtls.NoClientCert
+	}
+}
+
+//GetMinVersion 获取最低TLS版本，默认1.2
+func (t *TLS) GetMinVersion() uint16 {
+	if v, ok := versions[t.MinVersion]; ok {
+		return v
+	}
+	return xtls.VersionTLS12
+}
+
+//Load 加载证书并构建tls配置
+func (t *TLS) Load(varConf conf.IVarConf) (*xtls.Config, error) {
+	certPEM, keyPEM, caPEM, err := t.read(varConf)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := xtls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("证书加载失败:%w", err)
+	}
+	cfg := &xtls.Config{
+		Certificates: []xtls.Certificate{cert},
+		MinVersion:   t.GetMinVersion(),
+		ClientAuth:   t.GetClientAuth(),
+	}
+	if cfg.ClientAuth == xtls.NoClientCert {
+		return cfg, nil
+	}
+	if len(caPEM) == 0 {
+		return nil, fmt.Errorf("验证客户端证书时必须指定ca证书")
+	}
+	cfg.ClientCAs = x509.NewCertPool()
+	if !cfg.ClientCAs.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("ca证书格式有误")
+	}
+	return cfg, nil
+}
+
+//read 读取证书、私钥与ca证书内容，优先使用本地文件
+func (t *TLS) read(varConf conf.IVarConf) (cert []byte, key []byte, ca []byte, err error) {
+	if t.ConfigName != "" {
+		if varConf == nil {
+			return nil, nil, nil, fmt.Errorf("未获取到var配置，无法加载证书%s", t.ConfigName)
+		}
+		raw, err := varConf.GetConf(VarTypeNode, t.ConfigName)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("证书配置(/var/%s/%s)获取失败:%w", VarTypeNode, t.ConfigName, err)
+		}
+		cert, key, ca = []byte(raw.GetString("cert")), []byte(raw.GetString("key")), []byte(raw.GetString("ca"))
+	}
+	if t.CertFile != "" {
+		if cert, err = ioutil.ReadFile(t.CertFile); err != nil {
+			return nil, nil, nil, fmt.Errorf("证书文件(%s)读取失败:%w", t.CertFile, err)
+		}
+		if key, err = ioutil.ReadFile(t.KeyFile); err != nil {
+			return nil, nil, nil, fmt.Errorf("私钥文件(%s)读取失败:%w", t.KeyFile, err)
+		}
+	}
+	if t.ClientCAFile != "" {
+		if ca, err = ioutil.ReadFile(t.ClientCAFile); err != nil {
+			return nil, nil, nil, fmt.Errorf("ca证书文件(%s)读取失败:%w", t.ClientCAFile, err)
+		}
+	}
+	return cert, key, ca, nil
+}
+
+//GetConf 获取tls配置，未配置时不启用
+func GetConf(cnf conf.IServerConf) (*TLS, error) {
+	t := TLS{}
+	_, err := cnf.GetSubObject(TypeNodeName, &t)
+	if errors.Is(err, conf.ErrNoSetting) {
+		return &TLS{Disable: true}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("tls配置格式有误:%v", err)
+	}
+	if b, err := govalidator.ValidateStruct(&t); !b {
+		return nil, fmt.Errorf("tls配置数据有误:%v", err)
+	}
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		return nil, fmt.Errorf("tls配置数据有误:证书文件与私钥文件须同时指定")
+	}
+	return &t, nil
+}
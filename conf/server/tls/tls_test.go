@@ -0,0 +1,116 @@
+package tls
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	xtls "crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/micro-plat/hydra/conf"
+	"github.com/micro-plat/lib4go/assert"
+)
+
+//writeCert 生成自签名证书并写入文件
+func writeCert(t *testing.T, dir string, cn string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Equal(t, nil, err, "生成私钥")
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	assert.Equal(t, nil, err, "生成证书")
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.Equal(t, nil, err, "序列化私钥")
+	certFile, keyFile := filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key")
+	ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600)
+	return certFile, keyFile
+}
+
+func getCN(t *testing.T, cfg *xtls.Config) string {
+	cert, err := x509.ParseCertificate(cfg.Certificates[0].Certificate[0])
+	assert.Equal(t, nil, err, "解析证书")
+	return cert.Subject.CommonName
+}
+
+func TestTLS_Load(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "tls")
+	defer os.RemoveAll(dir)
+	certFile, keyFile := writeCert(t, dir, "server")
+
+	assert.Equal(t, false, (&TLS{}).IsEnabled(), "未配置证书")
+	assert.Equal(t, false, New(certFile, keyFile, WithDisable()).IsEnabled(), "禁用tls")
+	assert.Equal(t, true, New("", "", WithConfigName("api")).IsEnabled(), "使用注册中心证书")
+
+	c := New(certFile, keyFile)
+	cfg, err := c.Load(nil)
+	assert.Equal(t, nil, err, "加载证书")
+	assert.Equal(t, "server", getCN(t, cfg), "证书内容")
+	assert.Equal(t, uint16(xtls.VersionTLS12), cfg.MinVersion, "默认tls版本")
+	assert.Equal(t, xtls.NoClientCert, cfg.ClientAuth, "默认不验证客户端证书")
+
+	//验证客户端证书须指定ca
+	_, err = New(certFile, keyFile, WithClientAuth(true)).Load(nil)
+	assert.NotEqual(t, nil, err, "未指定ca")
+	cfg, err = New(certFile, keyFile, WithClientAuth(true, certFile), WithMinVersion("1.3")).Load(nil)
+	assert.Equal(t, nil, err, "指定ca")
+	assert.Equal(t, xtls.RequireAndVerifyClientCert, cfg.ClientAuth, "必须提供客户端证书")
+	assert.Equal(t, uint16(xtls.VersionTLS13), cfg.MinVersion, "tls版本")
+
+	//注册中心证书须提供var配置
+	_, err = New("", "", WithConfigName("api")).Load(nil)
+	assert.NotEqual(t, nil, err, "无var配置")
+}
+
+func TestNewServerConfig(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "tls")
+	defer os.RemoveAll(dir)
+	certFile, keyFile := writeCert(t, dir, "v1")
+
+	current := New(certFile, keyFile)
+	get := func() (*TLS, conf.IVarConf, error) { return current, nil, nil }
+	cfg, err := NewServerConfig(current, nil, get, "h2")
+	assert.Equal(t, nil, err, "构建服务器配置")
+
+	c, err := cfg.GetConfigForClient(nil)
+	assert.Equal(t, nil, err, "获取证书")
+	assert.Equal(t, "v1", getCN(t, c), "初始证书")
+	assert.Equal(t, []string{"h2"}, c.NextProtos, "协议")
+
+	//证书轮换后达到加载间隔时重新加载
+	writeCert(t, dir, "v2")
+	c, _ = cfg.GetConfigForClient(nil)
+	assert.Equal(t, "v1", getCN(t, c), "未达到加载间隔")
+	reloadInterval = 0
+	defer func() { reloadInterval = time.Minute }()
+	c, _ = cfg.GetConfigForClient(nil)
+	assert.Equal(t, "v2", getCN(t, c), "证书已轮换")
+
+	//新证书加载失败时继续使用原证书
+	os.Remove(certFile)
+	c, err = cfg.GetConfigForClient(nil)
+	assert.Equal(t, nil, err, "加载失败")
+	assert.Equal(t, "v2", getCN(t, c), "使用原证书")
+
+	//配置变更时立即加载
+	certFile, keyFile = writeCert(t, dir, "v3")
+	reloadInterval = time.Minute
+	current = New(certFile, keyFile)
+	c, _ = cfg.GetConfigForClient(nil)
+	assert.Equal(t, "v3", getCN(t, c), "配置变更")
+}
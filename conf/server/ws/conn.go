@@ -0,0 +1,151 @@
+package ws
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/micro-plat/hydra/conf"
+	"github.com/micro-plat/hydra/conf/server/header"
+)
+
+//TypeNodeName websocket连接配置节点名
+const TypeNodeName = "websocket"
+
+const (
+	defMaxMessageSize  = 64 * 1024
+	defReadTimeout     = 60
+	defWriteTimeout    = 10
+	defReadBufferSize  = 1024
+	defWriteBufferSize = 1024
+	defSendBuffer      = 256
+)
+
+//Conn websocket连接配置
+type Conn struct {
+
+	//MaxMessageSize 客户端消息最大字节数，超过时断开连接
+	MaxMessageSize int64 `json:"maxMessageSize,omitempty" valid:"range(0|1073741824)" toml:"maxMessageSize,omitempty"`
+
+	//ReadTimeout 等待客户端消息或pong的超时时长(秒)，ping间隔为该时长的9/10
+	ReadTimeout int `json:"readTimeout,omitempty" valid:"range(0|86400)" toml:"readTimeout,omitempty"`
+
+	//WriteTimeout 向客户端写入消息的超时时长(秒)
+	WriteTimeout int `json:"writeTimeout,omitempty" valid:"range(0|3600)" toml:"writeTimeout,omitempty"`
+
+	//ReadBufferSize 读缓冲区字节数
+	ReadBufferSize int `json:"readBufferSize,omitempty" toml:"readBufferSize,omitempty"`
+
+	//WriteBufferSize 写缓冲区字节数
+	WriteBufferSize int `json:"writeBufferSize,omitempty" toml:"writeBufferSize,omitempty"`
+
+	//SendBuffer 每个连接待发送消息的队列长度
+	SendBuffer int `json:"sendBuffer,omitempty" toml:"sendBuffer,omitempty"`
+
+	//CheckOrigin 是否检查连接来源，未启用且未设置AllowedOrigins时允许所有来源
+	CheckOrigin bool `json:"checkOrigin,omitempty" toml:"checkOrigin,omitempty"`
+
+	//AllowedOrigins 允许连接的来源，设置后检查连接来源。未设置且启用CheckOrigin时使用header中的跨域配置，未配置跨域时只允许同源连接；均未设置时允许所有来源
+	AllowedOrigins []string `json:"allowedOrigins,omitempty" toml:"allowedOrigins,omitempty"`
+
+	//Subprotocols 服务器支持的子协议，按客户端请求的顺序选择第一个支持的子协议
+	Subprotocols []string `json:"subprotocols,omitempty" toml:"subprotocols,omitempty"`
+
+	//Compression 是否启用permessage-deflate压缩
+	Compression bool `json:"compression,omitempty" toml:"compression,omitempty"`
+}
+
+//NewConn 构建websocket连接配置
+func NewConn(opts ...ConnOption) *Conn {
+	c := &Conn{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+//GetMaxMessageSize 获取客户端消息最大字节数
+func (c *Conn) GetMaxMessageSize() int64 {
+	if c.MaxMessageSize <= 0 {
+		return defMaxMessageSize
+	}
+	return c.MaxMessageSize
+}
+
+//GetReadTimeout 获取等待客户端消息的超时时长
+func (c *Conn) GetReadTimeout() time.Duration {
+	if c.ReadTimeout <= 0 {
+		return defReadTimeout * time.Second
+	}
+	return time.Duration(c.ReadTimeout) * time.Second
+}
+
+//GetPingPeriod 获取ping间隔时长，须小于ReadTimeout
+func (c *Conn) GetPingPeriod() time.Duration {
+	return c.GetReadTimeout() * 9 / 10
+}
+
+//GetWriteTimeout 获取写入超时时长
+func (c *Conn) GetWriteTimeout() time.Duration {
+	if c.WriteTimeout <= 0 {
+		return defWriteTimeout * time.Second
+	}
+	return time.Duration(c.WriteTimeout) * time.Second
+}
+
+//GetBufferSize 获取读写缓冲区字节数
+func (c *Conn) GetBufferSize() (read int, write int) {
+	read, write = c.ReadBufferSize, c.WriteBufferSize
+	if read <= 0 {
+		read = defReadBufferSize
+	}
+	if write <= 0 {
+		write = defWriteBufferSize
+	}
+	return read, write
+}
+
+//GetSendBuffer 获取待发送消息的队列长度
+func (c *Conn) GetSendBuffer() int {
+	if c.SendBuffer <= 0 {
+		return defSendBuffer
+	}
+	return c.SendBuffer
+}
+
+//AllowOrigin 检查是否允许来源连接，未启用来源检查时允许所有来源，未携带origin的非浏览器客户端允许连接
+func (c *Conn) AllowOrigin(origin string, host string, h header.Headers) bool {
+	if origin == "" || !c.CheckOrigin && len(c.AllowedOrigins) == 0 {
+		return true
+	}
+	if len(c.AllowedOrigins) > 0 {
+		for _, o := range c.AllowedOrigins {
+			if o == "*" || strings.EqualFold(o, origin) {
+				return true
+			}
+		}
+	} else if h.IsAllowOrigin(origin) {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, host)
+}
+
+//GetConnConf 获取websocket连接配置，未配置时使用默认值
+func GetConnConf(cnf conf.IServerConf) (*Conn, error) {
+	c := Conn{}
+	_, err := cnf.GetSubObject(TypeNodeName, &c)
+	if errors.Is(err, conf.ErrNoSetting) {
+		return NewConn(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("websocket配置格式有误:%v", err)
+	}
+	if b, err := govalidator.ValidateStruct(&c); !b {
+		return nil, fmt.Errorf("websocket配置数据有误:%v", err)
+	}
+	return &c, nil
+}
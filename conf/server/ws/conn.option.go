@@ -0,0 +1,62 @@
+package ws
+
+//ConnOption 连接配置选项
+type ConnOption func(*Conn)
+
+//WithMaxMessageSize 设置客户端消息最大字节数
+func WithMaxMessageSize(size int64) ConnOption {
+	return func(c *Conn) {
+		c.MaxMessageSize = size
+	}
+}
+
+//WithConnTimeout 设置等待客户端消息与写入消息的超时时长(秒)
+func WithConnTimeout(read int, write int) ConnOption {
+	return func(c *Conn) {
+		c.ReadTimeout = read
+		c.WriteTimeout = write
+	}
+}
+
+//WithBufferSize 设置读写缓冲区字节数
+func WithBufferSize(read int, write int) ConnOption {
+	return func(c *Conn) {
+		c.ReadBufferSize = read
+		c.WriteBufferSize = write
+	}
+}
+
+//WithSendBuffer 设置每个连接待发送消息的队列长度
+func WithSendBuffer(n int) ConnOption {
+	return func(c *Conn) {
+		c.SendBuffer = n
+	}
+}
+
+//WithCheckOrigin 启用连接来源检查，使用header中的跨域配置，未配置跨域时只允许同源连接
+func WithCheckOrigin() ConnOption {
+	return func(c *Conn) {
+		c.CheckOrigin = true
+	}
+}
+
+//WithAllowedOrigins 设置允许连接的来源并启用来源检查，*表示允许所有来源
+func WithAllowedOrigins(origins ...string) ConnOption {
+	return func(c *Conn) {
+		c.AllowedOrigins = append(c.AllowedOrigins, origins...)
+	}
+}
+
+//WithSubprotocols 设置服务器支持的子协议
+func WithSubprotocols(protocols ...string) ConnOption {
+	return func(c *Conn) {
+		c.Subprotocols = append(c.Subprotocols, protocols...)
+	}
+}
+
+//WithCompression 启用permessage-deflate压缩
+func WithCompression() ConnOption {
+	return func(c *Conn) {
+		c.Compression = true
+	}
+}
@@ -0,0 +1,51 @@
+package ws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/micro-plat/hydra/conf/server/header"
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func TestConn_Default(t *testing.T) {
+	c := NewConn()
+	assert.Equal(t, int64(64*1024), c.GetMaxMessageSize(), "默认消息大小")
+	assert.Equal(t, 60*time.Second, c.GetReadTimeout(), "默认读超时")
+	assert.Equal(t, 54*time.Second, c.GetPingPeriod(), "默认ping间隔")
+	assert.Equal(t, 10*time.Second, c.GetWriteTimeout(), "默认写超时")
+	assert.Equal(t, 256, c.GetSendBuffer(), "默认发送队列")
+
+	c = NewConn(WithMaxMessageSize(1024), WithConnTimeout(30, 5), WithBufferSize(4096, 2048), WithSendBuffer(16), WithCompression())
+	r, w := c.GetBufferSize()
+	assert.Equal(t, int64(1024), c.GetMaxMessageSize(), "消息大小")
+	assert.Equal(t, 27*time.Second, c.GetPingPeriod(), "ping间隔")
+	assert.Equal(t, 5*time.Second, c.GetWriteTimeout(), "写超时")
+	assert.Equal(t, []int{4096, 2048}, []int{r, w}, "缓冲区")
+	assert.Equal(t, 16, c.GetSendBuffer(), "发送队列")
+	assert.Equal(t, true, c.Compression, "启用压缩")
+}
+
+func TestConn_AllowOrigin(t *testing.T) {
+	cross := header.New(header.WithCrossDomain("http://a.com"))
+	tests := []struct {
+		name   string
+		conn   *Conn
+		h      header.Headers
+		origin string
+		want   bool
+	}{
+		{name: "默认允许所有来源", conn: NewConn(), origin: "http://a.com", want: true},
+		{name: "非浏览器客户端", conn: NewConn(WithCheckOrigin()), origin: "", want: true},
+		{name: "同源", conn: NewConn(WithCheckOrigin()), origin: "http://127.0.0.1:8070", want: true},
+		{name: "未配置跨域", conn: NewConn(WithCheckOrigin()), origin: "http://a.com", want: false},
+		{name: "使用header跨域配置", conn: NewConn(WithCheckOrigin()), h: cross, origin: "http://a.com", want: true},
+		{name: "不在header跨域配置中", conn: NewConn(WithCheckOrigin()), h: cross, origin: "http://b.com", want: false},
+		{name: "指定来源优先", conn: NewConn(WithAllowedOrigins("http://b.com")), h: cross, origin: "http://a.com", want: false},
+		{name: "指定来源", conn: NewConn(WithAllowedOrigins("http://b.com")), h: cross, origin: "http://b.com", want: true},
+		{name: "允许所有来源", conn: NewConn(WithAllowedOrigins("*")), origin: "http://c.com", want: true},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tt.conn.AllowOrigin(tt.origin, "127.0.0.1:8070", tt.h), tt.name)
+	}
+}
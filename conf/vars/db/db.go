@@ -1,5 +1,11 @@
 package db
 
+import (
+	"fmt"
+
+	"github.com/asaskevich/govalidator"
+)
+
 //TypeNodeName 分类节点名
 const TypeNodeName = "db"
 
@@ -26,3 +32,14 @@ func New(provider string, connString string, opts ...Option) *DB {
 	}
 	return db
 }
+
+//Validate 验证数据库连接配置
+func (d *DB) Validate() error {
+	if b, err := govalidator.ValidateStruct(d); !b && err != nil {
+		return fmt.Errorf("db配置有误:%v", err)
+	}
+	if d.MaxIdle > d.MaxOpen {
+		return fmt.Errorf("db配置有误:最大空闲连接数(%d)不能大于最大打开连接数(%d)", d.MaxIdle, d.MaxOpen)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/micro-plat/hydra/conf/vars/db"
+)
+
+//Postgres postgres数据库连接信息
+type Postgres = db.DB
+
+//New 构建postgres连接信息,支持url(postgres://)与key=value两种格式
+func New(connString string, opts ...db.Option) (*Postgres, error) {
+	if err := check(connString); err != nil {
+		return nil, fmt.Errorf("postgres连接串配置有误:%w", err)
+	}
+	d := db.New("postgres", connString, opts...)
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+//NewBy 构建postgres连接信息
+func NewBy(uName string, pwd string, serverIP string, dbName string, opts ...db.Option) (*Postgres, error) {
+	if uName == "" || serverIP == "" || dbName == "" {
+		return nil, fmt.Errorf("postgres连接信息配置有误:用户名,服务器地址,数据库名不能为空")
+	}
+	u := &url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(uName, pwd),
+		Host:     serverIP,
+		Path:     dbName,
+		RawQuery: "sslmode=disable",
+	}
+	return New(u.String(), opts...)
+}
+
+var sslModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+
+func check(connString string) error {
+	var host, dbName, sslMode string
+	if strings.HasPrefix(connString, "postgres://") || strings.HasPrefix(connString, "postgresql://") {
+		u, err := url.Parse(connString)
+		if err != nil {
+			return err
+		}
+		host, dbName, sslMode = u.Host, strings.Trim(u.Path, "/"), u.Query().Get("sslmode")
+	} else {
+		for _, kv := range strings.Fields(connString) {
+			p := strings.SplitN(kv, "=", 2)
+			if len(p) != 2 {
+				return fmt.Errorf("参数格式有误:%s", kv)
+			}
+			switch p[0] {
+			case "host":
+				host = p[1]
+			case "dbname":
+				dbName = p[1]
+			case "sslmode":
+				sslMode = p[1]
+			}
+		}
+	}
+	if host == "" || dbName == "" {
+		return fmt.Errorf("未指定服务器地址或数据库名:%s", connString)
+	}
+	if sslMode == "" {
+		return nil
+	}
+	for _, m := range sslModes {
+		if m == sslMode {
+			return nil
+		}
+	}
+	return fmt.Errorf("不支持的sslmode:%s", sslMode)
+}
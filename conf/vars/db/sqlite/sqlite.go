@@ -0,0 +1,61 @@
+package sqlite
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/micro-plat/hydra/conf/vars/db"
+)
+
+//SQLite sqlite数据库连接信息
+type SQLite = db.DB
+
+//New 构建sqlite连接信息,连接串为文件路径或file:格式的uri
+func New(connString string, opts ...db.Option) (*SQLite, error) {
+	if err := check(connString); err != nil {
+		return nil, fmt.Errorf("sqlite连接串配置有误:%w", err)
+	}
+	d := db.New("sqlite", connString, opts...)
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+//NewBy 构建sqlite连接信息,path为数据库文件路径,":memory:"表示内存数据库
+func NewBy(path string, opts ...db.Option) (*SQLite, error) {
+	if path == ":memory:" {
+		return New("file::memory:?cache=shared", opts...)
+	}
+	return New(fmt.Sprintf("file:%s?cache=shared&mode=rwc", path), opts...)
+}
+
+var modes = []string{"ro", "rw", "rwc", "memory"}
+
+func check(connString string) error {
+	path := connString
+	if strings.HasPrefix(connString, "file:") {
+		u, err := url.Parse(connString)
+		if err != nil {
+			return err
+		}
+		path = strings.TrimPrefix(strings.SplitN(connString, "?", 2)[0], "file:")
+		if mode := u.Query().Get("mode"); mode != "" && !contains(modes, mode) {
+			return fmt.Errorf("不支持的mode:%s", mode)
+		}
+	}
+	if strings.TrimSpace(path) == "" {
+		return fmt.Errorf("未指定数据库文件:%s", connString)
+	}
+	return nil
+}
+
+func contains(list []string, v string) bool {
+	for _, m := range list {
+		if m == v {
+			return true
+		}
+	}
+	return false
+}
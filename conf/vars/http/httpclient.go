@@ -14,6 +14,8 @@ type HTTPConf struct {
 	RequestTimeout    int      `json:"requestTimeout"`
 	Certs             []string `json:"certs"`
 	Ca                string   `json:"ca"`
+	Verify            bool     `json:"verify,omitempty"`
+	ServerName        string   `json:"serverName,omitempty"`
 	Proxy             string   `json:"proxy"`
 	Keepalive         bool     `json:"keepAlive"`
 	Trace             bool     `json:"trace"`
@@ -36,6 +36,16 @@ func WithCa(cafile string) Option {
 	}
 }
 
+//WithVerify 验证服务器证书(设置ca证书时自动验证)，serverName用于指定证书中的服务器名称
+func WithVerify(serverName ...string) Option {
+	return func(o *HTTPConf) {
+		o.Verify = true
+		if len(serverName) > 0 {
+			o.ServerName = serverName[0]
+		}
+	}
+}
+
 //WithProxy 使用代理地址
 func WithProxy(proxy string) Option {
 	return func(o *HTTPConf) {
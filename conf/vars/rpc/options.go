@@ -31,6 +31,20 @@ func WithTLS(tls []string) Option {
 	}
 }
 
+//WithCa 设置验证服务器证书的ca证书文件，设置后使用tls连接
+func WithCa(ca string) Option {
+	return func(o *RPCConf) {
+		o.Ca = ca
+	}
+}
+
+//WithServerName 设置服务器证书中的服务器名称，服务器证书未包含节点ip时使用
+func WithServerName(name string) Option {
+	return func(o *RPCConf) {
+		o.ServerName = name
+	}
+}
+
 //WithBalancer 配置为负载均衡器
 func WithBalancer(balancer string) Option {
 	return func(o *RPCConf) {
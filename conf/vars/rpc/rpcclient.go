@@ -18,9 +18,16 @@ type RPCConf struct {
 	Log          string   `json:"log"`
 	SortPrefix   string   `json:"sortPrefix"`
 	Tls          []string `json:"tls"`
+	Ca           string   `json:"ca,omitempty"`
+	ServerName   string   `json:"serverName,omitempty"`
 	Balancer     string   `json:"balancer"` //负载类型 localfirst:本地服务优先  round_robin:论寻负载
 }
 
+//IsTLS 是否使用tls连接(设置了客户端证书或ca证书)
+func (c *RPCConf) IsTLS() bool {
+	return len(c.Tls) == 2 || c.Ca != ""
+}
+
 //New 构建http 客户端配置信息
 func New(opts ...Option) *RPCConf {
 	rpcConf := &RPCConf{
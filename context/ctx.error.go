@@ -0,0 +1,223 @@
+package context
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+//ErrInvalidInput 输入参数验证失败的错误码
+const ErrInvalidInput = "INVALID_INPUT"
+
+func init() {
+	RegisterError(ErrInvalidInput, http.StatusBadRequest, "输入参数有误")
+	RegisterErrorMessage("en", ErrInvalidInput, "invalid input")
+}
+
+//errorCode 已注册的业务错误码
+type errorCode struct {
+	status   int
+	messages map[string]string
+}
+
+var errorCodes = map[string]*errorCode{}
+var errorLock sync.RWMutex
+
+//RegisterError 注册业务错误码，status为响应状态码，message为默认的错误消息，可包含fmt格式化参数
+func RegisterError(code string, status int, message string) {
+	errorLock.Lock()
+	defer errorLock.Unlock()
+	if c, ok := errorCodes[code]; ok {
+		c.status = status
+		c.messages[""] = message
+		return
+	}
+	errorCodes[code] = &errorCode{status: status, messages: map[string]string{"": message}}
+}
+
+//RegisterErrorMessage 注册错误码的多语言消息，lang为Accept-Language中的语言标识，如en,zh-CN
+func RegisterErrorMessage(lang string, code string, message string) {
+	errorLock.Lock()
+	defer errorLock.Unlock()
+	c, ok := errorCodes[code]
+	if !ok {
+		c = &errorCode{status: http.StatusBadRequest, messages: map[string]string{}}
+		errorCodes[code] = c
+	}
+	c.messages[strings.ToLower(lang)] = message
+}
+
+func getErrorCode(code string) (*errorCode, bool) {
+	errorLock.RLock()
+	defer errorLock.RUnlock()
+	c, ok := errorCodes[code]
+	return c, ok
+}
+
+//Error 结构化的业务错误，服务返回后按响应的Content-Type输出错误码、错误消息、详细信息与跟踪编号，
+//错误消息根据请求的Accept-Language从已注册的多语言消息中获取
+type Error struct {
+	code    string
+	status  int
+	args    []interface{}
+	details interface{}
+	cause   error
+}
+
+//NewError 根据错误码构建业务错误，args为错误消息的格式化参数，未注册的错误码使用400状态码，错误码作为错误消息
+func NewError(code string, args ...interface{}) *Error {
+	return &Error{code: code, args: args}
+}
+
+//WithStatus 设置响应状态码
+func (e *Error) WithStatus(status int) *Error {
+	e.status = status
+	return e
+}
+
+//WithDetails 设置错误详细信息，如验证失败的字段列表
+func (e *Error) WithDetails(details interface{}) *Error {
+	e.details = details
+	return e
+}
+
+//WithCause 设置引起错误的原始错误，只在调试模式下输出到响应中
+func (e *Error) WithCause(err error) *Error {
+	e.cause = err
+	return e
+}
+
+//GetErrorCode 获取业务错误码
+func (e *Error) GetErrorCode() string {
+	return e.code
+}
+
+//GetCode 获取响应状态码
+func (e *Error) GetCode() int {
+	if e.status != 0 {
+		return e.status
+	}
+	if c, ok := getErrorCode(e.code); ok && c.status != 0 {
+		return c.status
+	}
+	return http.StatusBadRequest
+}
+
+//GetMessage 根据Accept-Language获取错误消息，只有已注册且包含格式化参数的消息才使用args格式化
+func (e *Error) GetMessage(acceptLanguage string) string {
+	c, ok := getErrorCode(e.code)
+	if !ok {
+		return e.code
+	}
+	msg := c.getMessage(acceptLanguage)
+	if len(e.args) > 0 && strings.Contains(msg, "%") {
+		return fmt.Sprintf(msg, e.args...)
+	}
+	return msg
+}
+
+//GetError 获取错误信息
+func (e *Error) GetError() error {
+	return errors.New(e.Error())
+}
+
+//CanIgnore 是否可忽略
+func (e *Error) CanIgnore() bool {
+	return false
+}
+
+//Unwrap 获取原始错误
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+func (e *Error) Error() string {
+	if e.cause != nil {
+		return fmt.Sprintf("%s:%s(%v)", e.code, e.GetMessage(""), e.cause)
+	}
+	return fmt.Sprintf("%s:%s", e.code, e.GetMessage(""))
+}
+
+//GetResult 获取输出到响应的错误内容，debug为true时输出原始错误
+func (e *Error) GetResult(acceptLanguage string, traceID string, debug bool) *ErrorResult {
+	r := &ErrorResult{Code: e.code, Message: e.GetMessage(acceptLanguage), Details: e.details, TraceID: traceID}
+	if debug && e.cause != nil {
+		r.Debug = e.cause.Error()
+	}
+	return r
+}
+
+//ErrorResult 输出到响应的错误内容
+type ErrorResult struct {
+	Code    string      `json:"code" xml:"code" yaml:"code"`
+	Message string      `json:"message" xml:"message" yaml:"message"`
+	Details interface{} `json:"details,omitempty" xml:"details,omitempty" yaml:"details,omitempty"`
+	TraceID string      `json:"trace_id,omitempty" xml:"trace_id,omitempty" yaml:"trace_id,omitempty"`
+	Debug   string      `json:"debug,omitempty" xml:"debug,omitempty" yaml:"debug,omitempty"`
+}
+
+//String 以text/plain输出时的错误内容
+func (r *ErrorResult) String() string {
+	if r.Debug != "" {
+		return fmt.Sprintf("%s:%s(%s)", r.Code, r.Message, r.Debug)
+	}
+	return fmt.Sprintf("%s:%s", r.Code, r.Message)
+}
+
+//getMessage 按Accept-Language的语言权重获取消息，依次匹配完整语言标识与主语言，都未找到时使用默认消息
+func (c *errorCode) getMessage(acceptLanguage string) string {
+	for _, lang := range parseLanguages(acceptLanguage) {
+		if msg, ok := c.messages[lang]; ok {
+			return msg
+		}
+		if i := strings.Index(lang, "-"); i > 0 {
+			if msg, ok := c.messages[lang[:i]]; ok {
+				return msg
+			}
+		}
+	}
+	if msg, ok := c.messages[""]; ok {
+		return msg
+	}
+	for _, msg := range c.messages {
+		return msg
+	}
+	return ""
+}
+
+//parseLanguages 解析Accept-Language，按权重返回语言标识
+func parseLanguages(accept string) []string {
+	type language struct {
+		name string
+		q    float64
+	}
+	langs := make([]language, 0, 2)
+	for _, v := range strings.Split(accept, ",") {
+		parts := strings.Split(strings.TrimSpace(v), ";")
+		name := strings.ToLower(strings.TrimSpace(parts[0]))
+		if name == "" || name == "*" {
+			continue
+		}
+		q := 1.0
+		for _, p := range parts[1:] {
+			if p = strings.TrimSpace(p); strings.HasPrefix(p, "q=") {
+				if f, err := strconv.ParseFloat(strings.TrimPrefix(p, "q="), 64); err == nil {
+					q = f
+				}
+			}
+		}
+		if q > 0 {
+			langs = append(langs, language{name: name, q: q})
+		}
+	}
+	sort.SliceStable(langs, func(i, j int) bool { return langs[i].q > langs[j].q })
+	names := make([]string, 0, len(langs))
+	for _, l := range langs {
+		names = append(names, l.name)
+	}
+	return names
+}
@@ -2,6 +2,8 @@ package context
 
 import (
 	"context"
+	"crypto/x509"
+	"errors"
 	"io"
 	"net/http"
 	"net/url"
@@ -18,6 +20,15 @@ import (
 const (
 	UserName = "UserName"
 
+	//JWTClaims jwt验证通过后保存在Meta中的声明
+	JWTClaims = "JWTClaims"
+
+	//APIKeyID 静态密钥验证通过后保存在Meta中的密钥标识
+	APIKeyID = "APIKeyID"
+
+	//JWTReissue 需要重新签发jwt的标识，登录或刷新令牌后设置
+	JWTReissue = "JWTReissue"
+
 	XRequestID = "X-Request-Id"
 
 	JSONF  = "application/json; charset=%s"
@@ -31,10 +42,16 @@ const (
 	UTF8YAML  = "text/yaml; charset=utf-8"
 	UTF8HTML  = "text/html; charset=utf-8"
 	UTF8PLAIN = "text/plain; charset=utf-8"
+
+	//UTF8EventStream 事件流只支持utf-8编码
+	UTF8EventStream = "text/event-stream; charset=utf-8"
 )
 
 var EmptyReponseResult = &EmptyResult{}
 
+//ErrBodyTooLarge 请求内容或上传文件超过限制
+var ErrBodyTooLarge = errors.New("请求内容超过限制")
+
 type EmptyResult struct{}
 
 //Handler 业务处理Handler
@@ -129,6 +146,12 @@ type IPath interface {
 	AllowFallback() bool
 
 	GetEncoding() string
+
+	//IsStream 当前路由是否为流式处理
+	IsStream() bool
+
+	//GetLimits 获取请求大小限制，路由未设置时使用服务器的全局限制
+	GetLimits() router.Limits
 }
 
 //IVariable 参与变量
@@ -173,6 +196,9 @@ type IRequest interface {
 	//GetBody 获取请求的参数
 	GetBody() (body []byte, err error)
 
+	//GetBodyReader 获取请求body的读取流，流式路由直接读取原始请求流(只能读取一次)，否则读取已缓存的body
+	GetBodyReader() (io.ReadCloser, error)
+
 	//GetPlayload
 	GetPlayload() string
 
@@ -182,11 +208,27 @@ type IRequest interface {
 	//Cookies 获取cookie信息
 	Cookies() types.XMap
 
+	//GetRPCStream 获取rpc流式请求的数据流，非rpc流式请求时返回false
+	GetRPCStream() (IRPCStream, bool)
+
 	types.IXMap
 
 	IFile
 }
 
+//IRPCStream rpc流式请求的数据流，首个消息作为请求参数，其它消息通过Recv读取
+type IRPCStream interface {
+
+	//Recv 接收客户端发送的下一条消息，客户端发送完成时返回io.EOF
+	Recv() (string, error)
+
+	//Send 向客户端发送消息，字符串与[]byte直接发送，其它类型转换为json发送
+	Send(content interface{}) error
+
+	//Context 数据流的上下文，客户端断开或取消请求时结束
+	Context() context.Context
+}
+
 //IResponse 响应信息
 type IResponse interface {
 
@@ -255,8 +297,51 @@ type IResponse interface {
 	//Flush 将当前内容写入响应流(立即写入)
 	Flush()
 
+	//Encode 使用指定的内容编码(如gzip)对最终响应内容进行编码，编码后的内容在Flush时写入，
+	//编码后响应内容被修改时仍写入未编码的内容
+	Encode(contentEncoding string, encoder func([]byte) ([]byte, error)) error
+
+	//GetSize 获取响应内容的原始字节数与写入(编码后)的字节数
+	GetSize() (raw int, written int)
+
 	//GetHeaders 获取返回数据
 	GetHeaders() types.XMap
+
+	//Stream 以指定的状态码与Content-Type立即写入响应头，返回响应流，用于大文件下载等场景，
+	//返回后服务返回值不再写入响应，且不再受服务超时与服务器读写超时限制
+	Stream(status int, contentType string) (io.Writer, error)
+
+	//SSE 打开服务端推送事件流(Server-Sent Events)，heartbeat为心跳间隔，默认15秒，小于等于0时不发送心跳，
+	//打开后不再受服务超时与服务器写超时限制，客户端断开时ctx.Context()结束
+	SSE(heartbeat ...time.Duration) (IStream, error)
+
+	//IsStream 是否已打开响应流或事件流
+	IsStream() bool
+}
+
+//IStream 服务端推送事件流
+type IStream interface {
+
+	//Send 发送事件，id、event为空时不发送，data为非字符串时转换为json
+	Send(id string, event string, data interface{}) error
+
+	//Comment 发送注释，客户端会忽略注释内容，可用于保持连接
+	Comment(text string) error
+
+	//Retry 设置客户端断线重连间隔
+	Retry(d time.Duration) error
+
+	//Feed 将通道中的消息作为event事件持续发送，直到通道关闭或客户端断开
+	Feed(event string, ch <-chan string) error
+
+	//LastEventID 客户端断线重连时携带的最后事件编号
+	LastEventID() string
+
+	//Done 客户端断开或服务退出时关闭
+	Done() <-chan struct{}
+
+	//Count 已发送的事件数
+	Count() int
 }
 
 //IAuth 认证信息
@@ -272,6 +357,12 @@ type IAuth interface {
 
 	//Clear 清除用户登录信息
 	Clear()
+
+	//Revoke 吊销当前请求使用的jwt并清除用户登录信息
+	Revoke() error
+
+	//RevokeAll 吊销用户已签发的所有jwt(所有设备退出登录)，未指定用户时为当前用户
+	RevokeAll(user ...string) error
 }
 
 //IUser 用户相关信息
@@ -280,6 +371,9 @@ type IUser interface {
 	//GetUserName 获取用户名
 	GetUserName() string
 
+	//GetClientCert 获取已验证的客户端证书(双向tls认证启用后有效)
+	GetClientCert() *x509.Certificate
+
 	//GetClientIP 获取客户端请求IP
 	GetClientIP() string
 
@@ -344,6 +438,12 @@ type ITraceSpan interface {
 
 	//NewSpan 新的时间片
 	NewSpan(opertor string) ITraceSpan
+
+	//SetTag 设置标签
+	SetTag(key string, value string)
+
+	//SetError 记录错误信息，err为nil时不处理
+	SetError(err error)
 }
 
 //IEnd 关闭
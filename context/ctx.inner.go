@@ -1,6 +1,7 @@
 package context
 
 import (
+	"crypto/x509"
 	"io"
 	"net/http"
 	"net/url"
@@ -32,5 +33,7 @@ type IInnerContext interface {
 	GetService() string
 	GetFile(fileKey string) (string, io.ReadCloser, int64, error)
 	GetHTTPReqResp() (*http.Request, http.ResponseWriter)
+	GetPeerCertificates() []*x509.Certificate //客户端tls证书
+	GetRPCStream() IRPCStream                 //rpc流式请求的数据流
 	ClearAuth(c ...bool) bool
 }
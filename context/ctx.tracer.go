@@ -0,0 +1,24 @@
+package context
+
+//StartSpan 在当前请求的链路跟踪中创建并启动组件调用的子跨度，
+// 当前线程没有请求上下文或请求未被跟踪时返回空跨度
+func StartSpan(component string, operator string) ITraceSpan {
+	ctx, ok := GetContext()
+	if !ok || !ctx.Tracer().Available() {
+		return emptySpan{}
+	}
+	span := ctx.Tracer().NewSpan(operator)
+	span.Start()
+	span.SetTag("component", component)
+	return span
+}
+
+//emptySpan 未启用跟踪时使用的空跨度
+type emptySpan struct{}
+
+func (s emptySpan) End()                              {}
+func (s emptySpan) Available() bool                   { return false }
+func (s emptySpan) Start() IEnd                       { return s }
+func (s emptySpan) NewSpan(opertor string) ITraceSpan { return s }
+func (s emptySpan) SetTag(key string, value string)   {}
+func (s emptySpan) SetError(err error)                {}
@@ -2,22 +2,32 @@ package ctx
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
 
+	"github.com/micro-plat/hydra/components"
+	"github.com/micro-plat/hydra/conf"
+	"github.com/micro-plat/hydra/conf/app"
+	xjwt "github.com/micro-plat/hydra/conf/server/auth/jwt"
 	"github.com/micro-plat/hydra/context"
 	"github.com/micro-plat/lib4go/errs"
+	"github.com/micro-plat/lib4go/types"
 )
 
 type Auth struct {
 	request  interface{}
 	response interface{}
 	c        context.IInnerContext
+	meta     conf.IMeta
+	appConf  app.IAPPConf
 }
 
-func newAuth(c context.IInnerContext) *Auth {
+func newAuth(c context.IInnerContext, meta conf.IMeta, appConf app.IAPPConf) *Auth {
 	return &Auth{
-		c: c,
+		c:       c,
+		meta:    meta,
+		appConf: appConf,
 	}
 }
 
@@ -25,6 +35,7 @@ func newAuth(c context.IInnerContext) *Auth {
 func (c *Auth) Response(v ...interface{}) interface{} {
 	if len(v) > 0 {
 		c.response = v[0]
+		c.meta.SetValue(context.JWTReissue, true)
 	}
 	if c.response == nil {
 		return c.request
@@ -45,6 +56,75 @@ func (c *Auth) Clear() {
 	c.c.ClearAuth(true)
 }
 
+//Revoke 吊销当前请求使用的jwt并清除认证信息
+func (c *Auth) Revoke() error {
+	j, store, err := c.getJWTStore()
+	if err != nil {
+		return err
+	}
+	if claims, ok := c.getClaims(); ok {
+		if err := j.Revoke(claims, store); err != nil {
+			return err
+		}
+	}
+	c.Clear()
+	return nil
+}
+
+//RevokeAll 吊销用户已签发的所有jwt，未指定用户时为当前用户
+func (c *Auth) RevokeAll(user ...string) error {
+	j, store, err := c.getJWTStore()
+	if err != nil {
+		return err
+	}
+	current := ""
+	if claims, ok := c.getClaims(); ok {
+		current = types.GetString(claims["sub"])
+	}
+	uid := current
+	if len(user) > 0 {
+		uid = user[0]
+	}
+	if err := j.RevokeUser(uid, store); err != nil {
+		return err
+	}
+	if uid == current {
+		c.Clear()
+	}
+	return nil
+}
+
+func (c *Auth) getClaims() (map[string]interface{}, bool) {
+	claims, ok := c.meta.GetValue(context.JWTClaims).(map[string]interface{})
+	return claims, ok
+}
+
+func (c *Auth) getJWTStore() (*xjwt.JWTAuth, xjwt.IRevokeStore, error) {
+	j, err := c.appConf.GetJWTConf()
+	if err != nil {
+		return nil, nil, err
+	}
+	if j.Disable {
+		return nil, nil, errors.New("未启用jwt认证")
+	}
+	store, err := GetJWTStore(j)
+	if err != nil {
+		return nil, nil, err
+	}
+	if store == nil {
+		return nil, nil, errors.New("jwt未配置吊销信息缓存")
+	}
+	return j, store, nil
+}
+
+//GetJWTStore 获取jwt吊销信息存储，未配置缓存时返回nil
+func GetJWTStore(j *xjwt.JWTAuth) (xjwt.IRevokeStore, error) {
+	if j.Cache == "" {
+		return nil, nil
+	}
+	return components.Def.Cache().GetCache(j.Cache)
+}
+
 //Bind 绑定用户信息
 func (c *Auth) Bind(out interface{}) error {
 
@@ -3,13 +3,16 @@ package ctx
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/url"
 	"reflect"
 	"strings"
 
 	"github.com/micro-plat/hydra/context"
+	"github.com/micro-plat/hydra/pkgs/codec"
 	"github.com/micro-plat/lib4go/encoding"
 	"github.com/micro-plat/lib4go/types"
 	"gopkg.in/yaml.v3"
@@ -30,6 +33,7 @@ type valueReader struct {
 type body struct {
 	ctx      context.IInnerContext
 	encoding string
+	stream   bool
 	rawBody  valueReader
 	fullBody valueReader
 	mapBody  valueReader
@@ -62,11 +66,18 @@ func (w *body) GetMap() (data map[string]interface{}, err error) {
 	if strings.Contains(ctp, "__raw__") {
 		return w.ctx.GetRawForm(), nil
 	}
-	if body, _, w.mapBody.err = w.GetFullRaw(); w.mapBody.err != nil {
-		return nil, w.mapBody.err
-	}
-	if body, w.mapBody.err = urlDecode(body, w.encoding); w.mapBody.err != nil {
-		return nil, w.mapBody.err
+
+	//流式处理时不读取body，只处理url参数
+	if !w.stream {
+		if body, _, w.mapBody.err = w.GetFullRaw(); w.mapBody.err != nil {
+			return nil, w.mapBody.err
+		}
+		//二进制内容不进行字符编码转换
+		if !codec.IsBinary(ctp) {
+			if body, w.mapBody.err = urlDecode(body, w.encoding); w.mapBody.err != nil {
+				return nil, w.mapBody.err
+			}
+		}
 	}
 	//处理body数据
 	data = make(map[string]interface{})
@@ -98,6 +109,12 @@ func (w *body) GetMap() (data map[string]interface{}, err error) {
 				}
 				data[k] = types.BytesToString(buff)
 			}
+		case codec.IsBinary(ctp):
+			//无法解码为map的类型(如protobuf)只能通过Bind或GetBody读取
+			c, _ := codec.Get(ctp)
+			if err := c.Unmarshal(body, &data); err != nil && !errors.Is(err, codec.ErrUnsupportedType) {
+				w.mapBody.err = err
+			}
 		}
 	}
 	if w.mapBody.err != nil {
@@ -189,6 +206,28 @@ func (w *body) GetBody() (s []byte, err error) {
 
 }
 
+//GetBodyReader 获取请求body的读取流
+func (w *body) GetBodyReader() (io.ReadCloser, error) {
+	if !w.stream {
+		body, err := w.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		return ioutil.NopCloser(bytes.NewReader(body)), nil
+	}
+	if w.rawBody.hasRead {
+		return nil, fmt.Errorf("请求body已读取")
+	}
+	w.rawBody.hasRead = true
+	w.rawBody.err = fmt.Errorf("流式请求的body只能通过GetBodyReader读取一次")
+
+	//http请求直接读取原始请求流，不解析multipart表单
+	if req, _ := w.ctx.GetHTTPReqResp(); req != nil {
+		return req.Body, nil
+	}
+	return w.ctx.GetBody(), nil
+}
+
 func urlDecode(v []byte, c string) ([]byte, error) {
 	if strings.EqualFold(c, encoding.UTF8) {
 		return v, nil
@@ -0,0 +1,57 @@
+package ctx
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/micro-plat/hydra/context"
+	"github.com/micro-plat/lib4go/assert"
+)
+
+type innerStub struct {
+	context.IInnerContext
+	req *http.Request
+}
+
+func (s *innerStub) GetHTTPReqResp() (*http.Request, http.ResponseWriter) { return s.req, nil }
+func (s *innerStub) GetBody() io.ReadCloser                               { return s.req.Body }
+func (s *innerStub) ContentType() string                                  { return s.req.Header.Get("Content-Type") }
+func (s *innerStub) GetURL() *url.URL                                     { return s.req.URL }
+
+func TestBody_Stream(t *testing.T) {
+	newReq := func() *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/export?id=1", strings.NewReader(`{"name":"a"}`))
+		req.Header.Set("Content-Type", "application/json")
+		return req
+	}
+
+	//普通请求读取body到内存
+	b := NewBody(&innerStub{req: newReq()}, "utf-8")
+	m, err := b.GetMap()
+	assert.Equal(t, nil, err, "读取参数")
+	assert.Equal(t, map[string]interface{}{"id": "1", "name": "a"}, m, "合并body与url参数")
+	r, err := b.GetBodyReader()
+	assert.Equal(t, nil, err, "获取body读取流")
+	buff, _ := ioutil.ReadAll(r)
+	assert.Equal(t, `{"name":"a"}`, string(buff), "读取已缓存的body")
+
+	//流式请求只读取url参数
+	b = NewBody(&innerStub{req: newReq()}, "utf-8")
+	b.stream = true
+	m, err = b.GetMap()
+	assert.Equal(t, nil, err, "读取参数")
+	assert.Equal(t, map[string]interface{}{"id": "1"}, m, "只包含url参数")
+	r, err = b.GetBodyReader()
+	assert.Equal(t, nil, err, "获取body读取流")
+	buff, _ = ioutil.ReadAll(r)
+	assert.Equal(t, `{"name":"a"}`, string(buff), "读取原始请求流")
+	_, err = b.GetBodyReader()
+	assert.NotEqual(t, nil, err, "只能读取一次")
+	_, err = b.GetBody()
+	assert.NotEqual(t, nil, err, "不能再读取完整body")
+}
@@ -51,13 +51,31 @@ func NewCtx(c context.IInnerContext, tp string) *Ctx {
 	if err != nil {
 		panic(err)
 	}
-	ctx.user = NewUser(c, ctx.meta)
+	ctx.user = NewUser(c, ctx.meta, ctx.appConf)
 	context.Cache(ctx)
 	ctx.request = NewRequest(c, ctx.appConf, ctx.meta)
 	ctx.log = logger.GetSession(ctx.appConf.GetServerConf().GetServerName(), ctx.User().GetTraceID())
 	ctx.response = NewResponse(c, ctx.appConf, ctx.log, ctx.meta)
 	timeout := time.Duration(ctx.appConf.GetServerConf().GetMainConf().GetInt("", 30))
-	ctx.ctx, ctx.cancelFunc = r.WithTimeout(r.WithValue(r.Background(), "X-Request-Id", ctx.user.GetTraceID()), time.Second*timeout)
+
+	//rpc流式请求使用请求上下文，不受服务超时限制，客户端断开时结束。http请求打开事件流或响应流后关联请求上下文
+	parent := r.Background()
+	stream := c.GetRPCStream()
+	if stream != nil {
+		parent = stream.Context()
+	}
+	reqCtx := newReqContext(r.WithValue(parent, "X-Request-Id", ctx.user.GetTraceID()), time.Second*timeout)
+	ctx.ctx, ctx.cancelFunc = reqCtx, reqCtx.close
+	ctx.response.reqCtx = reqCtx
+	if stream != nil {
+		reqCtx.keepAlive(stream.Context())
+	}
+
+	//流式路由读取请求body与写入响应的时长不受服务超时与服务器读写超时限制，客户端断开时结束
+	if req, w := c.GetHTTPReqResp(); req != nil && ctx.request.Path().IsStream() {
+		reqCtx.keepAlive(req.Context())
+		liftDeadline(w)
+	}
 	ctx.tracer = newTracer(c.GetURL().Path, ctx.log, ctx.appConf)
 	return ctx
 }
@@ -122,6 +140,9 @@ func (c *Ctx) Invoke(service string) *pkgs.Rspns {
 func (c *Ctx) Close() {
 	context.Del() //从当前请求上下文中删除
 	c.appConf = nil
+	if c.response.stream != nil {
+		c.response.stream.close()
+	}
 	c.cancelFunc()
 	c.cancelFunc = nil
 	c.context = nil
@@ -3,6 +3,7 @@ package internal
 import (
 	r "context"
 	"sync"
+	"time"
 
 	"github.com/SkyAPM/go2sky"
 	"github.com/micro-plat/hydra/context"
@@ -12,9 +13,11 @@ import (
 type Span struct {
 	tracer    *go2sky.Tracer
 	span      go2sky.Span
+	spanType  go2sky.SpanType
 	ctx       r.Context
 	operator  string
 	subs      []*Span
+	lock      sync.Mutex
 	once      sync.Once
 	avaliable bool
 }
@@ -24,7 +27,7 @@ func New(ctx r.Context, tracer *go2sky.Tracer, operator string) *Span {
 	if tracer == nil {
 		return &Span{operator: operator, ctx: ctx}
 	}
-	span := &Span{ctx: ctx, operator: operator, avaliable: true, tracer: tracer, subs: make([]*Span, 0, 1)}
+	span := &Span{ctx: ctx, operator: operator, avaliable: true, tracer: tracer, spanType: go2sky.SpanTypeLocal, subs: make([]*Span, 0, 1)}
 	return span
 }
 
@@ -33,12 +36,11 @@ func (s *Span) Start() context.IEnd {
 	if !s.avaliable {
 		return s
 	}
-	var err error
-	s.span, s.ctx, err = s.tracer.CreateLocalSpan(s.ctx)
+	span, ctx, err := s.tracer.CreateLocalSpan(s.ctx, go2sky.WithOperationName(s.operator), go2sky.WithSpanType(s.spanType))
 	if err != nil {
 		return s
 	}
-	s.span.SetOperationName(s.operator)
+	s.span, s.ctx = span, ctx
 	return s
 }
 
@@ -47,6 +49,8 @@ func (s *Span) NewSpan(operator string) context.ITraceSpan {
 	if !s.avaliable {
 		return s
 	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
 	sub := New(s.ctx, s.tracer, operator)
 	s.subs = append(s.subs, sub)
 	return sub
@@ -57,15 +61,31 @@ func (s *Span) Available() bool {
 	return s.avaliable
 }
 
-//End 处理完成
+//SetTag 设置标签
+func (s *Span) SetTag(key string, value string) {
+	if s.span != nil {
+		s.span.Tag(go2sky.Tag(key), value)
+	}
+}
+
+//SetError 记录错误信息，err为nil时不处理
+func (s *Span) SetError(err error) {
+	if s.span != nil && err != nil {
+		s.span.Error(time.Now(), err.Error())
+	}
+}
+
+//End 处理完成，先结束所有子跨度再结束当前跨度
 func (s *Span) End() {
 	s.once.Do(func() {
+		s.lock.Lock()
+		subs := s.subs
+		s.lock.Unlock()
+		for _, v := range subs {
+			v.End()
+		}
 		if s.span != nil {
-			// fmt.Println("end:", s.operator)
 			s.span.End()
 		}
-		for _, v := range s.subs {
-			v.End()
-		}
 	})
 }
@@ -0,0 +1,54 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/SkyAPM/go2sky"
+	"github.com/micro-plat/lib4go/assert"
+)
+
+type testReporter struct {
+	spans chan []go2sky.ReportedSpan
+}
+
+func (r *testReporter) Boot(service string, serviceInstance string) {}
+func (r *testReporter) Send(spans []go2sky.ReportedSpan)            { r.spans <- spans }
+func (r *testReporter) Close()                                      {}
+
+func TestSpan_NewSpan(t *testing.T) {
+	reporter := &testReporter{spans: make(chan []go2sky.ReportedSpan, 1)}
+	tracer, err := go2sky.NewTracer("hydra", go2sky.WithReporter(reporter))
+	assert.Equal(t, nil, err, "创建跟踪器")
+
+	root := New(context.Background(), tracer, "/order/query")
+	root.spanType = go2sky.SpanTypeEntry
+	root.Start()
+	sub := root.NewSpan("db.query")
+	sub.Start()
+	sub.SetTag("db.type", "mysql")
+	sub.SetError(errors.New("timeout"))
+	root.SetTag("status_code", "200")
+	root.End()
+	root.End()
+
+	select {
+	case spans := <-reporter.spans:
+		assert.Equal(t, 2, len(spans), "上报根跨度与子跨度")
+		assert.Equal(t, "db.query", spans[0].OperationName(), "子跨度先结束")
+		assert.Equal(t, true, spans[0].IsError(), "子跨度错误")
+		assert.Equal(t, "mysql", spans[0].Tags()[0].Value, "子跨度标签")
+		assert.Equal(t, "/order/query", spans[1].OperationName(), "根跨度")
+		assert.Equal(t, "status_code", spans[1].Tags()[0].Key, "根跨度标签")
+	case <-time.After(time.Second):
+		t.Fatal("未上报跟踪数据")
+	}
+
+	empty := New(context.Background(), nil, "")
+	assert.Equal(t, false, empty.Available(), "空跨度")
+	assert.Equal(t, empty, empty.NewSpan("db.query"), "空跨度不创建子跨度")
+	empty.SetTag("db.type", "mysql")
+	empty.End()
+}
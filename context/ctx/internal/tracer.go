@@ -2,39 +2,55 @@ package internal
 
 import (
 	"context"
+	"fmt"
+	"sync"
+	"time"
 
 	"github.com/SkyAPM/go2sky"
 	"github.com/SkyAPM/go2sky/reporter"
 	"github.com/micro-plat/hydra/conf/app"
-	"github.com/micro-plat/lib4go/concurrent/cmap"
+	"github.com/micro-plat/hydra/conf/server/apm"
+	"github.com/micro-plat/hydra/global"
 )
 
 //Tracer 跟踪器
 type Tracer struct {
-	reporter go2sky.Reporter
 	*Span
 }
 
-var tracers = cmap.New(8)
+//serverTracer 服务器的跟踪器及其上报组件
+type serverTracer struct {
+	address  string
+	tracer   *go2sky.Tracer
+	reporter go2sky.Reporter
+}
+
+var tracers = map[string]*serverTracer{}
+var tracerLock sync.RWMutex
+
+var newReporter = func(address string) (go2sky.Reporter, error) {
+	return reporter.NewGRPCReporter(address, reporter.WithCheckInterval(time.Second))
+}
 
 //Empty 空跟踪器
 var Empty = &Tracer{Span: New(context.Background(), nil, "")}
 
-//GetTracer 创建跟踪器
-func GetTracer(service string, c app.IAPPConf) (*Tracer, error) {
+//GetTracer 创建请求的跟踪器，未启用APM或当前请求未被采样时返回空跟踪器
+func GetTracer(operator string, c app.IAPPConf) (*Tracer, error) {
 	conf, err := c.GetAPMConf()
-	if err != nil || conf.Disable {
-		return nil, err
+	if err == nil && conf.Disable {
+		CloseTracer(c.GetServerConf().GetServerPath())
 	}
-	reporter, err := reporter.NewGRPCReporter(conf.Address)
-	if reporter == nil || err != nil {
-		return &Tracer{Span: New(context.Background(), nil, service)}, err
+	if err != nil || conf.Disable || !conf.IsSampled() {
+		return Empty, err
 	}
-	tracer, err := go2sky.NewTracer(service, go2sky.WithReporter(reporter))
+	tracer, err := getTracer(c, conf)
 	if err != nil {
-		return &Tracer{Span: New(context.Background(), nil, service)}, err
+		return Empty, err
 	}
-	return &Tracer{reporter: reporter, Span: New(context.Background(), tracer, service)}, nil
+	root := New(context.Background(), tracer, operator)
+	root.spanType = go2sky.SpanTypeEntry
+	return &Tracer{Span: root}, nil
 }
 
 //Root 根节点
@@ -42,8 +58,52 @@ func (t *Tracer) Root() *Span {
 	return t.Span
 }
 
-//End 结束跟踪
-func (t *Tracer) End() {
-	t.reporter.Close()
-	t.Span.End()
+//getTracer 获取服务器的go2sky跟踪器，同一服务器共用一个跟踪器，APM地址变化时关闭原上报组件
+func getTracer(c app.IAPPConf, conf *apm.APM) (*go2sky.Tracer, error) {
+	s := c.GetServerConf()
+	key := s.GetServerPath()
+	tracerLock.RLock()
+	t, ok := tracers[key]
+	tracerLock.RUnlock()
+	if ok && t.address == conf.Address {
+		return t.tracer, nil
+	}
+
+	tracerLock.Lock()
+	defer tracerLock.Unlock()
+	if t, ok := tracers[key]; ok && t.address == conf.Address {
+		return t.tracer, nil
+	}
+	rpt, err := newReporter(conf.Address)
+	if err != nil {
+		return nil, fmt.Errorf("创建APM上报组件失败:%s %v", conf.Address, err)
+	}
+	service := fmt.Sprintf("%s_%s", s.GetPlatName(), s.GetSysName())
+	instance := fmt.Sprintf("%s-%s@%s", s.GetServerType(), s.GetClusterName(), global.LocalIP())
+	tracer, err := go2sky.NewTracer(service, go2sky.WithReporter(rpt), go2sky.WithInstance(instance))
+	if err != nil {
+		rpt.Close()
+		return nil, err
+	}
+	if old, ok := tracers[key]; ok {
+		old.reporter.Close()
+	}
+	tracers[key] = &serverTracer{address: conf.Address, tracer: tracer, reporter: rpt}
+	return tracer, nil
+}
+
+//CloseTracer 关闭服务器的跟踪器及上报组件，服务器关闭或禁用APM时调用
+func CloseTracer(serverPath string) {
+	tracerLock.RLock()
+	_, ok := tracers[serverPath]
+	tracerLock.RUnlock()
+	if !ok {
+		return
+	}
+	tracerLock.Lock()
+	defer tracerLock.Unlock()
+	if t, ok := tracers[serverPath]; ok {
+		t.reporter.Close()
+		delete(tracers, serverPath)
+	}
 }
@@ -0,0 +1,60 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/SkyAPM/go2sky"
+	"github.com/micro-plat/hydra/conf"
+	"github.com/micro-plat/hydra/conf/app"
+	"github.com/micro-plat/hydra/conf/server/apm"
+	"github.com/micro-plat/lib4go/assert"
+)
+
+type testServerConf struct {
+	conf.IServerConf
+}
+
+func (s *testServerConf) GetServerPath() string  { return "/hydra/apiserver/api/t/conf" }
+func (s *testServerConf) GetPlatName() string    { return "hydra" }
+func (s *testServerConf) GetSysName() string     { return "apiserver" }
+func (s *testServerConf) GetServerType() string  { return "api" }
+func (s *testServerConf) GetClusterName() string { return "t" }
+
+type testAPPConf struct {
+	app.IAPPConf
+}
+
+func (c *testAPPConf) GetServerConf() conf.IServerConf { return &testServerConf{} }
+
+type closeReporter struct {
+	testReporter
+	closed bool
+}
+
+func (r *closeReporter) Close() { r.closed = true }
+
+func TestGetTracer_Replace(t *testing.T) {
+	created := make([]*closeReporter, 0, 2)
+	newReporter = func(address string) (go2sky.Reporter, error) {
+		r := &closeReporter{}
+		created = append(created, r)
+		return r, nil
+	}
+	c := &testAPPConf{}
+	path := c.GetServerConf().GetServerPath()
+	defer CloseTracer(path)
+
+	t1, err := getTracer(c, &apm.APM{Address: "192.168.0.1:11800"})
+	assert.Equal(t, nil, err, "创建跟踪器")
+	t2, _ := getTracer(c, &apm.APM{Address: "192.168.0.1:11800"})
+	assert.Equal(t, t1, t2, "地址未变化时共用跟踪器")
+	assert.Equal(t, 1, len(created), "只创建一个上报组件")
+
+	t3, _ := getTracer(c, &apm.APM{Address: "192.168.0.2:11800"})
+	assert.NotEqual(t, t1, t3, "地址变化时创建新的跟踪器")
+	assert.Equal(t, true, created[0].closed, "关闭原上报组件")
+	assert.Equal(t, false, created[1].closed, "新上报组件可用")
+
+	CloseTracer(path)
+	assert.Equal(t, true, created[1].closed, "服务器关闭时关闭上报组件")
+}
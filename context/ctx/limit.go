@@ -0,0 +1,70 @@
+package ctx
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/micro-plat/hydra/conf/server/router"
+	"github.com/micro-plat/hydra/context"
+	"github.com/micro-plat/lib4go/errs"
+)
+
+//limitedBody 限制读取字节数的请求body，超过时返回context.ErrBodyTooLarge
+type limitedBody struct {
+	io.ReadCloser
+	remain   int64
+	exceeded bool
+}
+
+func (l *limitedBody) Read(p []byte) (n int, err error) {
+	if l.exceeded {
+		return 0, context.ErrBodyTooLarge
+	}
+	if int64(len(p)) > l.remain+1 {
+		p = p[:l.remain+1]
+	}
+	n, err = l.ReadCloser.Read(p)
+	if int64(n) <= l.remain {
+		l.remain -= int64(n)
+		return n, err
+	}
+	n = int(l.remain)
+	l.remain = 0
+	l.exceeded = true
+	return n, context.ErrBodyTooLarge
+}
+
+//limitBody 在读取body前设置大小限制，Content-Length超过限制时不再读取
+func limitBody(req *http.Request, limits router.Limits) *limitedBody {
+	if req == nil || req.Body == nil || limits.MaxBodySize <= 0 {
+		return nil
+	}
+	l := &limitedBody{ReadCloser: req.Body, remain: limits.MaxBodySize}
+	l.exceeded = req.ContentLength > limits.MaxBodySize
+	req.Body = l
+	return l
+}
+
+//checkLimits 检查请求内容与上传文件是否超过限制
+func checkLimits(req *http.Request, body *limitedBody, limits router.Limits) error {
+	if body != nil && body.exceeded {
+		return errs.NewError(http.StatusRequestEntityTooLarge, fmt.Errorf("请求内容超过%d字节:%w", limits.MaxBodySize, context.ErrBodyTooLarge))
+	}
+	if req == nil || req.MultipartForm == nil {
+		return nil
+	}
+	count := 0
+	for key, files := range req.MultipartForm.File {
+		count += len(files)
+		if limits.MaxFiles > 0 && count > limits.MaxFiles {
+			return errs.NewError(http.StatusRequestEntityTooLarge, fmt.Errorf("上传文件超过%d个:%w", limits.MaxFiles, context.ErrBodyTooLarge))
+		}
+		for _, f := range files {
+			if limits.MaxFileSize > 0 && f.Size > limits.MaxFileSize {
+				return errs.NewError(http.StatusRequestEntityTooLarge, fmt.Errorf("上传文件%s(%s)超过%d字节:%w", key, f.Filename, limits.MaxFileSize, context.ErrBodyTooLarge))
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package ctx
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/micro-plat/hydra/conf/server/router"
+	"github.com/micro-plat/lib4go/assert"
+	"github.com/micro-plat/lib4go/errs"
+)
+
+func TestLimitBody(t *testing.T) {
+	limits := router.Limits{MaxBodySize: 10}
+
+	//未超过限制
+	req, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader("0123456789"))
+	body := limitBody(req, limits)
+	buff, err := ioutil.ReadAll(req.Body)
+	assert.Equal(t, nil, err, "读取请求内容")
+	assert.Equal(t, "0123456789", string(buff), "请求内容")
+	assert.Equal(t, nil, checkLimits(req, body, limits), "未超过限制")
+
+	//Content-Length超过限制
+	req, _ = http.NewRequest(http.MethodPost, "/", strings.NewReader("01234567890"))
+	body = limitBody(req, limits)
+	buff, _ = ioutil.ReadAll(req.Body)
+	assert.Equal(t, 0, len(buff), "不读取请求内容")
+	assert.Equal(t, http.StatusRequestEntityTooLarge, errs.GetCode(checkLimits(req, body, limits)), "Content-Length超过限制")
+
+	//未指定Content-Length时按读取字节数检查
+	req, _ = http.NewRequest(http.MethodPost, "/", ioutil.NopCloser(strings.NewReader("01234567890")))
+	req.ContentLength = -1
+	body = limitBody(req, limits)
+	buff, _ = ioutil.ReadAll(req.Body)
+	assert.Equal(t, 10, len(buff), "读取到限制字节数")
+	assert.Equal(t, http.StatusRequestEntityTooLarge, errs.GetCode(checkLimits(req, body, limits)), "读取内容超过限制")
+
+	//未设置限制
+	req, _ = http.NewRequest(http.MethodPost, "/", strings.NewReader("01234567890"))
+	assert.Equal(t, true, limitBody(req, router.Limits{}) == nil, "未设置限制")
+}
+
+func TestLimits_Merge(t *testing.T) {
+	global := router.Limits{MaxBodySize: 100, MaxFileSize: 50, MaxFiles: 2}
+	l := router.Limits{MaxBodySize: 10}.Merge(global)
+	assert.Equal(t, router.Limits{MaxBodySize: 10, MaxFileSize: 50, MaxFiles: 2}, l, "路由配置优先")
+}
@@ -148,3 +148,27 @@ func (c *rpath) IsLimited() bool {
 func (c *rpath) AllowFallback() bool {
 	return c.fallback
 }
+
+//IsStream 当前路由是否为流式处理
+func (c *rpath) IsStream() bool {
+	routerObj, err := c.GetRouter()
+	return err == nil && routerObj.Stream
+}
+
+//GetLimits 获取请求大小限制，路由未设置时使用服务器的全局限制，流式处理的路由只使用路由的限制
+func (c *rpath) GetLimits() router.Limits {
+	routerObj, err := c.GetRouter()
+	if err == nil && routerObj.Stream {
+		return routerObj.Limits
+	}
+	main := c.appConf.GetServerConf().GetMainConf()
+	global := router.Limits{
+		MaxBodySize: main.GetInt64("maxBodySize"),
+		MaxFileSize: main.GetInt64("maxFileSize"),
+		MaxFiles:    main.GetInt("maxFiles"),
+	}
+	if err != nil {
+		return global
+	}
+	return routerObj.Limits.Merge(global)
+}
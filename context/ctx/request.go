@@ -9,6 +9,7 @@ import (
 	"github.com/micro-plat/hydra/conf"
 	"github.com/micro-plat/hydra/conf/app"
 	"github.com/micro-plat/hydra/context"
+	"github.com/micro-plat/hydra/pkgs/codec"
 	"github.com/micro-plat/lib4go/errs"
 	"github.com/micro-plat/lib4go/types"
 )
@@ -37,10 +38,21 @@ func NewRequest(c context.IInnerContext, s app.IAPPConf, meta conf.IMeta) *reque
 		path: rpath,
 		file: NewFile(c, meta),
 	}
+	req.body.stream = rpath.IsStream()
+
+	//读取body前设置大小限制
+	hreq, _ := c.GetHTTPReqResp()
+	limits := rpath.GetLimits()
+	limited := limitBody(hreq, limits)
+
 	req.XMap, req.readMapErr = req.body.GetMap()
 	if req.XMap == nil {
 		req.XMap = make(map[string]interface{})
 	}
+	if err := checkLimits(hreq, limited, limits); err != nil {
+		req.readMapErr = err
+		return req
+	}
 	if req.readMapErr != nil {
 		req.readMapErr = errs.NewError(http.StatusNotAcceptable, req.readMapErr)
 	}
@@ -64,8 +76,16 @@ func (r *request) Bind(obj interface{}) error {
 		return r.readMapErr
 	}
 
-	//处理数据结构转换
-	if err := r.XMap.ToAnyStruct(obj); err != nil {
+	//处理数据结构转换，二进制编解码器(protobuf,msgpack等)直接解码原始body
+	if c, ok := codec.Get(r.ctx.ContentType()); ok && codec.IsBinary(r.ctx.ContentType()) {
+		body, err := r.body.GetBody()
+		if err != nil {
+			return errs.NewError(http.StatusNotAcceptable, err)
+		}
+		if err := c.Unmarshal(body, obj); err != nil {
+			return errs.NewError(http.StatusNotAcceptable, fmt.Errorf("对象%s解码有误 %v", c.Name(), err))
+		}
+	} else if err := r.XMap.ToAnyStruct(obj); err != nil {
 		return errs.NewError(http.StatusNotAcceptable, fmt.Errorf("对象转换有误 %v", err))
 	}
 
@@ -145,3 +165,9 @@ func (r *request) Cookies() types.XMap {
 	}
 	return r.cookies
 }
+
+//GetRPCStream 获取rpc流式请求的数据流，非rpc流式请求时返回false
+func (r *request) GetRPCStream() (context.IRPCStream, bool) {
+	s := r.ctx.GetRPCStream()
+	return s, s != nil
+}
@@ -3,15 +3,18 @@ package ctx
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/clbanning/mxj"
 	"github.com/micro-plat/hydra/conf"
 	"github.com/micro-plat/hydra/conf/app"
 	"github.com/micro-plat/hydra/context"
 	"github.com/micro-plat/hydra/global"
+	"github.com/micro-plat/hydra/pkgs/codec"
 	"github.com/micro-plat/lib4go/encoding"
 	"github.com/micro-plat/lib4go/errs"
 	"github.com/micro-plat/lib4go/logger"
@@ -33,6 +36,14 @@ type rspns struct {
 	content     string
 }
 
+//encoded 已编码的响应内容
+type encoded struct {
+	source   string
+	encoding string
+	rawSize  int
+	data     []byte
+}
+
 type response struct {
 	ctx         context.IInnerContext
 	xmlRoot     string
@@ -46,6 +57,11 @@ type response struct {
 	noneedWrite bool
 	log         logger.ILogger
 	specials    []string
+	reqCtx      *reqContext
+	stream      *stream
+	streamed    *countWriter
+	encoded     *encoded
+	size        [2]int
 }
 
 //NewResponse 构建响应信息
@@ -116,6 +132,10 @@ func (c *response) File(path string) {
 	c.final.status = http.StatusOK
 	c.ctx.WStatus(http.StatusOK)
 	c.ctx.File(path)
+
+	//文件未修改(304)或范围请求(206)时使用实际写入的状态码
+	c.raw.status = types.DecodeInt(c.ctx.Status(), 0, http.StatusOK)
+	c.final.status = c.raw.status
 }
 
 //NoNeedWrite 无需写入响应数据到缓存
@@ -222,6 +242,12 @@ func (c *response) getContentType() string {
 func (c *response) swapBytp(status int, content interface{}) (rs int, rc interface{}) { //处理状态码与响应内容的默认
 
 	switch v := content.(type) {
+	case *context.Error:
+		c.log.Error(content)
+
+		//输出结构化的错误内容，调试模式下输出原始错误
+		rs = v.GetCode()
+		rc = v.GetResult(c.ctx.GetHeaders().Get("Accept-Language"), c.log.GetSessionID(), global.IsDebug)
 	case errs.IError:
 		c.log.Error(content)
 
@@ -301,12 +327,25 @@ func (c *response) getStringByCP(ctp string, tpkind reflect.Kind, content interf
 		return ""
 	}
 
+	//二进制内容直接输出
+	if buff, ok := content.([]byte); ok && !strings.Contains(ctp, "json") &&
+		!strings.Contains(ctp, "xml") && !strings.Contains(ctp, "yaml") {
+		return string(buff)
+	}
+
 	if tpkind != reflect.Map && tpkind != reflect.Struct && tpkind != reflect.Slice && tpkind != reflect.Array {
 
 		return fmt.Sprint(content)
 	}
 
 	switch {
+	case codec.IsBinary(ctp):
+		c, _ := codec.Get(ctp)
+		buff, err := c.Marshal(content)
+		if err != nil {
+			panic(err)
+		}
+		return string(buff)
 	case strings.Contains(ctp, "xml"):
 		str, err := types.Any2XML(content, c.xmlHeader, c.xmlRoot)
 		if err != nil {
@@ -367,12 +406,15 @@ func (c *response) writeNow() error {
 		return nil
 	}
 
-	buff := []byte(content)
-	e := c.path.GetEncoding()
-	if e != encoding.UTF8 {
-		buff1, err := encoding.Encode(content, e)
-		if err == nil {
-			buff = buff1
+	buff := c.getBytes(content)
+	c.size = [2]int{len(buff), len(buff)}
+	if c.encoded != nil {
+		if c.encoded.source == content {
+			buff = c.encoded.data
+			c.size[1] = len(buff)
+		} else {
+			//编码后内容已被修改
+			c.ctx.WHeaders().Del("Content-Encoding")
 		}
 	}
 	c.ContentType(ctyp)
@@ -380,6 +422,50 @@ func (c *response) writeNow() error {
 	return nil
 }
 
+//getBytes 按请求的字符编码转换响应内容
+func (c *response) getBytes(content string) []byte {
+	e := c.path.GetEncoding()
+	if e != encoding.UTF8 && !codec.IsBinary(c.final.contentType) {
+		buff, err := encoding.Encode(content, e)
+		if err == nil {
+			return buff
+		}
+	}
+	return []byte(content)
+}
+
+//Encode 使用指定的内容编码对最终响应内容进行编码，编码后的内容在Flush时写入
+func (c *response) Encode(contentEncoding string, encoder func([]byte) ([]byte, error)) error {
+	if c.noneedWrite || c.ctx.Written() {
+		return fmt.Errorf("响应已写入，无法编码")
+	}
+	content := c.final.content
+	buff := c.getBytes(content)
+	data, err := encoder(buff)
+	if err != nil {
+		return fmt.Errorf("响应内容%s编码失败:%w", contentEncoding, err)
+	}
+	c.encoded = &encoded{source: content, encoding: contentEncoding, rawSize: len(buff), data: data}
+	c.ctx.Header("Content-Encoding", contentEncoding)
+	c.AddSpecial(contentEncoding)
+	return nil
+}
+
+//GetSize 获取响应内容的原始字节数与写入(编码后)的字节数，写入前返回当前内容的字节数
+func (c *response) GetSize() (raw int, written int) {
+	if c.streamed != nil {
+		n := c.streamed.Count()
+		return n, n
+	}
+	if c.noneedWrite {
+		return c.size[0], c.size[1]
+	}
+	if c.encoded != nil && c.encoded.source == c.final.content {
+		return c.encoded.rawSize, len(c.encoded.data)
+	}
+	return len(c.final.content), len(c.final.content)
+}
+
 //Redirect 转跳g刚才gc
 func (c *response) Redirect(code int, url string) {
 	c.ctx.Redirect(code, url)
@@ -415,6 +501,76 @@ func (c *response) GetHTTPReponse() http.ResponseWriter {
 	return response
 }
 
+//Stream 立即写入响应头并返回响应流
+func (c *response) Stream(status int, contentType string) (io.Writer, error) {
+	if c.noneedWrite || c.ctx.Written() {
+		return nil, fmt.Errorf("响应已写入，无法打开响应流")
+	}
+	req, w := c.ctx.GetHTTPReqResp()
+	if req == nil || w == nil || c.reqCtx == nil {
+		return nil, fmt.Errorf("%s服务器不支持响应流", c.conf.GetServerConf().GetServerType())
+	}
+	if !c.reqCtx.keepAlive(req.Context()) {
+		return nil, fmt.Errorf("请求已超时，无法打开响应流")
+	}
+	liftDeadline(w)
+	c.writeHeaderNow(status, contentType, "stream")
+	c.streamed = &countWriter{w: w}
+	return c.streamed, nil
+}
+
+//IsStream 是否已打开响应流或事件流
+func (c *response) IsStream() bool {
+	return c.streamed != nil
+}
+
+//writeHeaderNow 写入响应头，并标记为已写入，后续中间件不再写入响应内容
+func (c *response) writeHeaderNow(status int, contentType string, special string) {
+	if contentType != "" {
+		c.ctx.Header("Content-Type", contentType)
+	}
+	c.ctx.WStatus(status)
+	c.noneedWrite, c.hasWrite = true, true
+	c.raw.status, c.raw.contentType = status, contentType
+	c.final.status, c.final.contentType = status, contentType
+	c.AddSpecial(special)
+}
+
+//SSE 打开服务端推送事件流
+func (c *response) SSE(heartbeat ...time.Duration) (context.IStream, error) {
+	if c.stream != nil {
+		return c.stream, nil
+	}
+	if c.noneedWrite || c.ctx.Written() {
+		return nil, fmt.Errorf("响应已写入，无法打开事件流")
+	}
+	req, w := c.ctx.GetHTTPReqResp()
+	flusher, ok := w.(http.Flusher)
+	if req == nil || !ok || c.reqCtx == nil {
+		return nil, fmt.Errorf("%s服务器不支持事件流", c.conf.GetServerConf().GetServerType())
+	}
+	if !c.reqCtx.keepAlive(req.Context()) {
+		return nil, fmt.Errorf("请求已超时，无法打开事件流")
+	}
+	liftDeadline(w)
+	c.ctx.Header("Cache-Control", "no-cache")
+	c.ctx.Header("Connection", "keep-alive")
+	c.ctx.Header("X-Accel-Buffering", "no")
+	c.writeHeaderNow(http.StatusOK, context.UTF8EventStream, "sse")
+	flusher.Flush()
+
+	c.streamed = &countWriter{w: w}
+	c.stream = newStream(c.streamed, flusher, c.reqCtx.Done(), req.Header.Get("Last-Event-ID"))
+	d := defHeartbeat
+	if len(heartbeat) > 0 {
+		d = heartbeat[0]
+	}
+	if d > 0 {
+		go c.stream.heartbeat(d)
+	}
+	return c.stream, nil
+}
+
 //GetFinalResponse 获取响应内容信息
 func (c *response) GetFinalResponse() (int, string, string) {
 	return c.final.status, c.final.content, c.final.contentType
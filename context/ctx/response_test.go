@@ -0,0 +1,103 @@
+package ctx
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/micro-plat/hydra/context"
+	"github.com/micro-plat/lib4go/assert"
+	"github.com/micro-plat/lib4go/logger"
+)
+
+type writerStub struct {
+	context.IInnerContext
+	header    http.Header
+	reqHeader http.Header
+	status    int
+	data      []byte
+}
+
+func (s *writerStub) Header(k string, v string) { s.header.Set(k, v) }
+func (s *writerStub) WHeaders() http.Header     { return s.header }
+func (s *writerStub) Written() bool             { return s.data != nil }
+func (s *writerStub) Status() int               { return s.status }
+func (s *writerStub) WHeader(k string) string   { return s.header.Get(k) }
+func (s *writerStub) GetHeaders() http.Header   { return s.reqHeader }
+func (s *writerStub) Data(status int, ctp string, data []byte) {
+	s.status, s.data = status, data
+}
+
+func gzipEncode(buff []byte) ([]byte, error) {
+	var b bytes.Buffer
+	w := gzip.NewWriter(&b)
+	w.Write(buff)
+	w.Close()
+	return b.Bytes(), nil
+}
+
+func newEncodeResponse(content string) (*response, *writerStub) {
+	w := &writerStub{header: http.Header{}}
+	r := &response{ctx: w, path: &rpath{ctx: w, encoding: "utf-8"}}
+	r.final = rspns{status: http.StatusOK, contentType: context.UTF8JSON, content: content}
+	return r, w
+}
+
+func TestResponse_Encode(t *testing.T) {
+	content := strings.Repeat(`{"name":"hydra"}`, 100)
+	r, w := newEncodeResponse(content)
+	assert.Equal(t, nil, r.Encode("gzip", gzipEncode), "压缩响应内容")
+	raw, written := r.GetSize()
+	assert.Equal(t, len(content), raw, "原始字节数")
+	assert.Equal(t, true, written < raw, "压缩后字节数")
+
+	r.Flush()
+	assert.Equal(t, "gzip", w.header.Get("Content-Encoding"), "内容编码")
+	gr, err := gzip.NewReader(bytes.NewReader(w.data))
+	assert.Equal(t, nil, err, "读取压缩内容")
+	buff, _ := ioutil.ReadAll(gr)
+	assert.Equal(t, content, string(buff), "解压后内容一致")
+	assert.Equal(t, [2]int{raw, written}, [2]int{len(content), len(w.data)}, "写入字节数")
+
+	//已写入的响应不能编码
+	assert.NotEqual(t, nil, r.Encode("gzip", gzipEncode), "响应已写入")
+
+	//编码后内容被修改时写入未编码的内容
+	r, w = newEncodeResponse(content)
+	r.Encode("gzip", gzipEncode)
+	r.final.content = "changed"
+	r.Flush()
+	assert.Equal(t, "", w.header.Get("Content-Encoding"), "移除内容编码")
+	assert.Equal(t, "changed", string(w.data), "写入修改后的内容")
+	raw, written = r.GetSize()
+	assert.Equal(t, raw, written, "未压缩")
+}
+
+func TestResponse_WriteError(t *testing.T) {
+	context.RegisterError("ORDER_NOT_FOUND", http.StatusNotFound, "订单%s不存在")
+	context.RegisterErrorMessage("en", "ORDER_NOT_FOUND", "order %s not found")
+
+	w := &writerStub{header: http.Header{}, reqHeader: http.Header{}}
+	w.header.Set("Content-Type", context.UTF8JSON)
+	w.reqHeader.Set("Accept-Language", "en-US,en;q=0.9,zh;q=0.8")
+	r := &response{ctx: w, path: &rpath{ctx: w, encoding: "utf-8"}, log: logger.GetSession("hydra", "abc123")}
+	r.Write(0, context.NewError("ORDER_NOT_FOUND", "0001").WithCause(errors.New("sql: no rows")))
+	status, content, _ := r.GetFinalResponse()
+	assert.Equal(t, http.StatusNotFound, status, "注册的状态码")
+	assert.Equal(t, `{"code":"ORDER_NOT_FOUND","message":"order 0001 not found","trace_id":"abc123"}`, content, "结构化错误内容")
+
+	w.header.Set("Content-Type", context.UTF8PLAIN)
+	w.reqHeader.Del("Accept-Language")
+	r.Write(0, context.NewError("ORDER_NOT_FOUND", "0002"))
+	_, content, _ = r.GetFinalResponse()
+	assert.Equal(t, "ORDER_NOT_FOUND:订单0002不存在", content, "默认消息")
+
+	r.Write(0, context.NewError("UNREGISTERED_CODE", "0003"))
+	status, content, _ = r.GetFinalResponse()
+	assert.Equal(t, http.StatusBadRequest, status, "未注册的状态码")
+	assert.Equal(t, "UNREGISTERED_CODE:UNREGISTERED_CODE", content, "未注册的错误码不格式化参数")
+}
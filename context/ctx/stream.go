@@ -0,0 +1,264 @@
+package ctx
+
+import (
+	r "context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/micro-plat/hydra/context"
+)
+
+var _ context.IStream = &stream{}
+
+//defHeartbeat 事件流默认心跳间隔
+const defHeartbeat = 15 * time.Second
+
+//stream 服务端推送事件流
+type stream struct {
+	w       io.Writer
+	flusher http.Flusher
+	done    <-chan struct{}
+	lastID  string
+	count   int32
+	closed  bool
+	lock    sync.Mutex
+}
+
+func newStream(w io.Writer, flusher http.Flusher, done <-chan struct{}, lastID string) *stream {
+	return &stream{w: w, flusher: flusher, done: done, lastID: lastID}
+}
+
+//Send 发送事件
+func (s *stream) Send(id string, event string, data interface{}) error {
+	text, err := getEventData(data)
+	if err != nil {
+		return err
+	}
+	var b strings.Builder
+	if id != "" {
+		fmt.Fprintf(&b, "id: %s\n", singleLine(id))
+	}
+	if event != "" {
+		fmt.Fprintf(&b, "event: %s\n", singleLine(event))
+	}
+	for _, line := range strings.Split(text, "\n") {
+		fmt.Fprintf(&b, "data: %s\n", strings.TrimSuffix(line, "\r"))
+	}
+	b.WriteString("\n")
+	if err := s.write(b.String()); err != nil {
+		return err
+	}
+	atomic.AddInt32(&s.count, 1)
+	return nil
+}
+
+//Comment 发送注释
+func (s *stream) Comment(text string) error {
+	return s.write(fmt.Sprintf(": %s\n\n", singleLine(text)))
+}
+
+//Retry 设置客户端断线重连间隔
+func (s *stream) Retry(d time.Duration) error {
+	return s.write(fmt.Sprintf("retry: %d\n\n", d.Milliseconds()))
+}
+
+//Feed 将通道中的消息作为事件持续发送
+func (s *stream) Feed(event string, ch <-chan string) error {
+	for {
+		select {
+		case <-s.done:
+			return nil
+		case msg, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			if err := s.Send("", event, msg); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+//LastEventID 客户端断线重连时携带的最后事件编号
+func (s *stream) LastEventID() string {
+	return s.lastID
+}
+
+//Done 客户端断开或服务退出时关闭
+func (s *stream) Done() <-chan struct{} {
+	return s.done
+}
+
+//Count 已发送的事件数
+func (s *stream) Count() int {
+	return int(atomic.LoadInt32(&s.count))
+}
+
+//heartbeat 定时发送心跳，防止代理服务器关闭空闲连接
+func (s *stream) heartbeat(d time.Duration) {
+	ticker := time.NewTicker(d)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			if err := s.Comment("heartbeat"); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *stream) write(text string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.closed {
+		return fmt.Errorf("事件流已关闭")
+	}
+	if _, err := s.w.Write([]byte(text)); err != nil {
+		return fmt.Errorf("事件流写入失败:%w", err)
+	}
+	s.flusher.Flush()
+	return nil
+}
+
+//close 关闭事件流，请求结束后不再写入
+func (s *stream) close() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.closed = true
+}
+
+//getEventData 获取事件数据，非字符串时转换为json
+func getEventData(data interface{}) (string, error) {
+	switch v := data.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case error:
+		return v.Error(), nil
+	default:
+		buff, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("事件数据转换为json失败:%w", err)
+		}
+		return string(buff), nil
+	}
+}
+
+func singleLine(s string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(s)
+}
+
+//countWriter 记录写入字节数的响应流
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	atomic.AddInt64(&c.n, int64(n))
+	return n, err
+}
+
+//Count 已写入的字节数
+func (c *countWriter) Count() int {
+	return int(atomic.LoadInt64(&c.n))
+}
+
+//liftDeadline 清除服务器为当前请求设置的读写超时，流式请求、响应流与事件流的时长不受服务器读写超时限制，不支持时忽略
+func liftDeadline(w http.ResponseWriter) {
+	rc := http.NewResponseController(unwrapWriter(w))
+	rc.SetReadDeadline(time.Time{})
+	rc.SetWriteDeadline(time.Time{})
+}
+
+//unwrapWriter 获取被包装的原始响应对象，gin的响应对象未提供Unwrap方法，通过嵌入的ResponseWriter获取
+func unwrapWriter(w http.ResponseWriter) http.ResponseWriter {
+	v := reflect.ValueOf(w)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return w
+	}
+	f := v.Elem().FieldByName("ResponseWriter")
+	if !f.IsValid() || !f.CanInterface() {
+		return w
+	}
+	if raw, ok := f.Interface().(http.ResponseWriter); ok && raw != nil {
+		return raw
+	}
+	return w
+}
+
+//reqContext 请求上下文，超时或请求结束时关闭，打开事件流后不再超时，客户端断开时关闭
+type reqContext struct {
+	r.Context
+	cancel   func()
+	deadline time.Time
+	timer    *time.Timer
+	expired  int32
+	lifted   int32
+}
+
+func newReqContext(parent r.Context, timeout time.Duration) *reqContext {
+	c := &reqContext{deadline: time.Now().Add(timeout)}
+	c.Context, c.cancel = r.WithCancel(parent)
+	c.timer = time.AfterFunc(timeout, func() {
+		atomic.StoreInt32(&c.expired, 1)
+		c.cancel()
+	})
+	return c
+}
+
+//Deadline 超时时间
+func (c *reqContext) Deadline() (time.Time, bool) {
+	if atomic.LoadInt32(&c.lifted) == 1 {
+		return c.Context.Deadline()
+	}
+	return c.deadline, true
+}
+
+//Err 超时关闭时返回DeadlineExceeded
+func (c *reqContext) Err() error {
+	err := c.Context.Err()
+	if err != nil && atomic.LoadInt32(&c.expired) == 1 {
+		return r.DeadlineExceeded
+	}
+	return err
+}
+
+//keepAlive 取消超时限制，并在客户端断开(client结束)时关闭，已超时时返回false
+func (c *reqContext) keepAlive(client r.Context) bool {
+	if atomic.LoadInt32(&c.lifted) == 1 {
+		return true
+	}
+	if !c.timer.Stop() {
+		return false
+	}
+	atomic.StoreInt32(&c.lifted, 1)
+	go func() {
+		select {
+		case <-client.Done():
+			c.cancel()
+		case <-c.Context.Done():
+		}
+	}()
+	return true
+}
+
+//close 关闭上下文
+func (c *reqContext) close() {
+	c.timer.Stop()
+	c.cancel()
+}
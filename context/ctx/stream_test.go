@@ -0,0 +1,120 @@
+package ctx
+
+import (
+	r "context"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func TestStream_Send(t *testing.T) {
+	w := httptest.NewRecorder()
+	done := make(chan struct{})
+	s := newStream(w, w, done, "5")
+
+	assert.Equal(t, "5", s.LastEventID(), "最后事件编号")
+	assert.Equal(t, nil, s.Retry(3*time.Second), "设置重连间隔")
+	assert.Equal(t, nil, s.Send("6", "progress", map[string]int{"value": 60}), "发送json事件")
+	assert.Equal(t, nil, s.Send("", "", "a\nb"), "发送多行事件")
+	assert.Equal(t, nil, s.Comment("heartbeat"), "发送注释")
+	assert.Equal(t, 2, s.Count(), "事件数")
+	assert.Equal(t, "retry: 3000\n\nid: 6\nevent: progress\ndata: {\"value\":60}\n\ndata: a\ndata: b\n\n: heartbeat\n\n", w.Body.String(), "事件流内容")
+
+	s.close()
+	assert.NotEqual(t, nil, s.Send("", "", "x"), "关闭后不能写入")
+}
+
+func TestStream_Feed(t *testing.T) {
+	w := httptest.NewRecorder()
+	done := make(chan struct{})
+	s := newStream(w, w, done, "")
+	ch := make(chan string, 2)
+	ch <- "1"
+	ch <- "2"
+	close(ch)
+	assert.Equal(t, nil, s.Feed("msg", ch), "通道关闭后结束")
+	assert.Equal(t, "event: msg\ndata: 1\n\nevent: msg\ndata: 2\n\n", w.Body.String(), "事件流内容")
+
+	close(done)
+	assert.Equal(t, nil, s.Feed("msg", make(chan string)), "客户端断开后结束")
+}
+
+func TestReqContext(t *testing.T) {
+	c := newReqContext(r.Background(), 20*time.Millisecond)
+	_, ok := c.Deadline()
+	assert.Equal(t, true, ok, "设置超时时间")
+	<-c.Done()
+	assert.Equal(t, r.DeadlineExceeded, c.Err(), "超时关闭")
+
+	c = newReqContext(r.Background(), 20*time.Millisecond)
+	assert.Equal(t, true, c.keepAlive(r.Background()), "取消超时")
+	_, ok = c.Deadline()
+	assert.Equal(t, false, ok, "无超时时间")
+	time.Sleep(40 * time.Millisecond)
+	assert.Equal(t, nil, c.Err(), "未超时关闭")
+	c.close()
+	assert.Equal(t, r.Canceled, c.Err(), "请求结束关闭")
+
+	client, cancel := r.WithCancel(r.Background())
+	c = newReqContext(r.Background(), time.Minute)
+	assert.Equal(t, true, c.keepAlive(client), "关联客户端")
+	assert.Equal(t, true, c.keepAlive(client), "重复调用")
+	cancel()
+	<-c.Done()
+	assert.Equal(t, r.Canceled, c.Err(), "客户端断开")
+
+	c = newReqContext(r.Background(), 10*time.Millisecond)
+	<-c.Done()
+	assert.Equal(t, false, c.keepAlive(r.Background()), "已超时")
+}
+
+func TestLiftDeadline(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	engine := gin.New()
+	engine.POST("/stream", func(c *gin.Context) {
+		liftDeadline(c.Writer)
+
+		//读取与写入的时长均超过服务器读写超时
+		body, err := ioutil.ReadAll(c.Request.Body)
+		if err != nil {
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		c.Status(http.StatusOK)
+		for i := 0; i < 3; i++ {
+			c.Writer.Write(body)
+			c.Writer.Flush()
+			time.Sleep(100 * time.Millisecond)
+		}
+	})
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Equal(t, nil, err, "监听端口")
+	server := &http.Server{Handler: engine, ReadTimeout: 100 * time.Millisecond, WriteTimeout: 100 * time.Millisecond}
+	go server.Serve(l)
+	defer server.Close()
+
+	pr, pw := io.Pipe()
+	go func() {
+		for i := 0; i < 3; i++ {
+			time.Sleep(60 * time.Millisecond)
+			pw.Write([]byte("a"))
+		}
+		pw.Close()
+	}()
+	resp, err := http.Post("http://"+l.Addr().String()+"/stream", "application/octet-stream", pr)
+	assert.Equal(t, nil, err, "发送请求")
+	defer resp.Body.Close()
+	buff, err := ioutil.ReadAll(resp.Body)
+	assert.Equal(t, nil, err, "读取响应")
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "读取请求body")
+	assert.Equal(t, strings.Repeat("aaa", 3), string(buff), "写入完整响应")
+}
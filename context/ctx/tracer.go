@@ -13,8 +13,12 @@ type tracer struct {
 }
 
 func newTracer(path string, l logger.ILogger, c app.IAPPConf) *tracer {
+	t, err := internal.GetTracer(path, c)
+	if err != nil {
+		l.Warn("创建链路跟踪器失败:", err)
+	}
 	return &tracer{
-		Tracer: internal.Empty,
+		Tracer: t,
 		l:      l,
 	}
 }
@@ -23,3 +27,8 @@ func newTracer(path string, l logger.ILogger, c app.IAPPConf) *tracer {
 func (t *tracer) Root() context.ITraceSpan {
 	return t.Tracer.Root()
 }
+
+//CloseTracer 关闭服务器的链路跟踪器，释放APM上报组件
+func CloseTracer(serverPath string) {
+	internal.CloseTracer(serverPath)
+}
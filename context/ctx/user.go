@@ -1,7 +1,10 @@
 package ctx
 
 import (
+	"crypto/x509"
+
 	"github.com/micro-plat/hydra/conf"
+	"github.com/micro-plat/hydra/conf/app"
 	"github.com/micro-plat/hydra/context"
 	"github.com/micro-plat/hydra/global"
 	"github.com/micro-plat/lib4go/utility"
@@ -18,10 +21,10 @@ type user struct {
 }
 
 //NewUser 用户信息
-func NewUser(ctx context.IInnerContext, meta conf.IMeta) *user {
+func NewUser(ctx context.IInnerContext, meta conf.IMeta, appConf app.IAPPConf) *user {
 	u := &user{
 		ctx:   ctx,
-		auth:  newAuth(ctx),
+		auth:  newAuth(ctx, meta, appConf),
 		IMeta: meta,
 	}
 	if ids, ok := ctx.GetHeaders()[context.XRequestID]; ok && len(ids) > 0 && ids[0] != "" {
@@ -37,9 +40,24 @@ func (c *user) GetTraceID() string {
 	return c.traceID
 }
 
-//GetUserName 获取用户名(basic认证启动后有效)
+//GetUserName 获取用户名(basic认证启动后有效)，未设置时使用客户端证书的CommonName
 func (c *user) GetUserName() string {
-	return c.GetString(context.UserName)
+	if name := c.GetString(context.UserName); name != "" {
+		return name
+	}
+	if cert := c.GetClientCert(); cert != nil {
+		return cert.Subject.CommonName
+	}
+	return ""
+}
+
+//GetClientCert 获取已验证的客户端证书(双向tls认证启用后有效)
+func (c *user) GetClientCert() *x509.Certificate {
+	certs := c.ctx.GetPeerCertificates()
+	if len(certs) == 0 {
+		return nil
+	}
+	return certs[0]
 }
 
 //GetClientIP 获取客户端IP地址
@@ -1,6 +1,7 @@
 package creator
 
 import (
+	"github.com/micro-plat/hydra/conf/server/apm"
 	"github.com/micro-plat/hydra/conf/server/cron"
 	"github.com/micro-plat/hydra/conf/server/task"
 	"github.com/micro-plat/hydra/services"
@@ -40,3 +41,9 @@ func (b *cronBuilder) Task(tks ...*task.Task) *cronBuilder {
 	otask.Append(tks...)
 	return b
 }
+
+//APM 构建APM配置
+func (b *cronBuilder) APM(address string, opts ...apm.Option) *cronBuilder {
+	b.BaseBuilder[apm.TypeNodeName] = apm.New(address, opts...)
+	return b
+}
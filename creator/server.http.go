@@ -12,11 +12,17 @@ import (
 	"github.com/micro-plat/hydra/conf/server/auth/apikey"
 	"github.com/micro-plat/hydra/conf/server/auth/basic"
 	"github.com/micro-plat/hydra/conf/server/auth/jwt"
+	"github.com/micro-plat/hydra/conf/server/auth/oidc"
 	"github.com/micro-plat/hydra/conf/server/auth/ras"
+	"github.com/micro-plat/hydra/conf/server/auth/rbac"
+	"github.com/micro-plat/hydra/conf/server/compress"
 	"github.com/micro-plat/hydra/conf/server/header"
+	"github.com/micro-plat/hydra/conf/server/idempotent"
 	"github.com/micro-plat/hydra/conf/server/metric"
 	"github.com/micro-plat/hydra/conf/server/render"
 	"github.com/micro-plat/hydra/conf/server/static"
+	tlsconf "github.com/micro-plat/hydra/conf/server/tls"
+	"github.com/micro-plat/hydra/conf/server/ws"
 )
 
 type httpBuilder struct {
@@ -43,6 +49,20 @@ func (b *httpBuilder) Jwt(opts ...jwt.Option) *httpBuilder {
 	return b
 }
 
+//OIDC oidc认证配置，认证通过后签发jwt，需同时配置jwt
+func (b *httpBuilder) OIDC(issuer string, clientID string, redirectURL string, opts ...oidc.Option) *httpBuilder {
+	path := fmt.Sprintf("%s/%s", oidc.ParNodeName, oidc.SubNodeName)
+	b.BaseBuilder[path] = oidc.New(issuer, clientID, redirectURL, opts...)
+	return b
+}
+
+//RBAC 基于角色与权限的访问控制配置
+func (b *httpBuilder) RBAC(opts ...rbac.Option) *httpBuilder {
+	path := fmt.Sprintf("%s/%s", rbac.ParNodeName, rbac.SubNodeName)
+	b.BaseBuilder[path] = rbac.New(opts...)
+	return b
+}
+
 //Fsa fsa静态密钥错误
 func (b *httpBuilder) APIKEY(secret string, opts ...apikey.Option) *httpBuilder {
 	path := fmt.Sprintf("%s/%s", apikey.ParNodeName, apikey.SubNodeName)
@@ -90,6 +110,30 @@ func (b *httpBuilder) Metric(host string, db string, cron string, opts ...metric
 	return b
 }
 
+//WebSocket websocket连接配置
+func (b *httpBuilder) WebSocket(opts ...ws.ConnOption) *httpBuilder {
+	b.BaseBuilder[ws.TypeNodeName] = ws.NewConn(opts...)
+	return b
+}
+
+//TLS 启用https(rpc为tls)，certFile与keyFile为空时通过tlsconf.WithConfigName使用注册中心中的证书
+func (b *httpBuilder) TLS(certFile string, keyFile string, opts ...tlsconf.Option) *httpBuilder {
+	b.BaseBuilder[tlsconf.TypeNodeName] = tlsconf.New(certFile, keyFile, opts...)
+	return b
+}
+
+//Compress 响应压缩配置
+func (b *httpBuilder) Compress(opts ...compress.Option) *httpBuilder {
+	b.BaseBuilder[compress.TypeNodeName] = compress.New(opts...)
+	return b
+}
+
+//Idempotent 幂等请求配置，requests为需要进行幂等处理的请求路径
+func (b *httpBuilder) Idempotent(requests []string, opts ...idempotent.Option) *httpBuilder {
+	b.BaseBuilder[idempotent.TypeNodeName] = idempotent.New(requests, opts...)
+	return b
+}
+
 //Static 静态文件配置
 func (b *httpBuilder) Static(opts ...static.Option) *httpBuilder {
 	b.BaseBuilder[static.TypeNodeName] = static.New(opts...)
@@ -117,7 +161,7 @@ func (b *httpBuilder) Render(script string) *httpBuilder {
 }
 
 //APM 构建APM配置
-func (b *httpBuilder) APM(address string) *httpBuilder {
-	b.BaseBuilder[apm.TypeNodeName] = apm.New(address)
+func (b *httpBuilder) APM(address string, opts ...apm.Option) *httpBuilder {
+	b.BaseBuilder[apm.TypeNodeName] = apm.New(address, opts...)
 	return b
 }
@@ -1,6 +1,7 @@
 package creator
 
 import (
+	"github.com/micro-plat/hydra/conf/server/apm"
 	"github.com/micro-plat/hydra/conf/server/mqc"
 	"github.com/micro-plat/hydra/conf/server/queue"
 	"github.com/micro-plat/hydra/global"
@@ -48,3 +49,9 @@ func (b *mqcBuilder) Queue(mq ...*queue.Queue) *mqcBuilder {
 	global.OnReady(f)
 	return b
 }
+
+//APM 构建APM配置
+func (b *mqcBuilder) APM(address string, opts ...apm.Option) *mqcBuilder {
+	b.BaseBuilder[apm.TypeNodeName] = apm.New(address, opts...)
+	return b
+}
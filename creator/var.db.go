@@ -4,6 +4,9 @@ import (
 	"github.com/micro-plat/hydra/conf/vars/db"
 	"github.com/micro-plat/hydra/conf/vars/db/mysql"
 	"github.com/micro-plat/hydra/conf/vars/db/oracle"
+	"github.com/micro-plat/hydra/conf/vars/db/postgres"
+	"github.com/micro-plat/hydra/conf/vars/db/sqlite"
+	"github.com/micro-plat/hydra/conf/vars/db/sqlserver"
 )
 
 //Vardb db配置
@@ -38,6 +41,36 @@ func (c *Vardb) MySQLByConnStr(nodeName string, connStr string, opts ...db.Optio
 	return c.Custom(nodeName, mysql.New(connStr, opts...))
 }
 
+//Postgres 添加Postgres
+func (c *Vardb) Postgres(nodeName string, uName string, pwd string, serverIP string, dbName string, opts ...db.Option) vars {
+	return c.Custom(nodeName, must(postgres.NewBy(uName, pwd, serverIP, dbName, opts...)))
+}
+
+//PostgresByConnStr 添加Postgres
+func (c *Vardb) PostgresByConnStr(nodeName string, connStr string, opts ...db.Option) vars {
+	return c.Custom(nodeName, must(postgres.New(connStr, opts...)))
+}
+
+//SQLite 添加SQLite,path为数据库文件路径
+func (c *Vardb) SQLite(nodeName string, path string, opts ...db.Option) vars {
+	return c.Custom(nodeName, must(sqlite.NewBy(path, opts...)))
+}
+
+//SQLiteByConnStr 添加SQLite
+func (c *Vardb) SQLiteByConnStr(nodeName string, connStr string, opts ...db.Option) vars {
+	return c.Custom(nodeName, must(sqlite.New(connStr, opts...)))
+}
+
+//SQLServer 添加SQLServer
+func (c *Vardb) SQLServer(nodeName string, uName string, pwd string, serverIP string, dbName string, opts ...db.Option) vars {
+	return c.Custom(nodeName, must(sqlserver.NewBy(uName, pwd, serverIP, dbName, opts...)))
+}
+
+//SQLServerByConnStr 添加SQLServer
+func (c *Vardb) SQLServerByConnStr(nodeName string, connStr string, opts ...db.Option) vars {
+	return c.Custom(nodeName, must(sqlserver.New(connStr, opts...)))
+}
+
 //Custom 自定义数据库配置
 func (c *Vardb) Custom(nodeName string, q interface{}) vars {
 	if _, ok := c.vars[db.TypeNodeName]; !ok {
@@ -46,3 +79,11 @@ func (c *Vardb) Custom(nodeName string, q interface{}) vars {
 	c.vars[db.TypeNodeName][nodeName] = q
 	return c.vars
 }
+
+//must 数据库连接信息有误时中止配置
+func must(d *db.DB, err error) *db.DB {
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/micro-plat/hydra/context"
 	"github.com/micro-plat/hydra/creator"
 	"github.com/micro-plat/hydra/global"
+	"github.com/micro-plat/hydra/hydra/servers/pkg/middleware"
 	"github.com/micro-plat/hydra/registry"
 	"github.com/micro-plat/hydra/services"
 )
@@ -31,6 +32,9 @@ var CRON services.ICRON = services.CRON
 //MQC MQC服务动态注册管理
 var MQC services.IMQC = services.MQC
 
+//PurgeResponseCache 清除指定路径前缀下的响应缓存
+var PurgeResponseCache = middleware.PurgeResponseCache
+
 //IContext 请求上下文
 type IContext = context.IContext
 
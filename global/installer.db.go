@@ -1,39 +1,27 @@
 package global
 
 import (
-	"strings"
-
 	"github.com/micro-plat/lib4go/types"
 )
 
 //db 数据库处理逻辑
 type db struct {
-	sqls     []string
-	handlers []func() error
+	sqls       []string
+	handlers   []func() error
+	migrations migrations
 }
 
 //AddBSQL 添加执行SQL
 func (d *db) AddBSQL(sqls ...[]byte) {
 	for _, sql := range sqls {
-		nsql := strings.Split(strings.Trim(types.BytesToString(sql), ";"), ";")
-		for _, m := range nsql {
-			if strings.TrimSpace(m) != "" {
-				d.sqls = append(d.sqls, strings.TrimSpace(m))
-			}
-		}
+		d.sqls = append(d.sqls, splitSQL(types.BytesToString(sql))...)
 	}
 }
 
 //AddBSQL 添加执行SQL
 func (d *db) AddSQL(sqls ...string) {
 	for _, sql := range sqls {
-		nsql := strings.Split(strings.Trim(sql, ";"), ";")
-		for _, m := range nsql {
-			if strings.TrimSpace(m) != "" {
-				d.sqls = append(d.sqls, strings.TrimSpace(m))
-			}
-		}
-
+		d.sqls = append(d.sqls, splitSQL(sql)...)
 	}
 
 }
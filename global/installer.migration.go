@@ -0,0 +1,94 @@
+package global
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+//Migration 数据库版本迁移脚本
+type Migration struct {
+
+	//Version 版本号，按从小到大的顺序执行
+	Version int64
+
+	//Name 迁移名称
+	Name string
+
+	//Up 升级脚本，多条语句以";"分隔
+	Up string
+
+	//Down 回滚脚本，多条语句以";"分隔
+	Down string
+}
+
+//GetUpSQLs 获取升级语句
+func (m *Migration) GetUpSQLs() []string {
+	return splitSQL(m.Up)
+}
+
+//GetDownSQLs 获取回滚语句
+func (m *Migration) GetDownSQLs() []string {
+	return splitSQL(m.Down)
+}
+
+//migrations 数据库版本迁移脚本集合
+type migrations struct {
+	items []*Migration
+	lock  sync.Mutex
+}
+
+//AddMigration 添加版本迁移脚本，版本号不能重复
+func (d *db) AddMigration(version int64, name string, up string, down ...string) {
+	d.migrations.lock.Lock()
+	defer d.migrations.lock.Unlock()
+	if version <= 0 {
+		panic(fmt.Sprintf("迁移脚本[%s]的版本号必须大于0", name))
+	}
+	if strings.TrimSpace(up) == "" {
+		panic(fmt.Sprintf("迁移脚本[%d.%s]未指定升级语句", version, name))
+	}
+	for _, m := range d.migrations.items {
+		if m.Version == version {
+			panic(fmt.Sprintf("迁移脚本版本号重复:%d(%s,%s)", version, m.Name, name))
+		}
+	}
+	d.migrations.items = append(d.migrations.items, &Migration{
+		Version: version,
+		Name:    name,
+		Up:      up,
+		Down:    strings.Join(down, ";"),
+	})
+}
+
+//AddBMigration 添加版本迁移脚本
+func (d *db) AddBMigration(version int64, name string, up []byte, down ...[]byte) {
+	downs := make([]string, 0, len(down))
+	for _, v := range down {
+		downs = append(downs, string(v))
+	}
+	d.AddMigration(version, name, string(up), downs...)
+}
+
+//GetMigrations 获取按版本号排序后的迁移脚本
+func (d *db) GetMigrations() []*Migration {
+	d.migrations.lock.Lock()
+	defer d.migrations.lock.Unlock()
+	list := make([]*Migration, len(d.migrations.items))
+	copy(list, d.migrations.items)
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Version < list[j].Version
+	})
+	return list
+}
+
+func splitSQL(s string) []string {
+	sqls := make([]string, 0, 1)
+	for _, m := range strings.Split(strings.Trim(s, ";"), ";") {
+		if strings.TrimSpace(m) != "" {
+			sqls = append(sqls, strings.TrimSpace(m))
+		}
+	}
+	return sqls
+}
@@ -0,0 +1,25 @@
+package global
+
+import (
+	"testing"
+
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func Test_db_AddMigration(t *testing.T) {
+	d := &db{}
+	d.AddMigration(3, "c", "alter table t add c int;")
+	d.AddMigration(1, "a", "create table t(a int);insert into t values(1);", "drop table t")
+	d.AddBMigration(2, "b", []byte("alter table t add b int"), []byte("alter table t drop b"))
+
+	list := d.GetMigrations()
+	assert.Equal(t, 3, len(list), "迁移脚本数量")
+	assert.Equal(t, []int64{1, 2, 3}, []int64{list[0].Version, list[1].Version, list[2].Version}, "按版本号排序")
+	assert.Equal(t, []string{"create table t(a int)", "insert into t values(1)"}, list[0].GetUpSQLs(), "拆分升级语句")
+	assert.Equal(t, []string{"alter table t drop b"}, list[1].GetDownSQLs(), "回滚语句")
+	assert.Equal(t, 0, len(list[2].GetDownSQLs()), "未指定回滚语句")
+
+	assert.Panics(t, func() { d.AddMigration(1, "x", "select 1") }, "版本号重复")
+	assert.Panics(t, func() { d.AddMigration(0, "x", "select 1") }, "版本号必须大于0")
+	assert.Panics(t, func() { d.AddMigration(4, "x", " ") }, "未指定升级语句")
+}
@@ -0,0 +1,107 @@
+package cron
+
+import (
+	"fmt"
+
+	logs "github.com/lib4dev/cli/logger"
+	"github.com/micro-plat/hydra/global/compatible"
+	"github.com/micro-plat/hydra/hydra/cmds/pkgs"
+	"github.com/micro-plat/hydra/hydra/servers/cron"
+	"github.com/urfave/cli"
+)
+
+func init() {
+	subCmds = append(subCmds, cli.Command{
+		Name:   "trigger",
+		Usage:  "-立即执行指定服务的任务",
+		Flags:  getControlFlags(),
+		Action: control(func(c *cron.Controller) error { return c.Trigger(service) }),
+	}, cli.Command{
+		Name:   "pause",
+		Usage:  "-暂停指定服务的任务",
+		Flags:  getControlFlags(),
+		Action: control(func(c *cron.Controller) error { return c.Pause(service) }),
+	}, cli.Command{
+		Name:   "resume",
+		Usage:  "-恢复指定服务的任务",
+		Flags:  getControlFlags(),
+		Action: control(func(c *cron.Controller) error { return c.Resume(service) }),
+	}, cli.Command{
+		Name:   "reschedule",
+		Usage:  "-修改指定服务任务的cron表达式,未指定表达式时恢复为配置的表达式",
+		Flags:  getRescheduleFlags(),
+		Action: control(func(c *cron.Controller) error { return c.Reschedule(service, expr) }),
+	}, cli.Command{
+		Name:   "controls",
+		Usage:  "-查看任务的控制信息",
+		Flags:  pkgs.GetBaseFlags(),
+		Action: showControls,
+	})
+}
+
+//control 构建修改任务控制信息的命令处理函数
+func control(fn func(c *cron.Controller) error) func(c *cli.Context) error {
+	return func(c *cli.Context) (err error) {
+		defer func() {
+			logNow(err)
+			err = nil
+		}()
+		cnf, err := getConf(c)
+		if err != nil {
+			return err
+		}
+		if service == "" {
+			return fmt.Errorf("未指定任务服务名")
+		}
+		if err := fn(cron.NewController(cnf.GetServerConf())); err != nil {
+			return err
+		}
+		logs.Log.Info(c.Command.Name, service, compatible.SUCCESS)
+		return nil
+	}
+}
+
+func showControls(c *cli.Context) (err error) {
+	defer func() {
+		logNow(err)
+		err = nil
+	}()
+	cnf, err := getConf(c)
+	if err != nil {
+		return err
+	}
+	controls, err := cron.NewController(cnf.GetServerConf()).Get()
+	if err != nil {
+		return err
+	}
+	if len(controls) == 0 {
+		logs.Log.Info("没有任务控制信息")
+		return nil
+	}
+	for service, ctrl := range controls {
+		logs.Log.Info(fmt.Sprintf("%-32spaused:%-6vcron:%s", service, ctrl.Paused, ctrl.Cron))
+	}
+	return nil
+}
+
+var expr string
+
+func getControlFlags() []cli.Flag {
+	flags := pkgs.GetBaseFlags()
+	flags = append(flags, cli.StringFlag{
+		Name:        "service",
+		Destination: &service,
+		Usage:       `-任务服务名`,
+	})
+	return flags
+}
+
+func getRescheduleFlags() []cli.Flag {
+	flags := getControlFlags()
+	flags = append(flags, cli.StringFlag{
+		Name:        "cron",
+		Destination: &expr,
+		Usage:       `-新的cron表达式`,
+	})
+	return flags
+}
@@ -0,0 +1,102 @@
+package cron
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lib4dev/cli/cmds"
+	logs "github.com/lib4dev/cli/logger"
+	"github.com/micro-plat/hydra/conf/app"
+	"github.com/micro-plat/hydra/global"
+	"github.com/micro-plat/hydra/global/compatible"
+	"github.com/micro-plat/hydra/hydra/cmds/pkgs"
+	"github.com/micro-plat/hydra/hydra/servers/cron"
+	"github.com/urfave/cli"
+)
+
+//subCmds 定时任务管理子命令
+var subCmds = []cli.Command{
+	{
+		Name:   "history",
+		Usage:  "-查看定时任务的执行记录",
+		Flags:  getHistoryFlags(),
+		Action: showHistory,
+	},
+}
+
+func init() {
+	cmds.RegisterFunc(func() cli.Command {
+		return cli.Command{
+			Name:        "cron",
+			Usage:       "定时任务, 定时任务执行情况管理",
+			Subcommands: subCmds,
+		}
+	})
+}
+
+//getConf 绑定应用程序参数并拉取cron服务器配置
+func getConf(c *cli.Context) (app.IAPPConf, error) {
+	global.Current().Log().Pause()
+	if err := global.Def.Bind(c); err != nil {
+		cli.ShowCommandHelp(c, c.Command.Name)
+		return nil, err
+	}
+	if err := app.PullAndSave(); err != nil {
+		return nil, err
+	}
+	return app.Cache.GetAPPConf(global.CRON)
+}
+
+func logNow(err error) {
+	if err != nil {
+		logs.Log.Error(err, compatible.FAILED)
+	}
+}
+
+func showHistory(c *cli.Context) (err error) {
+	defer func() {
+		logNow(err)
+		err = nil
+	}()
+	cnf, err := getConf(c)
+	if err != nil {
+		return err
+	}
+	records, err := cron.QueryHistory(cnf, service, limit)
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		logs.Log.Info("没有执行记录")
+		return nil
+	}
+	for _, r := range records {
+		msg := fmt.Sprintf("%-20s%-10s%-10s%-32s%-20s%s", r.GetScheduled().Format("2006-01-02 15:04:05"),
+			r.Status, time.Duration(r.End-r.Start).Round(time.Millisecond), r.Service, r.Node, r.Error)
+		if r.Status == cron.RecordFailed {
+			logs.Log.Warn(msg)
+			continue
+		}
+		logs.Log.Info(msg)
+	}
+	return nil
+}
+
+var service string
+var limit = 20
+
+func getHistoryFlags() []cli.Flag {
+	flags := pkgs.GetBaseFlags()
+	flags = append(flags, cli.StringFlag{
+		Name:        "service",
+		Destination: &service,
+		Usage:       `-任务服务名,未指定时查询所有任务`,
+	})
+	flags = append(flags, cli.IntFlag{
+		Name:        "limit,l",
+		Value:       20,
+		Destination: &limit,
+		Usage:       `-最多显示的记录数`,
+	})
+	return flags
+}
@@ -1,18 +1,13 @@
-// +build dev
-
-//数据库安装存在一定的风险，特别是SQL语句中包含有删除表，修改表等指令
-//所以编译项目时只有明确指定tags为"dev"时，才将此功能编译进二进制文件(go install -tags="dev")
-//生成生产环境二进制文件时，建议直接编译不要指定"dev"
 package db
 
 import (
-	"fmt"
 	"regexp"
 
 	"github.com/lib4dev/cli/cmds"
 	logs "github.com/lib4dev/cli/logger"
 	"github.com/manifoldco/promptui"
 	"github.com/micro-plat/hydra/components"
+	"github.com/micro-plat/hydra/components/dbs"
 	"github.com/micro-plat/hydra/conf/app"
 	"github.com/micro-plat/hydra/global"
 	"github.com/micro-plat/hydra/global/compatible"
@@ -22,85 +17,48 @@ import (
 	"github.com/urfave/cli"
 )
 
+//subCmds 数据库管理子命令，安装命令仅在指定tags为"dev"时添加
+var subCmds = make([]cli.Command, 0, 4)
+
 func init() {
 	cmds.RegisterFunc(func() cli.Command {
 		return cli.Command{
-			Name:  "db",
-			Usage: "数据库, 数据库初始化管理",
-			Subcommands: []cli.Command{
-				{
-					Name:   "install",
-					Usage:  "-将数据表等安装到数据库",
-					Flags:  getInstallFlags(),
-					Action: install,
-				},
-			},
+			Name:        "db",
+			Usage:       "数据库, 数据库初始化管理",
+			Subcommands: subCmds,
 		}
 	})
 }
-func install(c *cli.Context) (err error) {
-	defer func() {
-		logNow(err)
-		err = nil
-	}()
 
-	//1. 绑定应用程序参数
+//bind 绑定应用程序参数
+func bind(c *cli.Context) error {
 	global.Current().Log().Pause()
 	if err := global.Def.Bind(c); err != nil {
 		cli.ShowCommandHelp(c, c.Command.Name)
 		return err
 	}
+	return nil
+}
 
-	//2. 获取执行参数
-	sqls := global.Installer.DB.GetSQLs()
-	handlers := global.Installer.DB.GetHandlers()
-	if len(sqls) == 0 && len(handlers) == 0 {
-		return fmt.Errorf("未指定SQL或安装程序")
-	}
+//pullConf 拉取注册中心配置
+func pullConf() error {
 
-	//2.检查是否安装注册中心配置
+	//1.检查是否安装注册中心配置
 	if registry.GetProto(global.Current().GetRegistryAddr()) == registry.LocalMemory {
 		if err := pkgs.Pub2Registry(true); err != nil {
 			return err
 		}
 	}
 
-	//3. 拉取注册中心配置
-	if err := app.PullAndSave(); err != nil {
-		return err
-	}
-
-	//4. 执行SQL语句
-	if len(sqls) > 0 {
-		db, err := components.Def.DB().GetDB(types.GetString(dbName, "db"))
-		if err != nil {
-			return err
-		}
-		if !checkContinue() {
-			return nil
-		}
-		for _, sql := range sqls {
-			if _, err := db.Execute(sql, nil); err != nil {
-				err = fmt.Errorf("%32s\t%w", getMessage(sql), err)
-				if !skip {
-					return err
-				}
-				logs.Log.Error(err, compatible.FAILED)
-				continue
-			}
-			msg := fmt.Sprintf("%32s", getMessage(sql))
-			logs.Log.Info(msg, compatible.SUCCESS)
-		}
-	}
+	//2. 拉取注册中心配置
+	return app.PullAndSave()
+}
 
-	//5. 执行处理函数
-	for _, handle := range handlers {
-		if err := handle(); err != nil {
-			return err
-		}
-	}
-	return nil
+//getDB 获取数据库
+func getDB() (dbs.IDB, error) {
+	return components.Def.DB().GetDB(types.GetString(dbName, "db"))
 }
+
 func logNow(err error) {
 	if err != nil {
 		logs.Log.Error(err, compatible.FAILED)
@@ -126,10 +84,9 @@ func checkContinue() bool {
 }
 
 var dbName = "db"
-var skip bool
 
-//getInstallFlags 获取运行时的参数
-func getInstallFlags() []cli.Flag {
+//getBaseFlags 获取数据库命令的公共参数
+func getBaseFlags() []cli.Flag {
 	flags := pkgs.GetBaseFlags()
 	flags = append(flags, cli.BoolFlag{
 		Name:        "debug,d",
@@ -141,11 +98,5 @@ func getInstallFlags() []cli.Flag {
 		Destination: &dbName,
 		Usage:       `-数据库节点名,注册中配置的数据库节点名`,
 	})
-	flags = append(flags, cli.BoolFlag{
-		Name:        "skip",
-		Destination: &skip,
-		Usage:       `-跳过执行失败的SQL语句`,
-	})
-	flags = append(flags, global.DBCli.GetFlags()...)
 	return flags
 }
@@ -0,0 +1,92 @@
+// +build dev
+
+//数据库安装存在一定的风险，特别是SQL语句中包含有删除表，修改表等指令
+//所以编译项目时只有明确指定tags为"dev"时，才将此功能编译进二进制文件(go install -tags="dev")
+//生成生产环境二进制文件时，建议直接编译不要指定"dev"
+package db
+
+import (
+	"fmt"
+
+	logs "github.com/lib4dev/cli/logger"
+	"github.com/micro-plat/hydra/global"
+	"github.com/micro-plat/hydra/global/compatible"
+	"github.com/urfave/cli"
+)
+
+func init() {
+	subCmds = append(subCmds, cli.Command{
+		Name:   "install",
+		Usage:  "-将数据表等安装到数据库",
+		Flags:  getInstallFlags(),
+		Action: install,
+	})
+}
+func install(c *cli.Context) (err error) {
+	defer func() {
+		logNow(err)
+		err = nil
+	}()
+
+	//1. 绑定应用程序参数
+	if err := bind(c); err != nil {
+		return err
+	}
+
+	//2. 获取执行参数
+	sqls := global.Installer.DB.GetSQLs()
+	handlers := global.Installer.DB.GetHandlers()
+	if len(sqls) == 0 && len(handlers) == 0 {
+		return fmt.Errorf("未指定SQL或安装程序")
+	}
+
+	//3. 拉取注册中心配置
+	if err := pullConf(); err != nil {
+		return err
+	}
+
+	//4. 执行SQL语句
+	if len(sqls) > 0 {
+		db, err := getDB()
+		if err != nil {
+			return err
+		}
+		if !checkContinue() {
+			return nil
+		}
+		for _, sql := range sqls {
+			if _, err := db.Execute(sql, nil); err != nil {
+				err = fmt.Errorf("%32s\t%w", getMessage(sql), err)
+				if !skip {
+					return err
+				}
+				logs.Log.Error(err, compatible.FAILED)
+				continue
+			}
+			msg := fmt.Sprintf("%32s", getMessage(sql))
+			logs.Log.Info(msg, compatible.SUCCESS)
+		}
+	}
+
+	//5. 执行处理函数
+	for _, handle := range handlers {
+		if err := handle(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var skip bool
+
+//getInstallFlags 获取运行时的参数
+func getInstallFlags() []cli.Flag {
+	flags := getBaseFlags()
+	flags = append(flags, cli.BoolFlag{
+		Name:        "skip",
+		Destination: &skip,
+		Usage:       `-跳过执行失败的SQL语句`,
+	})
+	flags = append(flags, global.DBCli.GetFlags()...)
+	return flags
+}
@@ -0,0 +1,185 @@
+package db
+
+import (
+	"fmt"
+
+	logs "github.com/lib4dev/cli/logger"
+	"github.com/micro-plat/hydra/components/dbs/migrate"
+	"github.com/micro-plat/hydra/global"
+	"github.com/micro-plat/hydra/global/compatible"
+	"github.com/urfave/cli"
+)
+
+func init() {
+	subCmds = append(subCmds, cli.Command{
+		Name:   "migrate",
+		Usage:  "-按版本号执行未执行的数据库迁移脚本",
+		Flags:  getMigrateFlags(),
+		Action: migrateUp,
+	}, cli.Command{
+		Name:   "rollback",
+		Usage:  "-按版本号从大到小回滚已执行的数据库迁移脚本",
+		Flags:  getRollbackFlags(),
+		Action: migrateDown,
+	}, cli.Command{
+		Name:   "status",
+		Usage:  "-查看数据库迁移脚本的执行状态",
+		Flags:  getBaseFlags(),
+		Action: migrateStatus,
+	})
+}
+
+func migrateUp(c *cli.Context) (err error) {
+	defer func() {
+		logNow(err)
+		err = nil
+	}()
+	m, err := getMigrator(c)
+	if err != nil {
+		return err
+	}
+	if !dryRun && !assumeYes && !checkContinue() {
+		return nil
+	}
+	steps, err := m.Up(targetVersion)
+	logSteps(steps, "up")
+	return err
+}
+
+func migrateDown(c *cli.Context) (err error) {
+	defer func() {
+		logNow(err)
+		err = nil
+	}()
+	m, err := getMigrator(c)
+	if err != nil {
+		return err
+	}
+	if !dryRun && !assumeYes && !checkContinue() {
+		return nil
+	}
+	steps, err := m.Down(rollbackSteps, targetVersion)
+	logSteps(steps, "down")
+	return err
+}
+
+func migrateStatus(c *cli.Context) (err error) {
+	defer func() {
+		logNow(err)
+		err = nil
+	}()
+	dryRun = true
+	m, err := getMigrator(c)
+	if err != nil {
+		return err
+	}
+	states, err := m.Status()
+	if err != nil {
+		return err
+	}
+	for _, s := range states {
+		msg := fmt.Sprintf("%-16d%-32s%-10s%s", s.Version, s.Name, s.Status, s.AppliedAt)
+		if s.Status == migrate.StatusModified || s.Status == migrate.StatusMissing {
+			logs.Log.Warn(msg)
+			continue
+		}
+		logs.Log.Info(msg)
+	}
+	return nil
+}
+
+//getMigrator 构建数据库迁移程序
+func getMigrator(c *cli.Context) (*migrate.Migrator, error) {
+	if err := bind(c); err != nil {
+		return nil, err
+	}
+	migrations := global.Installer.DB.GetMigrations()
+	if len(migrations) == 0 {
+		return nil, fmt.Errorf("未指定数据库迁移脚本")
+	}
+	if err := pullConf(); err != nil {
+		return nil, err
+	}
+	db, err := getDB()
+	if err != nil {
+		return nil, err
+	}
+	opts := make([]migrate.Option, 0, 2)
+	if dryRun {
+		opts = append(opts, migrate.WithDryRun())
+	}
+	if ignoreChecksum {
+		opts = append(opts, migrate.WithIgnoreChecksum())
+	}
+	return migrate.New(db, migrations, opts...), nil
+}
+
+func logSteps(steps []*migrate.Step, direction string) {
+	if len(steps) == 0 {
+		logs.Log.Info("没有需要执行的迁移脚本")
+		return
+	}
+	for _, step := range steps {
+		msg := fmt.Sprintf("%-6s%-16d%s", direction, step.Migration.Version, step.Migration.Name)
+		if !dryRun {
+			logs.Log.Info(msg, compatible.SUCCESS)
+			continue
+		}
+		logs.Log.Info(msg)
+		for _, sql := range step.SQLs {
+			logs.Log.Info("\t", sql)
+		}
+	}
+}
+
+var targetVersion int64
+var rollbackSteps = 1
+var dryRun bool
+var assumeYes bool
+var ignoreChecksum bool
+
+func getMigrateFlags() []cli.Flag {
+	flags := getBaseFlags()
+	flags = append(flags, cli.Int64Flag{
+		Name:        "to",
+		Destination: &targetVersion,
+		Usage:       `-目标版本号,只执行版本号不大于该值的脚本`,
+	})
+	flags = append(flags, cli.BoolFlag{
+		Name:        "ignore-checksum",
+		Destination: &ignoreChecksum,
+		Usage:       `-忽略已执行脚本的内容变更`,
+	})
+	return append(flags, getExecFlags()...)
+}
+
+func getRollbackFlags() []cli.Flag {
+	flags := getBaseFlags()
+	flags = append(flags, cli.Int64Flag{
+		Name:        "to",
+		Destination: &targetVersion,
+		Usage:       `-目标版本号,回滚所有版本号大于该值的脚本`,
+	})
+	flags = append(flags, cli.IntFlag{
+		Name:        "steps",
+		Value:       1,
+		Destination: &rollbackSteps,
+		Usage:       `-回滚的脚本数,未指定目标版本号时有效`,
+	})
+	return append(flags, getExecFlags()...)
+}
+
+func getExecFlags() []cli.Flag {
+	flags := make([]cli.Flag, 0, 2)
+	flags = append(flags, cli.BoolFlag{
+		Name:        "dry-run",
+		Destination: &dryRun,
+		Usage:       `-只显示待执行的脚本,不修改数据库`,
+	})
+	flags = append(flags, cli.BoolFlag{
+		Name:        "yes,y",
+		Destination: &assumeYes,
+		Usage:       `-不提示确认,直接执行`,
+	})
+	return flags
+}
@@ -0,0 +1,145 @@
+package cron
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/micro-plat/hydra/components/dlock"
+	"github.com/micro-plat/hydra/conf"
+	"github.com/micro-plat/hydra/registry"
+	cron "github.com/robfig/cron/v3"
+)
+
+//Control 任务运行控制信息
+type Control struct {
+
+	//Paused 是否暂停按计划执行
+	Paused bool `json:"paused,omitempty"`
+
+	//Cron 替换配置中的cron表达式，为空时使用配置的表达式
+	Cron string `json:"cron,omitempty"`
+
+	//Trigger 最近一次手动触发的时间(纳秒)，值变大时立即执行一次
+	Trigger int64 `json:"trigger,omitempty"`
+}
+
+//lockTimeout 修改任务控制信息时等待锁的超时时长
+const lockTimeout = 10 * time.Second
+
+//Controls 以服务名为键的任务控制信息
+type Controls map[string]*Control
+
+//GetControlPath 获取任务控制节点路径
+func GetControlPath(c conf.IServerConf) string {
+	return registry.Join(c.GetServerRoot(), c.GetClusterName(), "control")
+}
+
+//Controller 通过注册中心控制节点管理任务，cron服务器监控该节点并实时生效
+type Controller struct {
+	r    registry.IRegistry
+	path string
+}
+
+//NewController 构建任务控制器
+func NewController(c conf.IServerConf) *Controller {
+	return &Controller{r: c.GetRegistry(), path: GetControlPath(c)}
+}
+
+//Trigger 立即执行指定服务的任务
+func (c *Controller) Trigger(service string) error {
+	return c.update(service, func(ctrl *Control) error {
+		ctrl.Trigger = time.Now().UnixNano()
+		return nil
+	})
+}
+
+//Pause 暂停指定服务的任务，暂停期间仍可手动触发
+func (c *Controller) Pause(service string) error {
+	return c.update(service, func(ctrl *Control) error {
+		ctrl.Paused = true
+		return nil
+	})
+}
+
+//Resume 恢复指定服务的任务
+func (c *Controller) Resume(service string) error {
+	return c.update(service, func(ctrl *Control) error {
+		ctrl.Paused = false
+		return nil
+	})
+}
+
+//Reschedule 修改指定服务任务的cron表达式，表达式为空时恢复为配置的表达式
+func (c *Controller) Reschedule(service string, expr string) error {
+	return c.update(service, func(ctrl *Control) error {
+		if expr != "" {
+			if _, err := cron.ParseStandard(expr); err != nil {
+				return fmt.Errorf("cron表达式(%s)配置有误 %w", expr, err)
+			}
+		}
+		ctrl.Cron = expr
+		return nil
+	})
+}
+
+//Get 获取所有任务的控制信息
+func (c *Controller) Get() (Controls, error) {
+	controls := make(Controls)
+	b, err := c.r.Exists(c.path)
+	if err != nil || !b {
+		return controls, err
+	}
+	buff, _, err := c.r.GetValue(c.path)
+	if err != nil {
+		return nil, fmt.Errorf("获取任务控制信息失败:%w", err)
+	}
+	return controls, parseControls(buff, controls)
+}
+
+//update 修改指定服务的控制信息，通过分布式锁串行执行读取、修改与写入，避免并发修改相互覆盖
+func (c *Controller) update(service string, fn func(*Control) error) error {
+	lk := dlock.NewLockByRegistry(c.path, c.r)
+	if err := lk.Lock(lockTimeout); err != nil {
+		return fmt.Errorf("获取任务控制节点%s的锁失败:%w", c.path, err)
+	}
+	defer lk.Unlock()
+
+	controls, err := c.Get()
+	if err != nil {
+		return err
+	}
+	ctrl, ok := controls[service]
+	if !ok {
+		ctrl = &Control{}
+		controls[service] = ctrl
+	}
+	if err := fn(ctrl); err != nil {
+		return err
+	}
+	if !ctrl.Paused && ctrl.Cron == "" && ctrl.Trigger == 0 {
+		delete(controls, service)
+	}
+	buff, err := json.Marshal(controls)
+	if err != nil {
+		return err
+	}
+	b, err := c.r.Exists(c.path)
+	if err != nil {
+		return err
+	}
+	if !b {
+		return c.r.CreatePersistentNode(c.path, string(buff))
+	}
+	return c.r.Update(c.path, string(buff))
+}
+
+func parseControls(buff []byte, controls Controls) error {
+	if len(buff) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(buff, &controls); err != nil {
+		return fmt.Errorf("任务控制信息格式有误:%w", err)
+	}
+	return nil
+}
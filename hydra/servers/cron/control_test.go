@@ -0,0 +1,91 @@
+package cron
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/micro-plat/hydra/conf/server/task"
+	"github.com/micro-plat/hydra/global"
+	"github.com/micro-plat/hydra/hydra/servers/pkg/dispatcher"
+	"github.com/micro-plat/hydra/registry"
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func TestController(t *testing.T) {
+	r, err := registry.GetRegistry("lm://.", global.Def.Log())
+	assert.Equal(t, nil, err, "获取注册中心")
+	c := &Controller{r: r, path: "/hydra/cron/t/control"}
+
+	assert.Equal(t, nil, c.Pause("/cron/a"), "暂停任务")
+	assert.NotEqual(t, nil, c.Reschedule("/cron/a", "错误"), "cron表达式错误")
+	assert.Equal(t, nil, c.Reschedule("/cron/a", "@every 5m"), "修改cron表达式")
+	assert.Equal(t, nil, c.Trigger("/cron/b"), "手动触发")
+
+	controls, err := c.Get()
+	assert.Equal(t, nil, err, "获取控制信息")
+	assert.Equal(t, &Control{Paused: true, Cron: "@every 5m"}, controls["/cron/a"], "获取控制信息")
+	assert.Equal(t, true, controls["/cron/b"].Trigger > 0, "获取控制信息")
+
+	assert.Equal(t, nil, c.Resume("/cron/a"), "恢复任务")
+	assert.Equal(t, nil, c.Reschedule("/cron/a", ""), "恢复cron表达式")
+	controls, err = c.Get()
+	assert.Equal(t, nil, err, "获取控制信息")
+	assert.Equal(t, 1, len(controls), "无控制信息的任务被删除")
+}
+
+func TestController_Concurrent(t *testing.T) {
+	r, err := registry.GetRegistry("lm://.", global.Def.Log())
+	assert.Equal(t, nil, err, "获取注册中心")
+	c := &Controller{r: r, path: "/hydra/cron/t/control_concurrent"}
+
+	//并发修改不同任务，各自的修改都应保留
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assert.Equal(t, nil, c.Pause(fmt.Sprintf("/cron/%d", i)), "暂停任务")
+		}(i)
+	}
+	wg.Wait()
+
+	controls, err := c.Get()
+	assert.Equal(t, nil, err, "获取控制信息")
+	assert.Equal(t, 10, len(controls), "并发修改不丢失")
+}
+
+func TestProcessor_Control(t *testing.T) {
+	h := &memHistory{}
+	s := NewProcessor()
+	s.UseHistory(h, "n1")
+	defer s.Close()
+
+	//使用空的服务引擎，避免依赖服务器配置
+	s.Engine = dispatcher.New()
+	s.Engine.Handle("GET", "/cron/ctrl", func(*dispatcher.Context) {})
+	s.Resume()
+
+	err := s.Add(task.NewTask("@every 1h", "/cron/ctrl"))
+	assert.Equal(t, nil, err, "添加任务")
+
+	//首次加载不执行已有的触发
+	s.Control(Controls{"/cron/ctrl": &Control{Trigger: 1}})
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 0, h.count(RecordSuccess), "首次加载不执行")
+
+	s.Control(Controls{"/cron/ctrl": &Control{Paused: true, Cron: "@every 2h", Trigger: 2}})
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 1, h.count(RecordSuccess), "手动触发")
+	tasks := s.tasks()
+	assert.Equal(t, 1, len(tasks), "修改cron表达式")
+	assert.Equal(t, "@every 2h", tasks[0].Cron, "修改cron表达式")
+	assert.Equal(t, true, tasks[0].IsPaused(), "暂停任务")
+
+	s.Control(Controls{})
+	tasks = s.tasks()
+	assert.Equal(t, 1, len(tasks), "恢复cron表达式")
+	assert.Equal(t, "@every 1h", tasks[0].Cron, "恢复cron表达式")
+	assert.Equal(t, false, tasks[0].IsPaused(), "恢复任务")
+}
@@ -2,6 +2,7 @@ package cron
 
 import (
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/micro-plat/hydra/conf/server/task"
@@ -17,6 +18,14 @@ type CronTask struct {
 	method   string
 	form     map[string]interface{}
 	header   map[string]string
+
+	lock      sync.Mutex
+	running   int
+	pending   *time.Time
+	skipping  bool
+	scheduled time.Time
+	paused    bool
+	origin    *task.Task
 }
 
 //NewCronTask 构建定时任务
@@ -72,3 +81,69 @@ func (m *CronTask) GetForm() map[string]interface{} {
 func (m *CronTask) GetHeader() map[string]string {
 	return m.header
 }
+
+//GetConf 获取配置的任务信息，cron表达式被修改时仍返回配置的表达式
+func (m *CronTask) GetConf() *task.Task {
+	if m.origin != nil {
+		return m.origin
+	}
+	return m.Task
+}
+
+//IsPaused 是否已暂停按计划执行
+func (m *CronTask) IsPaused() bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.paused
+}
+
+func (m *CronTask) setPaused(v bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.paused = v
+}
+
+//acquire 根据重叠执行策略检查是否可立即执行，不能执行时返回是否由执行转为跳过(连续跳过时只返回首次，
+//未跳过表示已排队等待)
+func (m *CronTask) acquire(scheduled time.Time) (run bool, skipped bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.running == 0 || m.GetOverlap() == task.OverlapAllow {
+		m.running++
+		m.skipping = false
+		return true, false
+	}
+	if m.GetOverlap() == task.OverlapQueue && m.pending == nil {
+		m.pending = &scheduled
+		return false, false
+	}
+	skipped, m.skipping = !m.skipping, true
+	return false, skipped
+}
+
+//release 执行结束，存在排队的执行时返回其计划时间
+func (m *CronTask) release() (scheduled time.Time, ok bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.running == 1 && m.pending != nil {
+		scheduled, m.pending = *m.pending, nil
+		return scheduled, true
+	}
+	m.running--
+	return scheduled, false
+}
+
+//missed 获取last之后至now之间错过的执行时间，最多返回最近的max个
+func (m *CronTask) missed(last time.Time, now time.Time, max int) []time.Time {
+	if m.IsImmediately() || max <= 0 {
+		return nil
+	}
+	times := make([]time.Time, 0, max)
+	for next := m.schedule.Next(last); !next.IsZero() && next.Before(now); next = m.schedule.Next(next) {
+		if len(times) == max {
+			times = times[1:]
+		}
+		times = append(times, next)
+	}
+	return times
+}
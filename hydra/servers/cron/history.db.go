@@ -0,0 +1,127 @@
+package cron
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/micro-plat/hydra/components"
+	"github.com/micro-plat/hydra/components/dbs"
+)
+
+//HistoryTableName 执行记录表名称
+const HistoryTableName = "cron_history"
+
+var sqlCreateHistory = `create table ` + HistoryTableName + `(
+task varchar(64) not null,
+service varchar(256) not null,
+cron varchar(64) not null,
+scheduled numeric(19) not null,
+start_time numeric(19) not null,
+end_time numeric(19) not null,
+status varchar(16) not null,
+code int not null,
+node varchar(64) not null,
+error varchar(1024))`
+
+var sqlCheckHistory = `select count(1) from ` + HistoryTableName + ` where 1=0`
+
+var sqlInsertHistory = `insert into ` + HistoryTableName + `(task,service,cron,scheduled,start_time,end_time,status,code,node,error)
+values(@task,@service,@cron,@scheduled,@start_time,@end_time,@status,@code,@node,@error)`
+
+var sqlQueryHistory = `select task,service,cron,scheduled,start_time,end_time,status,code,node,error from ` + HistoryTableName + `
+where task=@task order by scheduled desc,start_time desc`
+
+//dbHistory 将执行记录保存到数据库，表不存在时自动创建
+type dbHistory struct {
+	name    string
+	checked bool
+	lock    sync.Mutex
+}
+
+func newDBHistory(name string) *dbHistory {
+	return &dbHistory{name: name}
+}
+
+//Save 保存执行记录
+func (h *dbHistory) Save(r *Record) error {
+	db, err := h.getDB()
+	if err != nil {
+		return err
+	}
+	_, err = db.Execute(sqlInsertHistory, map[string]interface{}{
+		"task":       r.Task,
+		"service":    r.Service,
+		"cron":       r.Cron,
+		"scheduled":  r.Scheduled,
+		"start_time": r.Start,
+		"end_time":   r.End,
+		"status":     r.Status,
+		"code":       r.Code,
+		"node":       r.Node,
+		"error":      getError(r.Error),
+	})
+	if err != nil {
+		return fmt.Errorf("保存执行记录失败:%w", err)
+	}
+	return nil
+}
+
+//Query 按执行时间倒序查询任务的执行记录
+func (h *dbHistory) Query(task string, limit int) ([]*Record, error) {
+	db, err := h.getDB()
+	if err != nil {
+		return nil, err
+	}
+	sql := sqlQueryHistory
+	dialect, paging := dbs.GetDBDialect(db)
+	if limit > 0 && paging {
+		sql = dialect.Paging(sql, 1, limit)
+	}
+	rows, err := db.Query(sql, map[string]interface{}{"task": task})
+	if err != nil {
+		return nil, fmt.Errorf("查询执行记录失败:%w", err)
+	}
+	records := make([]*Record, 0, rows.Len())
+	for _, row := range rows {
+		records = append(records, &Record{
+			Task:      row.GetString("task"),
+			Service:   row.GetString("service"),
+			Cron:      row.GetString("cron"),
+			Scheduled: row.GetInt64("scheduled"),
+			Start:     row.GetInt64("start_time"),
+			End:       row.GetInt64("end_time"),
+			Status:    row.GetString("status"),
+			Code:      row.GetInt("code"),
+			Node:      row.GetString("node"),
+			Error:     row.GetString("error"),
+		})
+	}
+
+	//未注册方言的数据库无法分页查询，返回前limit条记录
+	if limit > 0 && len(records) > limit {
+		records = records[:limit]
+	}
+	return records, nil
+}
+
+func (h *dbHistory) getDB() (dbs.IDB, error) {
+	db, err := components.Def.DB().GetDB(h.name)
+	if err != nil {
+		return nil, fmt.Errorf("获取执行历史数据库失败:%w", err)
+	}
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.checked {
+		return db, nil
+	}
+	if _, err := db.Scalar(sqlCheckHistory, nil); err != nil {
+		if !dbs.IsTableNotFound(err) {
+			return nil, fmt.Errorf("检查执行记录表%s失败:%w", HistoryTableName, err)
+		}
+		if _, err := db.Execute(sqlCreateHistory, nil); err != nil {
+			return nil, fmt.Errorf("创建执行记录表%s失败:%w", HistoryTableName, err)
+		}
+	}
+	h.checked = true
+	return db, nil
+}
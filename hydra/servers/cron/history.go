@@ -0,0 +1,143 @@
+package cron
+
+import (
+	"fmt"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	"github.com/micro-plat/hydra/conf"
+	"github.com/micro-plat/hydra/conf/app"
+	xcron "github.com/micro-plat/hydra/conf/server/cron"
+	"github.com/micro-plat/hydra/registry"
+)
+
+const (
+	//RecordSuccess 执行成功
+	RecordSuccess = "success"
+
+	//RecordFailed 执行失败
+	RecordFailed = "failed"
+
+	//RecordSkipped 上次执行未结束，跳过本次执行
+	RecordSkipped = "skipped"
+)
+
+//Record 任务执行记录
+type Record struct {
+	Task      string `json:"task"`
+	Service   string `json:"service"`
+	Cron      string `json:"cron"`
+	Scheduled int64  `json:"scheduled"`
+	Start     int64  `json:"start"`
+	End       int64  `json:"end"`
+	Status    string `json:"status"`
+	Code      int    `json:"code"`
+	Node      string `json:"node"`
+	Error     string `json:"error,omitempty"`
+}
+
+//GetScheduled 计划执行时间
+func (r *Record) GetScheduled() time.Time {
+	return time.Unix(0, r.Scheduled)
+}
+
+//IHistory 任务执行历史
+type IHistory interface {
+
+	//Save 保存执行记录
+	Save(r *Record) error
+
+	//Query 按执行时间倒序查询任务的执行记录
+	Query(task string, limit int) ([]*Record, error)
+}
+
+//newRecord 构建执行记录
+func newRecord(task *CronTask, scheduled time.Time, node string) *Record {
+	return &Record{
+		Task:      task.GetName(),
+		Service:   task.GetService(),
+		Cron:      task.Cron,
+		Scheduled: scheduled.UnixNano(),
+		Start:     time.Now().UnixNano(),
+		Node:      node,
+	}
+}
+
+//finish 根据执行结果完成记录
+func (r *Record) finish(code int, data []byte, err error) *Record {
+	r.End = time.Now().UnixNano()
+	r.Code = code
+	r.Status = RecordSuccess
+	if err != nil || code >= 400 {
+		r.Status = RecordFailed
+		r.Error = getError(string(data))
+		if err != nil {
+			r.Error = getError(err.Error())
+		}
+	}
+	return r
+}
+
+//maxErrorLen 执行记录中错误信息的最大字符数，不超过执行记录表error字段的长度
+const maxErrorLen = 1000
+
+//getError 截取错误信息的前maxErrorLen个字符
+func getError(s string) string {
+	if utf8.RuneCountInString(s) <= maxErrorLen {
+		return s
+	}
+	return string([]rune(s)[:maxErrorLen])
+}
+
+//NewHistory 根据cron服务器配置构建执行历史存储，未启用时返回nil
+func NewHistory(c conf.IServerConf, s *xcron.Server) (IHistory, error) {
+	switch s.History {
+	case "":
+		return nil, nil
+	case xcron.HistoryRegistry:
+		root := registry.Join(c.GetServerRoot(), c.GetClusterName(), "history")
+		return newRegistryHistory(c.GetRegistry(), root, s.HistoryKeep), nil
+	case xcron.HistoryDB:
+		return newDBHistory(s.HistoryDB), nil
+	default:
+		return nil, fmt.Errorf("不支持的执行历史存储方式:%s", s.History)
+	}
+}
+
+//QueryHistory 查询cron服务器的任务执行记录，service为空时查询所有任务，按计划时间倒序返回最近limit条
+func QueryHistory(cnf app.IAPPConf, service string, limit int) ([]*Record, error) {
+	c, err := xcron.GetConf(cnf.GetServerConf())
+	if err != nil {
+		return nil, err
+	}
+	h, err := NewHistory(cnf.GetServerConf(), c)
+	if err != nil {
+		return nil, err
+	}
+	if h == nil {
+		return nil, fmt.Errorf("%s未启用执行历史", cnf.GetServerConf().GetServerName())
+	}
+	tasks, err := cnf.GetCRONTaskConf()
+	if err != nil {
+		return nil, err
+	}
+	records := make([]*Record, 0, limit)
+	for _, t := range tasks.Tasks {
+		if service != "" && t.Service != service {
+			continue
+		}
+		list, err := h.Query(t.GetUNQ(), limit)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, list...)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Scheduled > records[j].Scheduled
+	})
+	if limit > 0 && len(records) > limit {
+		records = records[:limit]
+	}
+	return records, nil
+}
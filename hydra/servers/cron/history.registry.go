@@ -0,0 +1,92 @@
+package cron
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/micro-plat/hydra/registry"
+)
+
+//defHistoryKeep 每个任务默认保留的记录数
+const defHistoryKeep = 100
+
+//registryHistory 将执行记录保存到注册中心，每条记录为任务节点下的一个永久节点
+type registryHistory struct {
+	r    registry.IRegistry
+	root string
+	keep int
+	lock sync.Mutex
+}
+
+func newRegistryHistory(r registry.IRegistry, root string, keep int) *registryHistory {
+	if keep <= 0 {
+		keep = defHistoryKeep
+	}
+	return &registryHistory{r: r, root: root, keep: keep}
+}
+
+//Save 保存执行记录，并删除超出保留数量的历史记录
+func (h *registryHistory) Save(r *Record) error {
+	rcd := *r
+	rcd.Error = getError(r.Error)
+	buff, err := json.Marshal(&rcd)
+	if err != nil {
+		return err
+	}
+	//节点名以计划时间开头，按名称排序即为执行顺序
+	name := fmt.Sprintf("%019d_%019d_%s", r.Scheduled, r.Start, r.Node)
+	if err := h.r.CreatePersistentNode(registry.Join(h.root, r.Task, name), string(buff)); err != nil {
+		return fmt.Errorf("保存执行记录失败:%w", err)
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	children, err := h.children(r.Task)
+	if err != nil {
+		return err
+	}
+	for i := h.keep; i < len(children); i++ {
+		h.r.Delete(registry.Join(h.root, r.Task, children[i]))
+	}
+	return nil
+}
+
+//Query 按执行时间倒序查询任务的执行记录
+func (h *registryHistory) Query(task string, limit int) ([]*Record, error) {
+	children, err := h.children(task)
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 && len(children) > limit {
+		children = children[:limit]
+	}
+	records := make([]*Record, 0, len(children))
+	for _, c := range children {
+		buff, _, err := h.r.GetValue(registry.Join(h.root, task, c))
+		if err != nil {
+			return nil, fmt.Errorf("获取执行记录失败:%w", err)
+		}
+		r := &Record{}
+		if err := json.Unmarshal(buff, r); err != nil {
+			return nil, fmt.Errorf("执行记录格式有误:%w", err)
+		}
+		records = append(records, r)
+	}
+	return records, nil
+}
+
+func (h *registryHistory) children(task string) ([]string, error) {
+	path := registry.Join(h.root, task)
+	b, err := h.r.Exists(path)
+	if err != nil || !b {
+		return nil, err
+	}
+	children, _, err := h.r.GetChildren(path)
+	if err != nil {
+		return nil, fmt.Errorf("获取执行记录列表失败:%w", err)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(children)))
+	return children, nil
+}
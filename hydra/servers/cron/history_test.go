@@ -0,0 +1,164 @@
+package cron
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/micro-plat/hydra/conf/server/task"
+	"github.com/micro-plat/hydra/global"
+	"github.com/micro-plat/hydra/hydra/servers/pkg/dispatcher"
+	"github.com/micro-plat/hydra/registry"
+	_ "github.com/micro-plat/hydra/registry/registry/localmemory"
+	"github.com/micro-plat/lib4go/assert"
+)
+
+//memHistory 内存中保存执行记录
+type memHistory struct {
+	lock    sync.Mutex
+	records []*Record
+}
+
+func (h *memHistory) Save(r *Record) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.records = append(h.records, r)
+	return nil
+}
+
+func (h *memHistory) Query(task string, limit int) ([]*Record, error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	list := make([]*Record, 0, len(h.records))
+	for i := len(h.records) - 1; i >= 0; i-- {
+		if h.records[i].Task == task {
+			list = append(list, h.records[i])
+		}
+	}
+	return list, nil
+}
+
+func (h *memHistory) count(status string) int {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	n := 0
+	for _, r := range h.records {
+		if r.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+func TestRegistryHistory(t *testing.T) {
+	r, err := registry.GetRegistry("lm://.", global.Def.Log())
+	assert.Equal(t, nil, err, "获取注册中心")
+
+	h := newRegistryHistory(r, "/hydra/cron/t/history", 3)
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		err := h.Save(&Record{Task: "task1", Scheduled: now.Add(time.Duration(i) * time.Second).UnixNano(), Status: RecordSuccess, Node: "n1"})
+		assert.Equal(t, nil, err, "保存执行记录")
+	}
+	records, err := h.Query("task1", 0)
+	assert.Equal(t, nil, err, "查询执行记录")
+	assert.Equal(t, 3, len(records), "只保留最近的记录")
+	assert.Equal(t, now.Add(4*time.Second).UnixNano(), records[0].Scheduled, "按计划时间倒序")
+
+	records, err = h.Query("task1", 1)
+	assert.Equal(t, nil, err, "查询执行记录")
+	assert.Equal(t, 1, len(records), "限制返回数量")
+
+	records, err = h.Query("task2", 10)
+	assert.Equal(t, nil, err, "查询不存在的任务")
+	assert.Equal(t, 0, len(records), "查询不存在的任务")
+}
+
+func TestRecord_FinishError(t *testing.T) {
+	r := (&Record{}).finish(500, []byte(strings.Repeat("错", 2000)), nil)
+	assert.Equal(t, RecordFailed, r.Status, "执行失败")
+	assert.Equal(t, maxErrorLen, utf8.RuneCountInString(r.Error), "截取响应内容")
+
+	r = (&Record{}).finish(0, nil, errors.New(strings.Repeat("e", 1200)))
+	assert.Equal(t, maxErrorLen, len(r.Error), "截取错误信息")
+
+	r = (&Record{}).finish(400, []byte("参数错误"), nil)
+	assert.Equal(t, "参数错误", r.Error, "未超长时不截取")
+}
+
+func TestCronTask_Overlap(t *testing.T) {
+	now := time.Now()
+	skip, _ := NewCronTask(task.NewTask("@every 1s", "/cron/skip", task.WithOverlap(task.OverlapSkip)))
+	run, skipped := skip.acquire(now)
+	assert.Equal(t, true, run, "首次执行")
+	run, skipped = skip.acquire(now)
+	assert.Equal(t, false, run, "执行中时跳过")
+	assert.Equal(t, true, skipped, "执行中时跳过")
+	run, skipped = skip.acquire(now)
+	assert.Equal(t, false, run, "连续跳过")
+	assert.Equal(t, false, skipped, "连续跳过时只记录首次")
+	_, ok := skip.release()
+	assert.Equal(t, false, ok, "无排队的执行")
+	skip.acquire(now)
+	_, skipped = skip.acquire(now)
+	assert.Equal(t, true, skipped, "再次执行后重新记录跳过")
+
+	def, _ := NewCronTask(task.NewTask("@every 1s", "/cron/default"))
+	def.acquire(now)
+	run, _ = def.acquire(now)
+	assert.Equal(t, true, run, "未配置时允许并行执行")
+
+	queue, _ := NewCronTask(task.NewTask("@every 1s", "/cron/queue", task.WithOverlap(task.OverlapQueue)))
+	queue.acquire(now)
+	run, skipped = queue.acquire(now.Add(time.Second))
+	assert.Equal(t, false, run, "执行中时排队")
+	assert.Equal(t, false, skipped, "执行中时排队")
+	run, skipped = queue.acquire(now.Add(2 * time.Second))
+	assert.Equal(t, true, skipped, "最多排队一次")
+	next, ok := queue.release()
+	assert.Equal(t, true, ok, "执行排队的任务")
+	assert.Equal(t, now.Add(time.Second), next, "执行排队的任务")
+	_, ok = queue.release()
+	assert.Equal(t, false, ok, "排队的任务已执行")
+
+	allow, _ := NewCronTask(task.NewTask("@every 1s", "/cron/allow", task.WithOverlap(task.OverlapAllow)))
+	allow.acquire(now)
+	run, _ = allow.acquire(now)
+	assert.Equal(t, true, run, "允许并行执行")
+}
+
+func TestCronTask_Missed(t *testing.T) {
+	m, _ := NewCronTask(task.NewTask("@every 1m", "/cron/missed", task.WithCatchUp(2)))
+	last := time.Date(2020, 1, 1, 0, 0, 0, 0, time.Local)
+	times := m.missed(last, last.Add(5*time.Minute+time.Second), m.CatchUp)
+	assert.Equal(t, []time.Time{last.Add(4 * time.Minute), last.Add(5 * time.Minute)}, times, "只补偿最近的执行")
+
+	times = m.missed(last, last.Add(30*time.Second), m.CatchUp)
+	assert.Equal(t, 0, len(times), "无错过的执行")
+}
+
+func TestProcessor_CatchUp(t *testing.T) {
+	h := &memHistory{}
+	s := NewProcessor()
+	s.UseHistory(h, "n1")
+	defer s.Close()
+
+	//使用空的服务引擎，避免依赖服务器配置
+	s.Engine = dispatcher.New()
+	s.Engine.Handle("GET", "/cron/catchup", func(*dispatcher.Context) {})
+
+	err := s.Add(task.NewTask("@every 1m", "/cron/catchup", task.WithCatchUp(3)))
+	assert.Equal(t, nil, err, "添加任务")
+	tasks := s.tasks()
+	assert.Equal(t, 1, len(tasks), "添加任务")
+	h.Save(&Record{Task: tasks[0].GetName(), Scheduled: time.Now().Add(-150 * time.Second).UnixNano(), Status: RecordSuccess})
+
+	ok, _ := s.Resume()
+	assert.Equal(t, true, ok, "恢复执行")
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, 3, h.count(RecordSuccess), "补偿执行错过的任务")
+	assert.Equal(t, 2, tasks[0].Counter.Get(), "补偿执行错过的任务")
+}
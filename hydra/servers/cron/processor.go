@@ -10,6 +10,7 @@ import (
 	"github.com/micro-plat/hydra/hydra/servers/pkg/dispatcher"
 	"github.com/micro-plat/hydra/hydra/servers/pkg/middleware"
 	"github.com/micro-plat/lib4go/concurrent/cmap"
+	"github.com/micro-plat/lib4go/logger"
 	"github.com/micro-plat/lib4go/utility"
 )
 
@@ -32,6 +33,12 @@ type Processor struct {
 	startTime time.Time
 	metric    *middleware.Metric
 	status    int
+	history   IHistory
+	node      string
+	log       logger.ILogger
+	ctrlLock  sync.Mutex
+	controls  Controls
+	triggered map[string]int64
 }
 
 //NewProcessor 创建processor
@@ -43,11 +50,13 @@ func NewProcessor() (p *Processor) {
 		length:    60,
 		startTime: time.Now(),
 		metric:    middleware.NewMetric(),
+		log:       logger.New(CRON),
 	}
 	p.Engine = dispatcher.New()
 	p.Engine.Use(middleware.Recovery().DispFunc(CRON))
 	p.Engine.Use(middleware.Logging().DispFunc())
 	p.Engine.Use(middleware.Recovery().DispFunc())
+	p.Engine.Use(middleware.APM().DispFunc())   //链路跟踪
 	p.Engine.Use(middleware.Trace().DispFunc()) //跟踪信息
 	p.Engine.Use(p.metric.Handle().DispFunc())
 	p.Engine.Use(middlewares.DispFunc()...)
@@ -80,7 +89,7 @@ func (s *Processor) Add(ts ...*task.Task) (err error) {
 			s.Remove(t.GetUNQ())
 			continue
 		}
-		task, err := NewCronTask(t)
+		task, err := s.newTask(t)
 		if err != nil {
 			return fmt.Errorf("构建cron.task失败:%v", err)
 		}
@@ -112,10 +121,94 @@ func (s *Processor) add(task *CronTask) (offset int, round int, err error) {
 		return -1, -1, errors.New("next time less than now.2")
 	}
 	task.Round.Update(round)
+	task.scheduled = nextTime
 	s.slots[offset].Set(utility.GetGUID(), task)
 	return
 }
 
+//UseHistory 设置执行历史存储，node为当前服务器节点编号
+func (s *Processor) UseHistory(h IHistory, node string) {
+	s.history = h
+	s.node = node
+}
+
+//Control 应用任务控制信息，暂停或恢复任务，修改cron表达式，并执行新触发的任务
+func (s *Processor) Control(controls Controls) {
+	//首次加载时只记录触发时间，不执行
+	s.ctrlLock.Lock()
+	initial := s.triggered == nil
+	if initial {
+		s.triggered = make(map[string]int64)
+	}
+	s.controls = controls
+	triggers := make([]string, 0, 1)
+	for service, ctrl := range controls {
+		last := s.triggered[service]
+		s.triggered[service] = ctrl.Trigger
+		if !initial && ctrl.Trigger > last {
+			triggers = append(triggers, service)
+		}
+	}
+	s.ctrlLock.Unlock()
+
+	for _, task := range s.tasks() {
+		ctrl := s.getControl(task.GetService())
+		conf := task.GetConf()
+		if ctrl.Cron != "" && ctrl.Cron != task.Cron || ctrl.Cron == "" && conf.Cron != task.Cron {
+			s.Remove(task.GetName())
+			if err := s.Add(conf); err != nil {
+				s.log.Errorf("修改任务%s的cron表达式失败:%v", task.GetService(), err)
+			}
+			continue
+		}
+		task.setPaused(ctrl.Paused)
+	}
+
+	for _, service := range triggers {
+		s.Trigger(service)
+	}
+}
+
+//Trigger 立即执行指定服务的任务，服务器未处于运行状态时不执行
+func (s *Processor) Trigger(service string) bool {
+	if s.done || s.status != running {
+		return false
+	}
+	found := false
+	for _, task := range s.tasks() {
+		if task.GetService() == service {
+			found = true
+			go s.run(task, time.Now())
+		}
+	}
+	return found
+}
+
+//newTask 构建定时任务，并应用任务控制信息
+func (s *Processor) newTask(t *task.Task) (*CronTask, error) {
+	ctrl := s.getControl(t.Service)
+	nt := *t
+	if ctrl.Cron != "" {
+		nt.Cron = ctrl.Cron
+	}
+	task, err := NewCronTask(&nt)
+	if err != nil {
+		return nil, err
+	}
+	task.origin = t
+	task.paused = ctrl.Paused
+	return task, nil
+}
+
+func (s *Processor) getControl(service string) *Control {
+	s.ctrlLock.Lock()
+	defer s.ctrlLock.Unlock()
+	if ctrl, ok := s.controls[service]; ok && ctrl != nil {
+		return ctrl
+	}
+	return &Control{}
+}
+
 //Remove 移除服务
 func (s *Processor) Remove(name string) {
 	s.lock.Lock()
@@ -123,8 +216,11 @@ func (s *Processor) Remove(name string) {
 	for _, slot := range s.slots {
 		slot.RemoveIterCb(func(k string, value interface{}) bool {
 			task := value.(*CronTask)
+			if task.GetName() != name {
+				return false
+			}
 			task.Disable = true
-			return task.GetName() == name
+			return true
 		})
 	}
 }
@@ -138,10 +234,11 @@ func (s *Processor) Pause() (bool, error) {
 	return false, nil
 }
 
-//Resume 恢复所有任务
+//Resume 恢复所有任务，并补偿执行暂停期间错过的任务
 func (s *Processor) Resume() (bool, error) {
 	if s.status != running {
 		s.status = running
+		go s.catchUp()
 		return true, nil
 	}
 	return false, nil
@@ -202,14 +299,87 @@ func (s *Processor) handle(task *CronTask) error {
 	if s.done || task.Disable {
 		return nil
 	}
-	if s.status == running {
+	scheduled := task.scheduled
+	if !task.IsImmediately() {
+		if _, _, err := s.add(task); err != nil {
+			return err
+		}
+	}
+	if s.status == running && !task.IsPaused() {
+		s.run(task, scheduled)
+	}
+	return nil
+}
+
+//run 按重叠执行策略执行任务并保存执行记录
+func (s *Processor) run(task *CronTask, scheduled time.Time) {
+	ok, skipped := task.acquire(scheduled)
+	if !ok {
+		//连续跳过时只记录首次
+		if skipped {
+			record := newRecord(task, scheduled, s.node)
+			record.End, record.Status = record.Start, RecordSkipped
+			s.save(record)
+		}
+		return
+	}
+	for {
 		task.Counter.Increase()
-		s.Engine.HandleRequest(task) //触发服务引擎进行业务处理
+		record := newRecord(task, scheduled, s.node)
+		w, err := s.Engine.HandleRequest(task) //触发服务引擎进行业务处理
+		s.save(record.finish(w.Status(), w.Data(), err))
+		if scheduled, ok = task.release(); !ok {
+			return
+		}
 	}
-	if task.IsImmediately() {
-		return nil
+}
+
+//catchUp 根据最近一次执行记录，补偿执行错过的任务
+func (s *Processor) catchUp() {
+	if s.history == nil {
+		return
+	}
+	now := time.Now()
+	for _, task := range s.tasks() {
+		if task.CatchUp <= 0 {
+			continue
+		}
+		records, err := s.history.Query(task.GetName(), 1)
+		if err != nil {
+			s.log.Errorf("获取任务%s的执行记录失败:%v", task.GetService(), err)
+			continue
+		}
+		if len(records) == 0 {
+			continue
+		}
+		go func(task *CronTask, times []time.Time) {
+			for _, scheduled := range times {
+				if s.done || s.status != running || task.Disable {
+					return
+				}
+				s.run(task, scheduled)
+			}
+		}(task, task.missed(records[0].GetScheduled(), now, task.CatchUp))
+	}
+}
+
+func (s *Processor) save(r *Record) {
+	if s.history == nil {
+		return
+	}
+	if err := s.history.Save(r); err != nil {
+		s.log.Errorf("保存任务%s的执行记录失败:%v", r.Service, err)
 	}
-	_, _, err := s.add(task)
-	return err
+}
 
+func (s *Processor) tasks() []*CronTask {
+	list := make([]*CronTask, 0, 1)
+	for i := range s.slots {
+		for item := range s.slots[i].IterBuffered() {
+			if task := item.Val.(*CronTask); !task.Disable {
+				list = append(list, task)
+			}
+		}
+	}
+	return list
 }
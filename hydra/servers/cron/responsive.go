@@ -8,6 +8,7 @@ import (
 	"github.com/micro-plat/hydra/conf/app"
 	"github.com/micro-plat/hydra/conf/server/cron"
 	"github.com/micro-plat/hydra/conf/server/task"
+	xctx "github.com/micro-plat/hydra/context/ctx"
 	"github.com/micro-plat/hydra/global"
 	"github.com/micro-plat/hydra/hydra/servers"
 	"github.com/micro-plat/hydra/registry/pub"
@@ -53,6 +54,9 @@ func (w *Responsive) Start() (err error) {
 		return
 	}
 
+	//启动前加载任务控制信息并监控变化，避免启动期间的控制变更丢失
+	w.watchControl()
+
 	if err = w.Server.Start(); err != nil {
 		err = fmt.Errorf("%s启动失败 %w", w.conf.GetServerConf().GetServerType(), err)
 		return
@@ -128,6 +132,7 @@ func (w *Responsive) Shutdown() {
 	w.log.Infof("关闭[%s]服务...", w.conf.GetServerConf().GetServerType())
 	w.Server.Shutdown()
 	w.pub.Clear()
+	xctx.CloseTracer(w.conf.GetServerConf().GetServerPath())
 	if err := services.Def.DoClosing(w.conf); err != nil {
 		w.log.Infof("关闭[%s]服务,出现错误", err)
 		return
@@ -158,7 +163,7 @@ func (w *Responsive) update(kv ...string) (err error) {
 
 //根据main.conf创建服务嚣
 func (w *Responsive) getServer(cnf app.IAPPConf) (*Server, error) {
-	_, err := cron.GetConf(cnf.GetServerConf())
+	c, err := cron.GetConf(cnf.GetServerConf())
 	if err != nil {
 		return nil, err
 	}
@@ -167,8 +172,19 @@ func (w *Responsive) getServer(cnf app.IAPPConf) (*Server, error) {
 	if err != nil {
 		return nil, err
 	}
+
+	history, err := NewHistory(cnf.GetServerConf(), c)
+	if err != nil {
+		return nil, err
+	}
+
 	//初始化server
-	return NewServer(task.Tasks...)
+	server, err := NewServer(task.Tasks...)
+	if err != nil {
+		return nil, err
+	}
+	server.UseHistory(history, cnf.GetServerConf().GetServerID())
+	return server, nil
 }
 
 func init() {
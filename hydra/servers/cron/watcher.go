@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/micro-plat/hydra/conf/server/cron"
+	"github.com/micro-plat/hydra/registry/watcher"
 )
 
 func (w *Responsive) watch() {
@@ -74,3 +75,48 @@ LOOP:
 		}
 	}
 }
+
+//watchControl 加载任务控制信息并监控控制节点变化，暂停、恢复、修改或手动触发指定任务
+func (w *Responsive) watchControl() {
+	server := w.Server
+	path := GetControlPath(w.conf.GetServerConf())
+
+	//先监控再加载，避免加载后至监控前的变更丢失
+	wc, err := watcher.NewValueWatcherByRegistry(w.conf.GetServerConf().GetRegistry(), []string{path}, w.log)
+	if err != nil {
+		w.log.Errorf("监控任务控制节点%s失败:%v", path, err)
+		return
+	}
+	notify, err := wc.Start()
+	if err != nil {
+		w.log.Errorf("监控任务控制节点%s失败:%v", path, err)
+		return
+	}
+
+	//加载当前控制信息，之前的手动触发不再执行
+	controls, err := NewController(w.conf.GetServerConf()).Get()
+	if err != nil {
+		w.log.Error(err)
+	} else {
+		server.Control(controls)
+	}
+
+	go func() {
+		for {
+			select {
+			case <-server.closeChan:
+				wc.Close()
+				return
+			case u := <-notify:
+				controls := make(Controls)
+				if u.OP != watcher.DEL {
+					if err := parseControls(u.Content, controls); err != nil {
+						w.log.Error(err)
+						continue
+					}
+				}
+				server.Control(controls)
+			}
+		}
+	}()
+}
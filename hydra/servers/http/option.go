@@ -1,6 +1,8 @@
 package http
 
 import (
+	"crypto/tls"
+
 	"github.com/gin-gonic/gin"
 	"github.com/micro-plat/hydra/hydra/servers/pkg/middleware"
 )
@@ -15,6 +17,8 @@ type option struct {
 	metric            *middleware.Metric
 	serverType        string
 	ginTrace          bool
+	tls               *tls.Config
+	maxHeaderBytes    int
 }
 
 //Option 配置选项
@@ -42,3 +46,17 @@ func WithGinTrace(b bool) Option {
 		o.ginTrace = b
 	}
 }
+
+//WithTLS 使用tls配置启动https服务
+func WithTLS(cfg *tls.Config) Option {
+	return func(o *option) {
+		o.tls = cfg
+	}
+}
+
+//WithMaxHeaderBytes 设置请求头最大字节数
+func WithMaxHeaderBytes(size int) Option {
+	return func(o *option) {
+		o.maxHeaderBytes = size
+	}
+}
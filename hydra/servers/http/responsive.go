@@ -7,8 +7,11 @@ import (
 	"github.com/micro-plat/hydra/conf"
 	"github.com/micro-plat/hydra/conf/app"
 	"github.com/micro-plat/hydra/conf/server/api"
+	tlsconf "github.com/micro-plat/hydra/conf/server/tls"
+	xctx "github.com/micro-plat/hydra/context/ctx"
 	"github.com/micro-plat/hydra/global"
 	"github.com/micro-plat/hydra/hydra/servers"
+	"github.com/micro-plat/hydra/hydra/servers/http/ws"
 	"github.com/micro-plat/hydra/registry/pub"
 	"github.com/micro-plat/hydra/services"
 	"github.com/micro-plat/lib4go/logger"
@@ -51,6 +54,14 @@ func (w *Responsive) Start() (err error) {
 		return
 	}
 
+	//ws服务器启动前订阅消息分发通道
+	if w.conf.GetServerConf().GetServerType() == WS {
+		if err = ws.Start(); err != nil {
+			err = fmt.Errorf("%s启动失败 %w", w.conf.GetServerConf().GetServerType(), err)
+			return
+		}
+	}
+
 	if err = w.Server.Start(); err != nil {
 		err = fmt.Errorf("%s启动失败 %w", w.conf.GetServerConf().GetServerType(), err)
 		return
@@ -78,7 +89,7 @@ func (w *Responsive) Notify(c app.IAPPConf) (change bool, err error) {
 	if !w.comparer.IsChanged() {
 		return false, nil
 	}
-	if w.comparer.IsValueChanged() || w.comparer.IsSubConfChanged() {
+	if w.comparer.IsValueChanged() || w.comparer.IsSubConfChanged() || w.isTLSChanged(c) {
 		w.log.Info("关键配置发生变化，准备重启服务器")
 		server, err := w.getServer(c)
 		if err != nil {
@@ -109,6 +120,7 @@ func (w *Responsive) Shutdown() {
 	w.log.Infof("关闭[%s]服务...", w.conf.GetServerConf().GetServerType())
 	w.Server.Shutdown()
 	w.pub.Clear()
+	xctx.CloseTracer(w.conf.GetServerConf().GetServerPath())
 	if err := services.Def.DoClosing(w.conf); err != nil {
 		w.log.Infof("关闭[%s]服务,出现错误", err)
 		return
@@ -154,31 +166,41 @@ func (w *Responsive) getServer(cnf app.IAPPConf) (*Server, error) {
 	if err != nil {
 		return nil, err
 	}
+	opts := []Option{
+		WithServerType(tp),
+		WithTimeout(apiConf.GetRTimeout(), apiConf.GetWTimeout(), apiConf.GetRHTimeout()),
+		WithGinTrace(apiConf.Trace),
+		WithMaxHeaderBytes(apiConf.GetMaxHeaderBytes()),
+	}
+
+	//启用tls时证书由注册中心配置动态加载
+	tlsConf, err := cnf.GetTLSConf()
+	if err != nil {
+		return nil, err
+	}
+	if tlsConf.IsEnabled() {
+		cfg, err := tlsconf.NewServerConfig(tlsConf, cnf.GetVarConf(), app.TLSGetter(tp), "http/1.1")
+		if err != nil {
+			return nil, fmt.Errorf("%s tls配置有误 %w", tp, err)
+		}
+		opts = append(opts, WithTLS(cfg))
+	}
 	switch tp {
 	case WS:
-		return NewWSServer(tp,
-			apiConf.GetWSAddress(),
-			routerconf.GetRouters(),
-			WithServerType(tp),
-			WithTimeout(apiConf.GetRTimeout(), apiConf.GetWTimeout(), apiConf.GetRHTimeout()),
-			WithGinTrace(apiConf.Trace))
+		return NewWSServer(tp, apiConf.GetWSAddress(), routerconf.GetRouters(), opts...)
 	case Web:
-		return NewServer(tp,
-			apiConf.GetWEBAddress(),
-			routerconf.GetRouters(),
-			WithServerType(tp),
-			WithTimeout(apiConf.GetRTimeout(), apiConf.GetWTimeout(), apiConf.GetRHTimeout()),
-			WithGinTrace(apiConf.Trace))
+		return NewServer(tp, apiConf.GetWEBAddress(), routerconf.GetRouters(), opts...)
 	default:
-		return NewServer(tp,
-			apiConf.GetAPIAddress(),
-			routerconf.GetRouters(),
-			WithServerType(tp),
-			WithTimeout(apiConf.GetRTimeout(), apiConf.GetWTimeout(), apiConf.GetRHTimeout()),
-			WithGinTrace(apiConf.Trace))
+		return NewServer(tp, apiConf.GetAPIAddress(), routerconf.GetRouters(), opts...)
 	}
 }
 
+//isTLSChanged tls启用状态发生变化，须重启服务器;证书变更时由tls配置动态加载，无需重启
+func (w *Responsive) isTLSChanged(c app.IAPPConf) bool {
+	tlsConf, err := c.GetTLSConf()
+	return err == nil && tlsConf.IsEnabled() != w.Server.IsTLS()
+}
+
 func init() {
 	fn := func(c app.IAPPConf) (servers.IResponsiveServer, error) {
 		return NewResponsive(c)
@@ -16,25 +16,31 @@ func (s *Server) addHttpRouters(routers ...*router.Router) {
 	s.engine.Use(middleware.Recovery().GinFunc(s.serverType))
 	s.engine.Use(middleware.Logging().GinFunc()) //记录请求日志
 	s.engine.Use(middleware.Recovery().GinFunc())
-	// s.engine.Use(middleware.APM().GinFunc())       //链数跟踪
-	s.engine.Use(middleware.Trace().GinFunc())     //跟踪信息
-	s.engine.Use(middleware.BlackList().GinFunc()) //黑名单控制
-	s.engine.Use(middleware.WhiteList().GinFunc()) //白名单控制
-	s.engine.Use(middleware.Proxy().GinFunc())     //灰度配置
-	s.engine.Use(middleware.Delay().GinFunc())     //
-	s.engine.Use(middleware.Limit().GinFunc())     //限流处理
-	s.engine.Use(middleware.Static().GinFunc())    //处理静态文件
-	s.engine.Use(middleware.Header().GinFunc())    //设置请求头
-	s.engine.Use(middleware.Options().GinFunc())   //处理option响应
-	s.engine.Use(middleware.BasicAuth().GinFunc()) //
+	s.engine.Use(middleware.APM().GinFunc())                  //链路跟踪
+	s.engine.Use(middleware.Trace().GinFunc())                //跟踪信息
+	s.engine.Use(middleware.RequestLimit(s.metric).GinFunc()) //请求大小限制
+	s.engine.Use(middleware.BlackList().GinFunc())            //黑名单控制
+	s.engine.Use(middleware.WhiteList().GinFunc())            //白名单控制
+	s.engine.Use(middleware.Proxy().GinFunc())                //灰度配置
+	s.engine.Use(middleware.Delay().GinFunc())                //
+	s.engine.Use(middleware.Limit().GinFunc())                //限流处理
+	s.engine.Use(middleware.Static().GinFunc())               //处理静态文件
+	s.engine.Use(middleware.Header().GinFunc())               //设置请求头
+	s.engine.Use(middleware.Options().GinFunc())              //处理option响应
+	s.engine.Use(middleware.BasicAuth().GinFunc())            //
 	s.engine.Use(middleware.APIKeyAuth().GinFunc())
 	s.engine.Use(middleware.RASAuth().GinFunc())
-	s.engine.Use(middleware.JwtAuth().GinFunc()) //jwt安全认证
+	s.engine.Use(middleware.OIDC().GinFunc())       //oidc登录
+	s.engine.Use(middleware.JwtAuth().GinFunc())    //jwt安全认证
+	s.engine.Use(middleware.RBAC().GinFunc())       //角色与权限检查
+	s.engine.Use(middleware.Idempotent().GinFunc()) //幂等请求处理
 	s.engine.Use(middlewares.GinFunc()...)
 
-	s.engine.Use(middleware.Render().GinFunc())    //响应渲染组件
-	s.engine.Use(middleware.JwtWriter().GinFunc()) //设置jwt回写
-	s.engine.Use(s.metric.Handle().GinFunc())      //生成metric报表
+	s.engine.Use(s.metric.Handle().GinFunc())          //生成metric报表
+	s.engine.Use(middleware.Compress().GinFunc())      //响应压缩(在渲染与jwt回写之后执行)
+	s.engine.Use(middleware.Render().GinFunc())        //响应渲染组件
+	s.engine.Use(middleware.JwtWriter().GinFunc())     //设置jwt回写
+	s.engine.Use(middleware.ResponseCache().GinFunc()) //响应缓存(命中时不再执行服务)
 
 	s.addRouter(routers...)
 	s.server.Handler = s.engine
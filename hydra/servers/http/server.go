@@ -42,7 +42,7 @@ func NewWSServer(name string, addr string, routers []*router.Router, opts ...Opt
 	if err != nil {
 		return
 	}
-	t.proto = "ws"
+	t.proto = types.DecodeString(t.tls == nil, true, "ws", "wss")
 	t.addWSRouters(routers...)
 	return
 }
@@ -50,18 +50,19 @@ func NewWSServer(name string, addr string, routers []*router.Router, opts ...Opt
 //new 创建http api服务嚣
 func new(name string, addr string, opts ...Option) (t *Server, err error) {
 	t = &Server{
-		proto: "http",
 		ip:    global.LocalIP(), // net.GetLocalIPAddress(),
 		option: &option{
 			readHeaderTimeout: 6,
 			readTimeout:       6,
 			writeTimeout:      6,
+			maxHeaderBytes:    1 << 20,
 			metric:            middleware.NewMetric(),
 		},
 	}
 	for _, opt := range opts {
 		opt(t.option)
 	}
+	t.proto = types.DecodeString(t.tls == nil, true, "http", "https")
 	t.host, t.port, err = global.GetHostPort(addr)
 	if err != nil {
 		return nil, err
@@ -71,7 +72,8 @@ func new(name string, addr string, opts ...Option) (t *Server, err error) {
 		ReadHeaderTimeout: time.Second * time.Duration(t.option.readHeaderTimeout),
 		ReadTimeout:       time.Second * time.Duration(t.option.readTimeout),
 		WriteTimeout:      time.Second * time.Duration(t.option.writeTimeout),
-		MaxHeaderBytes:    1 << 20,
+		MaxHeaderBytes:    t.option.maxHeaderBytes,
+		TLSConfig:         t.tls,
 	}
 	return
 }
@@ -82,6 +84,13 @@ func (s *Server) Start() error {
 	errChan := make(chan error, 1)
 
 	go func(ch chan error) {
+		if s.server.TLSConfig != nil {
+			//证书由TLSConfig动态提供
+			if err := s.server.ListenAndServeTLS("", ""); err != nil {
+				ch <- err
+			}
+			return
+		}
 		if err := s.server.ListenAndServe(); err != nil {
 			ch <- err
 		}
@@ -124,6 +133,11 @@ func (s *Server) GetAddress(h ...string) string {
 	return fmt.Sprintf("%s://%s:%s", s.proto, s.host, s.port)
 }
 
+//IsTLS 是否启用tls
+func (s *Server) IsTLS() bool {
+	return s.server.TLSConfig != nil
+}
+
 //GetStatus 获取当前服务器状态
 func (s *Server) GetStatus() string {
 	return types.DecodeString(s.running, true, "运行中", "停止")
@@ -0,0 +1,289 @@
+package ws
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	goredis "github.com/go-redis/redis"
+
+	"github.com/micro-plat/hydra/components/pkgs/redis"
+	"github.com/micro-plat/hydra/conf"
+	"github.com/micro-plat/hydra/conf/app"
+	"github.com/micro-plat/hydra/conf/vars/queue/queueredis"
+	varredis "github.com/micro-plat/hydra/conf/vars/redis"
+	"github.com/micro-plat/lib4go/logger"
+	"github.com/micro-plat/lib4go/utility"
+)
+
+//channelName 跨节点消息分发使用的发布订阅通道
+const channelName = "ws:exchange:broadcast"
+
+//presenceKey 节点的主题成员存储key，每个节点单独存储并设置过期时间，节点异常退出后自动清除
+const presenceKey = "ws:exchange:presence:%s:%s"
+
+//nodesKey 存活节点集合，分值为最后一次心跳时间
+const nodesKey = "ws:exchange:nodes"
+
+//presenceTTL 节点成员信息的过期时长，节点每隔presenceTTL/3发送一次心跳
+const presenceTTL = 30 * time.Second
+
+//IBroker 跨节点消息分发与主题成员存储，所有节点共用一个发布订阅通道
+type IBroker interface {
+	Publish(msg []byte) error
+	Subscribe(f func([]byte)) error
+	AddMember(topic string, uuid string, user string) error
+	RemoveMember(topic string, uuid string) error
+	GetMembers(topic string) (map[string]string, error)
+	Close() error
+}
+
+//newBroker 根据队列配置创建消息分发器，未配置时只在当前节点内分发
+func newBroker(name string) (IBroker, error) {
+	varConf, err := app.Cache.GetVarConf()
+	if err != nil {
+		return nil, err
+	}
+	raw, err := varConf.GetConf("queue", name)
+	if errors.Is(err, conf.ErrNoSetting) {
+		return newLocalBroker(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch proto := raw.GetString("proto"); proto {
+	case "redis":
+		return newRedisBroker(queueredis.NewByRaw(string(raw.GetRaw())).GetRaw())
+	default:
+		return nil, fmt.Errorf("ws消息分发不支持的队列类型:%s", proto)
+	}
+}
+
+//localBroker 单节点内的消息分发
+type localBroker struct {
+	handlers []func([]byte)
+	members  map[string]map[string]string
+	lock     sync.RWMutex
+}
+
+func newLocalBroker() *localBroker {
+	return &localBroker{
+		handlers: make([]func([]byte), 0, 1),
+		members:  make(map[string]map[string]string),
+	}
+}
+
+//Publish 发布消息
+func (b *localBroker) Publish(msg []byte) error {
+	b.lock.RLock()
+	handlers := b.handlers
+	b.lock.RUnlock()
+	for _, f := range handlers {
+		f(msg)
+	}
+	return nil
+}
+
+//Subscribe 订阅消息
+func (b *localBroker) Subscribe(f func([]byte)) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.handlers = append(b.handlers, f)
+	return nil
+}
+
+//AddMember 添加主题成员
+func (b *localBroker) AddMember(topic string, uuid string, user string) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if _, ok := b.members[topic]; !ok {
+		b.members[topic] = make(map[string]string)
+	}
+	b.members[topic][uuid] = user
+	return nil
+}
+
+//RemoveMember 移除主题成员
+func (b *localBroker) RemoveMember(topic string, uuid string) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	delete(b.members[topic], uuid)
+	if len(b.members[topic]) == 0 {
+		delete(b.members, topic)
+	}
+	return nil
+}
+
+//GetMembers 获取主题成员(uuid:user)
+func (b *localBroker) GetMembers(topic string) (map[string]string, error) {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	members := make(map[string]string, len(b.members[topic]))
+	for k, v := range b.members[topic] {
+		members[k] = v
+	}
+	return members, nil
+}
+
+//Close 关闭
+func (b *localBroker) Close() error {
+	return nil
+}
+
+//redisBroker 基于redis发布订阅的跨节点消息分发，主题成员按节点保存在redis hash中，通过心跳续期
+type redisBroker struct {
+	client *redis.Client
+	node   string
+	topics map[string]int
+	closer []func() error
+	done   chan struct{}
+	lock   sync.Mutex
+}
+
+func newRedisBroker(raw string) (*redisBroker, error) {
+	client, err := redis.NewByConfig(varredis.NewByRaw(raw))
+	if err != nil {
+		return nil, fmt.Errorf("ws消息分发连接redis失败:%w", err)
+	}
+	b := &redisBroker{
+		client: client,
+		node:   utility.GetGUID(),
+		topics: make(map[string]int),
+		done:   make(chan struct{}),
+	}
+	if err := b.heartbeat(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("ws消息分发注册节点失败:%w", err)
+	}
+	go b.loop()
+	return b, nil
+}
+
+//Publish 发布消息
+func (b *redisBroker) Publish(msg []byte) error {
+	return b.client.Publish(channelName, msg).Err()
+}
+
+//Subscribe 订阅消息，连接断开时自动重连
+func (b *redisBroker) Subscribe(f func([]byte)) error {
+	ps := b.client.Subscribe(channelName)
+	if _, err := ps.Receive(); err != nil {
+		ps.Close()
+		return fmt.Errorf("ws消息分发订阅失败:%w", err)
+	}
+	b.lock.Lock()
+	b.closer = append(b.closer, ps.Close)
+	b.lock.Unlock()
+	go func() {
+		for msg := range ps.Channel() {
+			f([]byte(msg.Payload))
+		}
+	}()
+	return nil
+}
+
+//AddMember 添加主题成员
+func (b *redisBroker) AddMember(topic string, uuid string, user string) error {
+	key := fmt.Sprintf(presenceKey, topic, b.node)
+	pipe := b.client.TxPipeline()
+	pipe.HSet(key, uuid, user)
+	pipe.Expire(key, presenceTTL)
+	if _, err := pipe.Exec(); err != nil {
+		return err
+	}
+	b.lock.Lock()
+	b.topics[topic]++
+	b.lock.Unlock()
+	return nil
+}
+
+//RemoveMember 移除主题成员
+func (b *redisBroker) RemoveMember(topic string, uuid string) error {
+	if err := b.client.HDel(fmt.Sprintf(presenceKey, topic, b.node), uuid).Err(); err != nil {
+		return err
+	}
+	b.lock.Lock()
+	if b.topics[topic]--; b.topics[topic] <= 0 {
+		delete(b.topics, topic)
+	}
+	b.lock.Unlock()
+	return nil
+}
+
+//GetMembers 获取所有存活节点上的主题成员(uuid:user)
+func (b *redisBroker) GetMembers(topic string) (map[string]string, error) {
+	min := time.Now().Add(-presenceTTL).Unix()
+	nodes, err := b.client.ZRangeByScore(nodesKey, goredis.ZRangeBy{Min: fmt.Sprint(min), Max: "+inf"}).Result()
+	if err != nil {
+		return nil, err
+	}
+	members := make(map[string]string)
+	for _, node := range nodes {
+		m, err := b.client.HGetAll(fmt.Sprintf(presenceKey, topic, node)).Result()
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range m {
+			members[k] = v
+		}
+	}
+	return members, nil
+}
+
+//Close 关闭订阅与连接，并清除当前节点的成员信息
+func (b *redisBroker) Close() error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	select {
+	case <-b.done:
+		return nil
+	default:
+		close(b.done)
+	}
+	for _, c := range b.closer {
+		c()
+	}
+	b.closer = nil
+	b.client.ZRem(nodesKey, b.node)
+	for topic := range b.topics {
+		b.client.Del(fmt.Sprintf(presenceKey, topic, b.node))
+	}
+	return b.client.Close()
+}
+
+//loop 定时发送心跳
+func (b *redisBroker) loop() {
+	tk := time.NewTicker(presenceTTL / 3)
+	defer tk.Stop()
+	for {
+		select {
+		case <-b.done:
+			return
+		case <-tk.C:
+			if err := b.heartbeat(); err != nil {
+				logger.New("ws.exchange").Errorf("ws消息分发心跳失败:%v", err)
+			}
+		}
+	}
+}
+
+//heartbeat 更新节点存活时间并延长当前节点成员信息的过期时间，同时清除已失效的节点
+func (b *redisBroker) heartbeat() error {
+	now := time.Now()
+	b.lock.Lock()
+	topics := make([]string, 0, len(b.topics))
+	for topic := range b.topics {
+		topics = append(topics, topic)
+	}
+	b.lock.Unlock()
+
+	pipe := b.client.Pipeline()
+	pipe.ZAdd(nodesKey, goredis.Z{Score: float64(now.Unix()), Member: b.node})
+	pipe.ZRemRangeByScore(nodesKey, "-inf", fmt.Sprint(now.Add(-presenceTTL).Unix()))
+	for _, topic := range topics {
+		pipe.Expire(fmt.Sprintf(presenceKey, topic, b.node), presenceTTL)
+	}
+	_, err := pipe.Exec()
+	return err
+}
@@ -1,14 +1,13 @@
 package ws
 
 import (
+	"encoding/json"
 	"fmt"
-	"net/http"
+	"sort"
 	"sync"
 
-	"github.com/micro-plat/hydra"
-	"github.com/micro-plat/hydra/context"
-	"github.com/micro-plat/lib4go/concurrent/cmap"
-	"github.com/micro-plat/lib4go/errs"
+	"github.com/micro-plat/lib4go/jsons"
+	"github.com/micro-plat/lib4go/logger"
 )
 
 //WSExchange web socket message exchange
@@ -19,75 +18,347 @@ var DataExchange IDataExchange = exchange
 
 var confName = "queue"
 
-//Conf 配置管理
+//Conf 配置管理，使用指定的队列配置进行跨节点消息分发
 func Conf(queueConfName string) {
 	confName = queueConfName
 }
 
+//UseBroker 使用自定义的消息分发器
+func UseBroker(b IBroker) error {
+	return exchange.setBroker(b)
+}
+
+//Start 创建消息分发器并订阅分发通道，服务器启动时调用，订阅失败时返回错误
+func Start() error {
+	_, err := exchange.getBroker()
+	return err
+}
+
 //IDataExchange 数据交换接口
 type IDataExchange interface {
 	Notify(uuid string, data interface{}) error
+	NotifyUser(user string, data interface{}) error
+	Broadcast(topic string, data interface{}) error
+	Join(uuid string, topics ...string) error
+	Leave(uuid string, topics ...string) error
+	Presence(topic string) ([]*Member, error)
+}
+
+const (
+	opConn  = "conn"
+	opUser  = "user"
+	opTopic = "topic"
+	opJoin  = "join"
+	opLeave = "leave"
+)
+
+//Member 主题成员
+type Member struct {
+	UUID string `json:"uuid"`
+	User string `json:"user,omitempty"`
+}
+
+//message 节点间分发的消息
+type message struct {
+	Op     string          `json:"op"`
+	Target string          `json:"target"`
+	Topics []string        `json:"topics,omitempty"`
+	Data   json.RawMessage `json:"data,omitempty"`
+}
+
+//conn 当前节点的连接
+type conn struct {
+	user     string
+	callback func(...interface{}) error
+	topics   map[string]bool
 }
 
-//Exchange 数据交换中心
+//Exchange 数据交换中心，所有节点通过一个发布订阅通道分发消息，由持有连接的节点发送给客户端
 type Exchange struct {
-	uuid            cmap.ConcurrentMap
-	queueFormatName string
-	service         string
-	once            sync.Once
+	conns  map[string]*conn
+	users  map[string]map[string]bool
+	topics map[string]map[string]bool
+	lock   sync.RWMutex
+	broker IBroker
+	block  sync.Mutex
+	log    logger.ILogger
 }
 
 //NewExchange 构建数据交换中心
 func NewExchange() *Exchange {
 	return &Exchange{
-		uuid:            cmap.New(8),
-		queueFormatName: "ws:exchange:%s",
-		service:         "/ws/handle",
+		conns:  make(map[string]*conn),
+		users:  make(map[string]map[string]bool),
+		topics: make(map[string]map[string]bool),
+		log:    logger.New("ws.exchange"),
 	}
 }
 
-//Subscribe 订阅消息通知
-func (e *Exchange) Subscribe(uuid string, f func(...interface{}) error) error {
-	e.once.Do(func() {
-		hydra.S.MQC(e.service, e.handle) //注册MQC服务
-	})
-
-	if ok, _ := e.uuid.SetIfAbsent(uuid, f); ok {
-		hydra.MQC.Add(e.getQueueName(uuid), e.service) //为每个用户添加处理队列
+//Subscribe 订阅消息通知，user为当前连接的用户标识，可为空
+func (e *Exchange) Subscribe(uuid string, user string, f func(...interface{}) error) error {
+	e.lock.Lock()
+	e.conns[uuid] = &conn{user: user, callback: f, topics: make(map[string]bool)}
+	if user != "" {
+		add(e.users, user, uuid)
 	}
-	return nil
+	e.lock.Unlock()
+
+	_, err := e.getBroker()
+	return err
 }
 
-//Unsubscribe 取消订阅
+//Unsubscribe 取消订阅，并退出已加入的主题
 func (e *Exchange) Unsubscribe(uuid string) {
-	e.uuid.Remove(uuid)
-	hydra.MQC.Remove(e.getQueueName(uuid), e.service) //关闭队列
+	e.lock.Lock()
+	c, ok := e.conns[uuid]
+	if !ok {
+		e.lock.Unlock()
+		return
+	}
+	delete(e.conns, uuid)
+	remove(e.users, c.user, uuid)
+	topics := make([]string, 0, len(c.topics))
+	for topic := range c.topics {
+		remove(e.topics, topic, uuid)
+		topics = append(topics, topic)
+	}
+	e.lock.Unlock()
+	e.removeMembers(uuid, topics...)
 }
 
-//Notify 发送通知消息
-func (e *Exchange) Notify(uuid string, msg interface{}) error {
-	queue, err := hydra.C.Queue().GetQueue(confName)
+//Notify 向指定连接发送通知消息
+func (e *Exchange) Notify(uuid string, data interface{}) error {
+	return e.publish(opConn, uuid, data)
+}
+
+//NotifyUser 向用户的所有连接发送通知消息
+func (e *Exchange) NotifyUser(user string, data interface{}) error {
+	return e.publish(opUser, user, data)
+}
+
+//Broadcast 向加入主题的所有连接发送消息
+func (e *Exchange) Broadcast(topic string, data interface{}) error {
+	return e.publish(opTopic, topic, data)
+}
+
+//Join 将连接加入主题，连接不在当前节点时由持有连接的节点处理
+func (e *Exchange) Join(uuid string, topics ...string) error {
+	if !e.join(uuid, topics...) {
+		return e.publish(opJoin, uuid, nil, topics...)
+	}
+	return e.addMembers(uuid, topics...)
+}
+
+//Leave 将连接退出主题，连接不在当前节点时由持有连接的节点处理
+func (e *Exchange) Leave(uuid string, topics ...string) error {
+	if !e.leave(uuid, topics...) {
+		return e.publish(opLeave, uuid, nil, topics...)
+	}
+	return e.removeMembers(uuid, topics...)
+}
+
+//Presence 获取主题的所有成员(包含其它节点上的连接)
+func (e *Exchange) Presence(topic string) ([]*Member, error) {
+	broker, err := e.getBroker()
+	if err != nil {
+		return nil, err
+	}
+	members, err := broker.GetMembers(topic)
+	if err != nil {
+		return nil, fmt.Errorf("获取主题(%s)成员失败:%w", topic, err)
+	}
+	list := make([]*Member, 0, len(members))
+	for uuid, user := range members {
+		list = append(list, &Member{UUID: uuid, User: user})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].UUID < list[j].UUID })
+	return list, nil
+}
+
+//publish 发布消息到所有节点
+func (e *Exchange) publish(op string, target string, data interface{}, topics ...string) error {
+	broker, err := e.getBroker()
 	if err != nil {
 		return err
 	}
-	return queue.Send(e.getQueueName(uuid), msg, uuid)
+	msg := &message{Op: op, Target: target, Topics: topics}
+	if data != nil {
+		if msg.Data, err = jsons.Marshal(data); err != nil {
+			return fmt.Errorf("消息格式有误:%w", err)
+		}
+	}
+	buff, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if err := broker.Publish(buff); err != nil {
+		return fmt.Errorf("发布消息失败:%w", err)
+	}
+	return nil
 }
 
-//handle 业务回调处理
-func (e *Exchange) handle(ctx context.IContext) interface{} {
-	uuid := ctx.User().GetTraceID()
-	v, ok := e.uuid.Get(uuid)
+//handle 处理其它节点分发的消息，只处理当前节点持有的连接
+func (e *Exchange) handle(buff []byte) {
+	msg := &message{}
+	if err := json.Unmarshal(buff, msg); err != nil {
+		e.log.Errorf("ws消息格式有误:%v", err)
+		return
+	}
+	switch msg.Op {
+	case opJoin:
+		if e.join(msg.Target, msg.Topics...) {
+			e.addMembers(msg.Target, msg.Topics...)
+		}
+		return
+	case opLeave:
+		if e.leave(msg.Target, msg.Topics...) {
+			e.removeMembers(msg.Target, msg.Topics...)
+		}
+		return
+	}
+	for _, f := range e.getCallbacks(msg.Op, msg.Target) {
+		e.notify(f, msg.Data)
+	}
+}
+
+//notify 向连接发送消息，连接已关闭时忽略
+func (e *Exchange) notify(f func(...interface{}) error, data json.RawMessage) {
+	defer func() {
+		if r := recover(); r != nil {
+			e.log.Debugf("ws连接已关闭:%v", r)
+		}
+	}()
+	if err := f(data); err != nil {
+		e.log.Error(err)
+	}
+}
+
+//getCallbacks 获取需要通知的连接
+func (e *Exchange) getCallbacks(op string, target string) []func(...interface{}) error {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+	var uuids map[string]bool
+	switch op {
+	case opConn:
+		uuids = map[string]bool{target: true}
+	case opUser:
+		uuids = e.users[target]
+	case opTopic:
+		uuids = e.topics[target]
+	}
+	list := make([]func(...interface{}) error, 0, len(uuids))
+	for uuid := range uuids {
+		if c, ok := e.conns[uuid]; ok {
+			list = append(list, c.callback)
+		}
+	}
+	return list
+}
+
+//join 当前节点的连接加入主题，连接不存在时返回false
+func (e *Exchange) join(uuid string, topics ...string) bool {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	c, ok := e.conns[uuid]
+	if !ok {
+		return false
+	}
+	for _, topic := range topics {
+		c.topics[topic] = true
+		add(e.topics, topic, uuid)
+	}
+	return true
+}
+
+//leave 当前节点的连接退出主题，连接不存在时返回false
+func (e *Exchange) leave(uuid string, topics ...string) bool {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	c, ok := e.conns[uuid]
 	if !ok {
-		return errs.NewError(http.StatusNoContent, nil)
+		return false
+	}
+	for _, topic := range topics {
+		delete(c.topics, topic)
+		remove(e.topics, topic, uuid)
+	}
+	return true
+}
+
+func (e *Exchange) addMembers(uuid string, topics ...string) error {
+	broker, err := e.getBroker()
+	if err != nil {
+		return err
+	}
+	e.lock.RLock()
+	user := ""
+	if c, ok := e.conns[uuid]; ok {
+		user = c.user
 	}
-	callback := v.(func(...interface{}) error)
-	body, err := ctx.Request().GetBody()
+	e.lock.RUnlock()
+	for _, topic := range topics {
+		if err := broker.AddMember(topic, uuid, user); err != nil {
+			return fmt.Errorf("加入主题(%s)失败:%w", topic, err)
+		}
+	}
+	return nil
+}
+
+func (e *Exchange) removeMembers(uuid string, topics ...string) error {
+	broker, err := e.getBroker()
+	if err != nil {
+		return err
+	}
+	for _, topic := range topics {
+		if err := broker.RemoveMember(topic, uuid); err != nil {
+			return fmt.Errorf("退出主题(%s)失败:%w", topic, err)
+		}
+	}
+	return nil
+}
+
+//getBroker 获取消息分发器，首次使用时创建并订阅分发通道
+func (e *Exchange) getBroker() (IBroker, error) {
+	e.block.Lock()
+	defer e.block.Unlock()
+	if e.broker != nil {
+		return e.broker, nil
+	}
+	broker, err := newBroker(confName)
 	if err != nil {
+		return nil, fmt.Errorf("创建ws消息分发器失败:%w", err)
+	}
+	if err := broker.Subscribe(e.handle); err != nil {
+		broker.Close()
+		return nil, err
+	}
+	e.broker = broker
+	return broker, nil
+}
+
+func (e *Exchange) setBroker(b IBroker) error {
+	e.block.Lock()
+	defer e.block.Unlock()
+	if err := b.Subscribe(e.handle); err != nil {
 		return err
 	}
-	return callback(string(body))
+	if e.broker != nil {
+		e.broker.Close()
+	}
+	e.broker = b
+	return nil
 }
 
-func (e *Exchange) getQueueName(id string) string {
-	return fmt.Sprintf(e.queueFormatName, id)
+func add(m map[string]map[string]bool, key string, uuid string) {
+	if _, ok := m[key]; !ok {
+		m[key] = make(map[string]bool)
+	}
+	m[key][uuid] = true
+}
+
+func remove(m map[string]map[string]bool, key string, uuid string) {
+	delete(m[key], uuid)
+	if len(m[key]) == 0 {
+		delete(m, key)
+	}
 }
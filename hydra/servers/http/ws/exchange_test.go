@@ -0,0 +1,77 @@
+package ws
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/micro-plat/lib4go/assert"
+)
+
+type recorder struct {
+	msgs []string
+}
+
+func (r *recorder) callback(input ...interface{}) error {
+	r.msgs = append(r.msgs, string(input[0].(json.RawMessage)))
+	return nil
+}
+
+func TestExchange_Broadcast(t *testing.T) {
+	e := NewExchange()
+	assert.Equal(t, nil, e.setBroker(newLocalBroker()), "设置消息分发器")
+
+	a, b, c := &recorder{}, &recorder{}, &recorder{}
+	e.Subscribe("a", "u1", a.callback)
+	e.Subscribe("b", "u1", b.callback)
+	e.Subscribe("c", "u2", c.callback)
+
+	//加入主题
+	assert.Equal(t, nil, e.Join("a", "room"), "加入主题")
+	assert.Equal(t, nil, e.Join("c", "room", "dashboard"), "加入主题")
+	members, err := e.Presence("room")
+	assert.Equal(t, nil, err, "获取主题成员")
+	assert.Equal(t, []*Member{{UUID: "a", User: "u1"}, {UUID: "c", User: "u2"}}, members, "主题成员")
+
+	//主题广播
+	assert.Equal(t, nil, e.Broadcast("room", map[string]string{"msg": "hello"}), "主题广播")
+	assert.Equal(t, []string{`{"msg":"hello"}`}, a.msgs, "a收到主题消息")
+	assert.Equal(t, 0, len(b.msgs), "b未加入主题")
+	assert.Equal(t, []string{`{"msg":"hello"}`}, c.msgs, "c收到主题消息")
+
+	//发送给用户的所有连接
+	assert.Equal(t, nil, e.NotifyUser("u1", "bye"), "用户通知")
+	assert.Equal(t, `"bye"`, a.msgs[1], "a收到用户消息")
+	assert.Equal(t, []string{`"bye"`}, b.msgs, "b收到用户消息")
+	assert.Equal(t, 1, len(c.msgs), "c不是该用户")
+
+	//发送给指定连接
+	assert.Equal(t, nil, e.Notify("b", 1), "连接通知")
+	assert.Equal(t, []string{`"bye"`, `1`}, b.msgs, "b收到连接消息")
+
+	//退出主题与断开连接
+	assert.Equal(t, nil, e.Leave("a", "room"), "退出主题")
+	e.Unsubscribe("c")
+	members, _ = e.Presence("room")
+	assert.Equal(t, 0, len(members), "主题无成员")
+	members, _ = e.Presence("dashboard")
+	assert.Equal(t, 0, len(members), "断开连接后自动退出主题")
+	e.Broadcast("room", "none")
+	assert.Equal(t, 2, len(a.msgs), "已退出主题")
+}
+
+func TestExchange_Remote(t *testing.T) {
+	broker := newLocalBroker()
+	n1, n2 := NewExchange(), NewExchange()
+	n1.setBroker(broker)
+	n2.setBroker(broker)
+
+	a := &recorder{}
+	n1.Subscribe("a", "u1", a.callback)
+
+	//在其它节点为连接加入主题并广播
+	assert.Equal(t, nil, n2.Join("a", "room"), "跨节点加入主题")
+	members, _ := n2.Presence("room")
+	assert.Equal(t, []*Member{{UUID: "a", User: "u1"}}, members, "跨节点查询成员")
+	assert.Equal(t, nil, n2.Broadcast("room", "hi"), "跨节点广播")
+	assert.Equal(t, []string{`"hi"`}, a.msgs, "收到跨节点消息")
+}
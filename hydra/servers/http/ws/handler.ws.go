@@ -6,6 +6,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
+	"github.com/micro-plat/hydra/conf/server/header"
+	wsconf "github.com/micro-plat/hydra/conf/server/ws"
 	"github.com/micro-plat/hydra/hydra/servers/pkg/middleware"
 )
 
@@ -20,15 +22,27 @@ func WSExecuteHandler(service string) middleware.Handler {
 		}
 		c := n.(*gin.Context)
 
-		conn, err := getUpgrader(c.Writer, c.Request, c.Request.Header)
+		wsConf, err := ctx.APPConf().GetWSConnConf()
+		if err != nil {
+			ctx.Response().Abort(http.StatusNotExtended, err)
+			return
+		}
+		headers, err := ctx.APPConf().GetHeaderConf()
+		if err != nil {
+			ctx.Response().Abort(http.StatusNotExtended, err)
+			return
+		}
+		conn, err := getUpgrader(c.Writer, c.Request, wsConf, headers)
 		if err != nil {
 			ctx.Response().Write(http.StatusNotAcceptable, fmt.Errorf("无法初始化ws.upgrader %w", err))
 			return
 		}
 
 		//构建处理函数
-		h := newWSHandler(conn, ctx.User().GetTraceID(), ctx.User().GetClientIP())
-		exchange.Subscribe(ctx.User().GetTraceID(), h.recvNotify(c))
+		h := newWSHandler(conn, ctx.User().GetTraceID(), ctx.User().GetClientIP(), wsConf)
+		if err := exchange.Subscribe(ctx.User().GetTraceID(), getUser(ctx), h.recvNotify(c)); err != nil {
+			ctx.Log().Error(err)
+		}
 		defer exchange.Unsubscribe(ctx.User().GetTraceID())
 
 		//异步读取与写入
@@ -37,14 +51,31 @@ func WSExecuteHandler(service string) middleware.Handler {
 		ctx.Response().NoNeedWrite(c.Writer.Status())
 	}
 }
-func getUpgrader(w http.ResponseWriter, r *http.Request, h http.Header) (*websocket.Conn, error) {
+
+//getUser 获取当前连接的用户标识，未启用jwt认证时为空
+func getUser(ctx middleware.IMiddleContext) string {
+	jwt, err := ctx.APPConf().GetJWTConf()
+	if err != nil || jwt.Disable {
+		return ""
+	}
+	return jwt.GetUser(ctx.User().Auth().Request())
+}
+
+func getUpgrader(w http.ResponseWriter, r *http.Request, cnf *wsconf.Conn, h header.Headers) (*websocket.Conn, error) {
+	rsize, wsize := cnf.GetBufferSize()
 	var upgrader = websocket.Upgrader{
-		ReadBufferSize:  1024,
-		WriteBufferSize: 1024,
-		Subprotocols:    []string{h.Get("Sec-WebSocket-Extensions")},
+		ReadBufferSize:    rsize,
+		WriteBufferSize:   wsize,
+		EnableCompression: cnf.Compression,
+		Subprotocols:      cnf.Subprotocols,
 		CheckOrigin: func(r *http.Request) bool {
-			return true
+			return cnf.AllowOrigin(r.Header.Get("Origin"), r.Host, h)
 		},
 	}
-	return upgrader.Upgrade(w, r, nil)
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return nil, err
+	}
+	conn.EnableWriteCompression(cnf.Compression)
+	return conn, nil
 }
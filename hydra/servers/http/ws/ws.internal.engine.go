@@ -28,12 +28,14 @@ func newWSEngine(routers ...*router.Router) *wsEngine {
 	s.Engine.Use(middleware.Logging().DispFunc()) //记录请求日志
 	s.Engine.Use(middleware.Recovery().DispFunc())
 	s.Engine.Use(middleware.Tag().DispFunc())
+	s.Engine.Use(middleware.APM().DispFunc())   //链路跟踪
 	s.Engine.Use(middleware.Trace().DispFunc()) //跟踪信息
 	s.Engine.Use(middleware.Limit().DispFunc()) //限流处理
 	s.Engine.Use(middleware.Delay().DispFunc()) //
 	s.Engine.Use(middleware.APIKeyAuth().DispFunc())
 	s.Engine.Use(middleware.RASAuth().DispFunc())
 	s.Engine.Use(middleware.JwtAuth().DispFunc())   //jwt安全认证
+	s.Engine.Use(middleware.RBAC().DispFunc())      //角色与权限检查
 	s.Engine.Use(middleware.Render().DispFunc())    //响应渲染组件
 	s.Engine.Use(middleware.JwtWriter().DispFunc()) //设置jwt回写
 	s.Engine.Use(middlewares.DispFunc()...)
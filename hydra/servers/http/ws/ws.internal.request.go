@@ -1,6 +1,7 @@
 package ws
 
 import (
+	"bytes"
 	"encoding/json"
 
 	"github.com/micro-plat/hydra/context"
@@ -51,6 +52,39 @@ func NewRequest(method string, content []byte, uuid string, clientip string, opt
 	return r, nil
 }
 
+//NewBinaryRequest 构建二进制消息请求，原始字节作为请求body传入服务，可通过ctx.Request().GetBody()获取。
+//消息以json头信息(包含service等参数)+换行符开头时，头信息作为请求参数，其余字节作为body；
+//否则整个消息作为body，由默认服务处理
+func NewBinaryRequest(method string, content []byte, uuid string, clientip string, opts ...WSOption) (r *Request, err error) {
+	r = &Request{
+		method: method,
+		form:   make(map[string]interface{}),
+		header: map[string]string{
+			context.XRequestID: uuid,
+			"Client-IP":        clientip,
+			"Content-Type":     "application/octet-stream",
+		},
+	}
+	for _, o := range opts {
+		o(r)
+	}
+	r.form["__body__"] = content
+	if len(content) == 0 || content[0] != '{' {
+		return r, nil
+	}
+	index := bytes.IndexByte(content, '\n')
+	if index < 0 {
+		return r, nil
+	}
+	form := make(map[string]interface{})
+	if err := json.Unmarshal(content[:index], &form); err != nil {
+		return r, nil
+	}
+	r.form = form
+	r.form["__body__"] = content[index+1:]
+	return r, nil
+}
+
 //GetName 获取任务名称
 func (m *Request) GetName() string {
 	return m.form.GetString("service", "/")
@@ -0,0 +1,25 @@
+package ws
+
+import (
+	"testing"
+
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func TestNewBinaryRequest(t *testing.T) {
+	req, err := NewBinaryRequest("GET", append([]byte(`{"service":"/upload","name":"a.png"}`+"\n"), 0x89, 0x50, 0x0a, 0x00), "uuid", "127.0.0.1")
+	assert.Equal(t, nil, err, "构建二进制请求")
+	assert.Equal(t, "/upload", req.GetService(), "服务名")
+	assert.Equal(t, "a.png", req.GetForm()["name"], "头信息参数")
+	assert.Equal(t, []byte{0x89, 0x50, 0x0a, 0x00}, req.GetForm()["__body__"], "原始字节")
+	assert.Equal(t, "application/octet-stream", req.GetHeader()["Content-Type"], "内容类型")
+
+	req, err = NewBinaryRequest("GET", []byte{0x89, 0x50, 0x0a}, "uuid", "127.0.0.1")
+	assert.Equal(t, nil, err, "原始二进制消息")
+	assert.Equal(t, "/", req.GetService(), "使用默认服务")
+	assert.Equal(t, []byte{0x89, 0x50, 0x0a}, req.GetForm()["__body__"], "整个消息作为body")
+
+	req, err = NewBinaryRequest("GET", []byte("{abc\n1"), "uuid", "127.0.0.1")
+	assert.Equal(t, nil, err, "头信息不是json")
+	assert.Equal(t, []byte("{abc\n1"), req.GetForm()["__body__"], "整个消息作为body")
+}
@@ -2,26 +2,12 @@ package ws
 
 import (
 	"sync"
-	"time"
 
 	"github.com/gorilla/websocket"
+	wsconf "github.com/micro-plat/hydra/conf/server/ws"
 	"github.com/micro-plat/lib4go/logger"
 )
 
-const (
-	// Time allowed to write a message to the peer.
-	writeWait = 10 * time.Second
-
-	// Time allowed to read the next pong message from the peer.
-	pongWait = 60 * time.Second
-
-	// Send pings to peer with this period. Must be less than pongWait.
-	pingPeriod = (pongWait * 9) / 10
-
-	// Maximum message size allowed from peer.
-	maxMessageSize = 512
-)
-
 var (
 	newline = []byte{'\n'}
 	space   = []byte{' '}
@@ -37,10 +23,11 @@ type wsHandler struct {
 	uuid      string
 	log       logger.ILogger
 	clientip  string
+	conf      *wsconf.Conn
 }
 
 //newWSHandler 使用新引擎进行业务处理
-func newWSHandler(conn *websocket.Conn, uuid string, clientip string) *wsHandler {
+func newWSHandler(conn *websocket.Conn, uuid string, clientip string, conf *wsconf.Conn) *wsHandler {
 	if wsInternalEngine == nil {
 		panic("ws internal engine未初始化")
 	}
@@ -48,10 +35,11 @@ func newWSHandler(conn *websocket.Conn, uuid string, clientip string) *wsHandler
 		conn:      conn,
 		uuid:      uuid,
 		closeChan: make(chan struct{}),
-		send:      make(chan []byte, 256),
+		send:      make(chan []byte, conf.GetSendBuffer()),
 		log:       logger.GetSession("ws", uuid),
 		clientip:  clientip,
 		engine:    wsInternalEngine,
+		conf:      conf,
 	}
 	return s
 }
@@ -13,9 +13,9 @@ func (c *wsHandler) readPump() {
 	defer func() {
 		c.close()
 	}()
-	c.conn.SetReadLimit(maxMessageSize)
-	c.conn.SetReadDeadline(time.Now().Add(pongWait))
-	c.conn.SetPongHandler(func(string) error { c.conn.SetReadDeadline(time.Now().Add(pongWait)); return nil })
+	c.conn.SetReadLimit(c.conf.GetMaxMessageSize())
+	c.conn.SetReadDeadline(time.Now().Add(c.conf.GetReadTimeout()))
+	c.conn.SetPongHandler(func(string) error { c.conn.SetReadDeadline(time.Now().Add(c.conf.GetReadTimeout())); return nil })
 	for {
 		select {
 		case <-c.closeChan:
@@ -42,13 +42,18 @@ func (c *wsHandler) wsAction() error {
 		return err
 	}
 
-	//检查消息类型
-	if tp != websocket.TextMessage && tp != websocket.BinaryMessage {
+	c.conn.SetReadDeadline(time.Now().Add(c.conf.GetReadTimeout()))
+
+	//构建请求，二进制消息以原始字节传入服务
+	var req *Request
+	switch tp {
+	case websocket.TextMessage:
+		req, err = NewRequest(http.MethodGet, msg, c.uuid, c.clientip)
+	case websocket.BinaryMessage:
+		req, err = NewBinaryRequest(http.MethodGet, msg, c.uuid, c.clientip)
+	default:
 		return nil
 	}
-
-	//构建请求
-	req, err := NewRequest(http.MethodGet, msg, c.uuid, c.clientip)
 	if err != nil {
 		c.log.Errorf("消息有误:%v", err)
 		c.sendNow("/ws.init", http.StatusNotAcceptable, err)
@@ -11,7 +11,7 @@ import (
 
 //writePump 向客户端写入响应消息
 func (c *wsHandler) writePump() {
-	ticker := time.NewTicker(pingPeriod)
+	ticker := time.NewTicker(c.conf.GetPingPeriod())
 	defer func() {
 		ticker.Stop()
 		c.close()
@@ -19,7 +19,7 @@ func (c *wsHandler) writePump() {
 	for {
 		select {
 		case message, ok := <-c.send:
-			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			c.conn.SetWriteDeadline(time.Now().Add(c.conf.GetWriteTimeout()))
 			if !ok {
 				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
 				c.conn.Close()
@@ -41,7 +41,7 @@ func (c *wsHandler) writePump() {
 				break
 			}
 		case <-ticker.C:
-			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			c.conn.SetWriteDeadline(time.Now().Add(c.conf.GetWriteTimeout()))
 			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
 				c.close()
 				break
@@ -49,6 +49,7 @@ func NewProcessor(proto string, confRaw string) (p *Processor, err error) {
 	p.Engine.Use(middleware.Recovery().DispFunc(MQC))
 	p.Engine.Use(middleware.Logging().DispFunc())
 	p.Engine.Use(middleware.Recovery().DispFunc())
+	p.Engine.Use(middleware.APM().DispFunc())   //链路跟踪
 	p.Engine.Use(middleware.Trace().DispFunc()) //跟踪信息
 	p.Engine.Use(p.metric.Handle().DispFunc())
 	p.Engine.Use(middlewares.DispFunc()...)
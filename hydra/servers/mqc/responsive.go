@@ -9,6 +9,7 @@ import (
 	"github.com/micro-plat/hydra/conf/server/mqc"
 	"github.com/micro-plat/hydra/conf/server/queue"
 	varqueue "github.com/micro-plat/hydra/conf/vars/queue"
+	xctx "github.com/micro-plat/hydra/context/ctx"
 	"github.com/micro-plat/hydra/global"
 	"github.com/micro-plat/hydra/hydra/servers"
 	"github.com/micro-plat/hydra/registry/pub"
@@ -123,6 +124,7 @@ func (w *Responsive) Shutdown() {
 	w.log.Infof("关闭[%s]服务...", w.conf.GetServerConf().GetServerType())
 	w.Server.Shutdown()
 	w.pub.Clear()
+	xctx.CloseTracer(w.conf.GetServerConf().GetServerPath())
 	if err := services.Def.DoClosing(w.conf); err != nil {
 		w.log.Infof("关闭[%s]服务,出现错误", err)
 		return
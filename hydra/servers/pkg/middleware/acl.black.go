@@ -3,6 +3,9 @@ package middleware
 import (
 	"fmt"
 	"net/http"
+
+	"github.com/micro-plat/hydra/components"
+	"github.com/micro-plat/hydra/conf/server/acl/blacklist"
 )
 
 //BlackList 黑名单
@@ -18,11 +21,51 @@ func BlackList() Handler {
 			return
 		}
 		ctx.Response().AddSpecial("black")
-		if white.IsDeny(ctx.User().GetClientIP()) {
-			err := fmt.Errorf("黑名单限制[%s]不允许访问", ctx.User().GetClientIP())
+		store, err := getDenyStore(white)
+		if err != nil {
+			ctx.Response().Abort(http.StatusNotExtended, err)
+			return
+		}
+		ip := ctx.User().GetClientIP()
+		if white.IsDeny(ip) || white.IsBanned(ip, store) {
+			err := fmt.Errorf("黑名单限制[%s]不允许访问", ip)
 			ctx.Response().Abort(http.StatusForbidden, err)
 			return
 		}
 		ctx.Next()
+
+		//统计认证失败与被限流次数，超过阈值时加入动态黑名单
+		if store == nil {
+			return
+		}
+		kind := ""
+		code, _, _ := ctx.Response().GetFinalResponse()
+		if code == 0 {
+			code, _, _ = ctx.Response().GetRawResponse()
+		}
+		switch {
+		case ctx.Request().Path().IsLimited():
+			kind = blacklist.FailureLimit
+		case code == http.StatusUnauthorized || code == http.StatusForbidden:
+			kind = blacklist.FailureAuth
+		default:
+			return
+		}
+		banned, err := white.Record(ip, ctx.Request().Path().GetRequestPath(), kind, store)
+		if err != nil {
+			ctx.Log().Error(err)
+			return
+		}
+		if banned {
+			ctx.Log().Warnf("[%s]失败次数过多(%s)，已加入动态黑名单", ip, kind)
+		}
+	}
+}
+
+//getDenyStore 获取动态黑名单存储，未配置缓存时返回nil
+func getDenyStore(b *blacklist.BlackList) (blacklist.IDenyStore, error) {
+	if b.Cache == "" {
+		return nil, nil
 	}
+	return components.Def.Cache().GetCache(b.Cache)
 }
@@ -1,15 +1,55 @@
 package middleware
 
-//APM 跟踪数据
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+//APM 跟踪数据，为每个请求创建根跨度，服务处理完成后记录服务名、状态码、客户端IP与特殊标记，
+//rpc流式请求另外创建数据流子跨度
 func APM() Handler {
 	return func(ctx IMiddleContext) {
-		if !ctx.Tracer().Available() {
+		tracer := ctx.Tracer()
+		if !tracer.Available() {
 			ctx.Next()
 			return
 		}
 		ctx.Response().AddSpecial("apm")
-		ctx.Tracer().Start()
-		defer ctx.Tracer().End()
+		tracer.Start()
+		defer tracer.End()
+
+		//rpc流式请求创建数据流子跨度，记录数据流的处理时长与收发的消息数
+		if stream, ok := ctx.Request().GetRPCStream(); ok {
+			span := tracer.NewSpan("rpc.stream")
+			span.Start()
+			span.SetTag("component", "rpc")
+			defer func() {
+				if c, ok := stream.(interface{ GetCount() (int64, int64) }); ok {
+					recv, sent := c.GetCount()
+					span.SetTag("recv", strconv.FormatInt(recv, 10))
+					span.SetTag("sent", strconv.FormatInt(sent, 10))
+				}
+				span.SetError(stream.Context().Err())
+				span.End()
+			}()
+		}
 		ctx.Next()
+
+		status, content, _ := ctx.Response().GetRawResponse()
+		tracer.SetTag("server", ctx.APPConf().GetServerConf().GetServerType())
+		tracer.SetTag("service", ctx.Request().Path().GetService())
+		tracer.SetTag("method", ctx.Request().Path().GetMethod())
+		tracer.SetTag("status_code", strconv.Itoa(status))
+		tracer.SetTag("client_ip", ctx.User().GetClientIP())
+		tracer.SetTag("trace_id", ctx.User().GetTraceID())
+		tracer.SetTag("specials", ctx.Response().GetSpecials())
+		if err, ok := content.(error); ok {
+			tracer.SetError(err)
+			return
+		}
+		if status >= http.StatusInternalServerError {
+			tracer.SetError(fmt.Errorf("响应状态码:%d", status))
+		}
 	}
 }
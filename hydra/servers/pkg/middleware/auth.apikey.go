@@ -9,6 +9,7 @@ import (
 	"github.com/micro-plat/hydra/context"
 	"github.com/micro-plat/hydra/global"
 	"github.com/micro-plat/lib4go/net"
+	"github.com/micro-plat/lib4go/security/md5"
 	"github.com/micro-plat/lib4go/types"
 )
 
@@ -46,6 +47,7 @@ func APIKeyAuth() Handler {
 			ctx.Response().Abort(http.StatusForbidden, err)
 			return
 		}
+		ctx.Meta().SetValue(context.APIKeyID, md5.Encrypt(auth.Secret)[:8])
 		ctx.Next()
 	}
 }
@@ -6,6 +6,7 @@ import (
 
 	xjwt "github.com/micro-plat/hydra/conf/server/auth/jwt"
 	"github.com/micro-plat/hydra/context"
+	xctx "github.com/micro-plat/hydra/context/ctx"
 	"github.com/micro-plat/lib4go/errs"
 )
 
@@ -55,16 +56,36 @@ func JwtAuth() Handler {
 }
 
 // CheckJWT 检查jwk参数是否合法
-func checkJWT(ctx context.IContext, j *xjwt.JWTAuth) (data interface{}, err error) {
+func checkJWT(ctx IMiddleContext, j *xjwt.JWTAuth) (data interface{}, err error) {
 
-	//1. 从请求中获取jwt信息
+	//1. 获取吊销信息存储
+	store, err := xctx.GetJWTStore(j)
+	if err != nil {
+		return nil, errs.NewError(xjwt.JWTStatusConfDataError, err)
+	}
+
+	//2. 从请求中获取jwt信息并检查是否有效
 	token := getToken(ctx, j)
-	data, err = j.CheckJWT(token)
+	claims, err := j.Verify(token, store)
 	if err != nil {
-		return nil, err
+
+		//3. 访问令牌过期时使用刷新令牌换取新的令牌，cookie中的访问令牌过期后不再传入，按过期处理
+		if j.RefreshExpireAt <= 0 || token != "" && !xjwt.IsExpired(err) {
+			return nil, err
+		}
+		rtoken := getRefreshToken(ctx, j)
+		if rtoken == "" {
+			return nil, err
+		}
+		if claims, err = j.Refresh(rtoken, store); err != nil {
+			return nil, err
+		}
+		ctx.Meta().SetValue(context.JWTReissue, true)
 	}
 
 	//保存到Context中
+	data = xjwt.GetData(claims)
+	ctx.Meta().SetValue(context.JWTClaims, map[string]interface{}(claims))
 	ctx.User().Auth().Request(data)
 	return data, nil
 }
@@ -79,3 +100,13 @@ func getToken(ctx context.IContext, jwt *xjwt.JWTAuth) string {
 		return cookie
 	}
 }
+
+//getRefreshToken 从请求头或cookie中获取刷新令牌
+func getRefreshToken(ctx context.IContext, jwt *xjwt.JWTAuth) string {
+	switch strings.ToUpper(jwt.Source) {
+	case xjwt.SourceHeader, xjwt.SourceHeaderShort:
+		return ctx.Request().Headers().GetString(jwt.GetRefreshName())
+	default:
+		return ctx.Request().Cookies().GetString(jwt.GetRefreshName())
+	}
+}
@@ -4,7 +4,8 @@ import (
 	"fmt"
 
 	xjwt "github.com/micro-plat/hydra/conf/server/auth/jwt"
-	"github.com/micro-plat/lib4go/security/jwt"
+	"github.com/micro-plat/hydra/context"
+	xctx "github.com/micro-plat/hydra/context/ctx"
 )
 
 //JwtWriter 将jwt信息写入到请求中
@@ -24,27 +25,52 @@ func JwtWriter() Handler {
 	}
 }
 
-func setJwtResponse(ctx IMiddleContext, jwtAuth *xjwt.JWTAuth, data interface{}) {
+//setJwtResponse 写入或清除jwt，签发失败时终止请求并返回false
+func setJwtResponse(ctx IMiddleContext, jwtAuth *xjwt.JWTAuth, data interface{}) bool {
+	setHeader := func(k string, v string, ok bool) {
+		writeJwtHeader(ctx, k, v, ok)
+	}
 
 	//清除jwt认证信息
 	if ctx.ClearAuth() {
-		if k, v, ok := jwtAuth.GetJWTForRspns("", true); ok {
-			ctx.Response().Header(k, v)
+		setHeader(jwtAuth.GetJWTForRspns("", true))
+		if jwtAuth.RefreshExpireAt > 0 {
+			setHeader(jwtAuth.GetRefreshForRspns("", true))
 		}
-		return
+		return true
+	}
+
+	//启用刷新令牌时只在登录或刷新后签发，否则每次请求重新签发
+	if data == nil || jwtAuth.RefreshExpireAt > 0 && !ctx.Meta().GetBool(context.JWTReissue) {
+		return true
 	}
 
 	//写入响应
-	if data != nil {
-		jwtToken, err := jwt.Encrypt(jwtAuth.Secret, jwtAuth.Mode, data, jwtAuth.ExpireAt)
-		if err != nil {
-			ctx.Response().Abort(xjwt.JWTStatusConfDataError, fmt.Errorf("jwt配置出错：%v", err))
-			return
-		}
-		if k, v, ok := jwtAuth.GetJWTForRspns(jwtToken); ok {
-			ctx.Response().Header(k, v)
-		}
+	store, err := xctx.GetJWTStore(jwtAuth)
+	if err != nil {
+		ctx.Response().Abort(xjwt.JWTStatusConfDataError, fmt.Errorf("jwt吊销缓存配置出错：%v", err))
+		return false
 	}
-	return
+	access, refresh, err := jwtAuth.Issue(data, store)
+	if err != nil {
+		ctx.Response().Abort(xjwt.JWTStatusConfDataError, fmt.Errorf("jwt配置出错：%v", err))
+		return false
+	}
+	setHeader(jwtAuth.GetJWTForRspns(access))
+	if refresh != "" {
+		setHeader(jwtAuth.GetRefreshForRspns(refresh))
+	}
+	return true
+}
 
+//writeJwtHeader 写入jwt响应头，多个cookie时追加
+func writeJwtHeader(ctx IMiddleContext, k string, v string, ok bool) {
+	if !ok {
+		return
+	}
+	if k == "Set-Cookie" {
+		ctx.WHeaders().Add(k, v)
+		return
+	}
+	ctx.Response().Header(k, v)
 }
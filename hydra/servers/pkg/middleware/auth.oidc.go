@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/micro-plat/hydra/conf/server/auth/oidc"
+)
+
+//OIDC OpenID Connect登录，处理登录跳转与认证回调，认证通过后签发jwt
+func OIDC() Handler {
+	return func(ctx IMiddleContext) {
+
+		//1. 获取oidc配置
+		o, err := ctx.APPConf().GetOIDCConf()
+		if err != nil {
+			ctx.Response().Abort(http.StatusNotExtended, err)
+			return
+		}
+		if o.Disable {
+			ctx.Next()
+			return
+		}
+
+		//2. 只处理登录与回调地址
+		switch ctx.Request().Path().GetRequestPath() {
+		case o.GetLoginPath():
+			ctx.Response().AddSpecial("oidc")
+			oidcLogin(ctx, o)
+		case o.GetCallbackPath():
+			ctx.Response().AddSpecial("oidc")
+			oidcCallback(ctx, o)
+		default:
+			ctx.Next()
+		}
+	}
+}
+
+//oidcLogin 生成登录状态并跳转到认证服务器
+func oidcLogin(ctx IMiddleContext, o *oidc.OIDC) {
+	state := oidc.NewState(ctx.Request().GetString("redirect"))
+	url, err := o.AuthCodeURL(state)
+	if err != nil {
+		ctx.Response().Abort(http.StatusBadGateway, err)
+		return
+	}
+	ctx.WHeaders().Add("Set-Cookie", o.GetStateCookie(state))
+	ctx.Response().Header("Location", url)
+	ctx.Response().Abort(http.StatusFound)
+}
+
+//oidcCallback 校验登录状态，使用授权码换取ID Token，并将用户信息写入jwt
+func oidcCallback(ctx IMiddleContext, o *oidc.OIDC) {
+	ctx.WHeaders().Add("Set-Cookie", o.GetStateCookie(nil))
+	if e := ctx.Request().GetString("error"); e != "" {
+		ctx.Response().Abort(http.StatusUnauthorized, fmt.Errorf("oidc认证失败:%s %s", e, ctx.Request().GetString("error_description")))
+		return
+	}
+	state, err := oidc.DecodeState(ctx.Request().Cookies().GetString(oidc.StateCookie))
+	if err != nil {
+		ctx.Response().Abort(http.StatusUnauthorized, err)
+		return
+	}
+	if ctx.Request().GetString("state") != state.State {
+		ctx.Response().Abort(http.StatusUnauthorized, fmt.Errorf("oidc登录状态不一致"))
+		return
+	}
+	claims, err := o.Exchange(ctx.Request().GetString("code"), state)
+	if err != nil {
+		ctx.Response().Abort(http.StatusUnauthorized, err)
+		return
+	}
+
+	//签发jwt
+	jwtAuth, err := ctx.APPConf().GetJWTConf()
+	if err != nil {
+		ctx.Response().Abort(http.StatusNotExtended, err)
+		return
+	}
+	if jwtAuth.Disable {
+		ctx.Response().Abort(http.StatusNotExtended, fmt.Errorf("oidc登录需启用jwt认证"))
+		return
+	}
+	data := o.MapClaims(claims)
+	ctx.Log().Infof("oidc登录成功:%v", claims["sub"])
+	ctx.User().Auth().Response(data)
+	if !setJwtResponse(ctx, jwtAuth, data) {
+		return
+	}
+	ctx.Response().Header("Location", o.GetSuccessURL(state.Redirect))
+	ctx.Response().Abort(http.StatusFound)
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/micro-plat/lib4go/types"
+)
+
+//RBAC 基于角色与权限的访问控制，从用户认证信息中获取角色与权限
+func RBAC() Handler {
+	return func(ctx IMiddleContext) {
+
+		//1. 获取访问控制配置
+		rbac, err := ctx.APPConf().GetRBACConf()
+		if err != nil {
+			ctx.Response().Abort(http.StatusNotExtended, err)
+			return
+		}
+		path := ctx.Request().Path().GetRequestPath()
+		if rbac.Disable {
+			ctx.Next()
+			return
+		}
+		if ok, _ := rbac.Match(path); ok {
+			ctx.Next()
+			return
+		}
+
+		//2. 获取服务注册时指定的权限
+		var required []string
+		if r, err := ctx.Request().Path().GetRouter(); err == nil {
+			required = r.Permissions
+		}
+
+		//3. 检查用户的角色与权限
+		user := make(map[string]interface{})
+		authed := ctx.User().Auth().Request() != nil
+		ctx.User().Auth().Bind(&user)
+		method := ctx.Request().Path().GetMethod()
+		err = rbac.Authorize(path, method, user, required...)
+		if err == nil {
+			ctx.Next()
+			return
+		}
+
+		ctx.Response().AddSpecial("rbac")
+		name := types.GetString(user["uid"], ctx.User().GetUserName())
+		ctx.Log().Warnf("用户[%s]无权访问%s %s:%v", name, method, path, err)
+		if !authed {
+			ctx.Response().Abort(http.StatusUnauthorized, fmt.Errorf("用户未登录"))
+			return
+		}
+		ctx.Response().Abort(http.StatusForbidden, fmt.Errorf("无权访问"))
+	}
+}
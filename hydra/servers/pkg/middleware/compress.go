@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+)
+
+//Encoder 响应内容压缩器，level为0时使用默认压缩级别
+type Encoder func(w io.Writer, level int) (io.WriteCloser, error)
+
+var encoders = map[string]Encoder{
+	"br": func(w io.Writer, level int) (io.WriteCloser, error) {
+		if level == 0 {
+			level = brotli.DefaultCompression
+		}
+		return brotli.NewWriterLevel(w, level), nil
+	},
+	"gzip": func(w io.Writer, level int) (io.WriteCloser, error) {
+		if level == 0 {
+			level = gzip.DefaultCompression
+		}
+		return gzip.NewWriterLevel(w, level)
+	},
+	"deflate": func(w io.Writer, level int) (io.WriteCloser, error) {
+		if level == 0 {
+			level = flate.DefaultCompression
+		}
+		return flate.NewWriter(w, level)
+	},
+}
+var encoderLock sync.RWMutex
+
+//RegisterEncoder 注册响应压缩器，已存在时替换
+func RegisterEncoder(name string, e Encoder) {
+	encoderLock.Lock()
+	defer encoderLock.Unlock()
+	encoders[name] = e
+}
+
+func getEncoder(name string) (Encoder, bool) {
+	encoderLock.RLock()
+	defer encoderLock.RUnlock()
+	e, ok := encoders[name]
+	return e, ok
+}
+
+func hasEncoder(name string) bool {
+	_, ok := getEncoder(name)
+	return ok
+}
+
+//Compress 根据客户端Accept-Encoding压缩响应内容，在render与jwt回写之后执行
+func Compress() Handler {
+	return func(ctx IMiddleContext) {
+		ctx.Next()
+
+		//流式处理的响应已直接写入响应流
+		if ctx.Request().Path().IsStream() || ctx.Request().Path().GetMethod() == http.MethodHead {
+			return
+		}
+
+		//1. 获取压缩配置
+		cmp, err := ctx.APPConf().GetCompressConf()
+		if err != nil {
+			ctx.Log().Error("compress配置有误:", err)
+			return
+		}
+		if cmp.Disable {
+			return
+		}
+
+		//2. 检查响应内容是否需要压缩
+		status, content, ctp := ctx.Response().GetFinalResponse()
+		if status == http.StatusNoContent || status == http.StatusNotModified ||
+			len(content) < cmp.GetMinSize() || !cmp.IsAllowContentType(ctp) {
+			return
+		}
+		if w := ctx.Response().GetHTTPReponse(); w == nil || w.Header().Get("Content-Encoding") != "" {
+			return
+		}
+
+		//3. 根据客户端支持的压缩方式进行压缩
+		ctx.Response().Header("Vary", "Accept-Encoding")
+		name := cmp.Negotiate(ctx.Request().Headers().GetString("Accept-Encoding"), hasEncoder)
+		if name == "" {
+			return
+		}
+		encoder, _ := getEncoder(name)
+		err = ctx.Response().Encode(name, func(buff []byte) ([]byte, error) {
+			var b bytes.Buffer
+			w, err := encoder(&b, cmp.Level)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := w.Write(buff); err != nil {
+				return nil, err
+			}
+			if err := w.Close(); err != nil {
+				return nil, err
+			}
+			return b.Bytes(), nil
+		})
+		if err != nil {
+			ctx.Log().Debug(err)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/micro-plat/hydra/conf/server/compress"
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func TestEncoder_Brotli(t *testing.T) {
+	cmp := compress.New()
+	assert.Equal(t, "br", cmp.Negotiate("br", hasEncoder), "Accept-Encoding: br")
+	assert.Equal(t, "br", cmp.Negotiate("gzip, deflate, br", hasEncoder), "优先使用br")
+
+	encoder, ok := getEncoder("br")
+	assert.Equal(t, true, ok, "已注册br压缩")
+	content := strings.Repeat("hydra", 100)
+	var b bytes.Buffer
+	w, err := encoder(&b, cmp.Level)
+	assert.Equal(t, nil, err, "创建br压缩")
+	w.Write([]byte(content))
+	assert.Equal(t, nil, w.Close(), "压缩")
+	assert.Equal(t, true, b.Len() < len(content), "压缩后变小")
+
+	buff, err := ioutil.ReadAll(brotli.NewReader(&b))
+	assert.Equal(t, nil, err, "解压")
+	assert.Equal(t, content, string(buff), "解压后内容一致")
+}
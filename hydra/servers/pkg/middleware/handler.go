@@ -1,7 +1,10 @@
 package middleware
 
 import (
+	"net/http"
+
 	"github.com/micro-plat/hydra/components"
+	"github.com/micro-plat/hydra/context"
 	"github.com/micro-plat/hydra/global"
 	"github.com/micro-plat/hydra/services"
 )
@@ -17,6 +20,12 @@ func ExecuteHandler(service string) Handler {
 			fallback(ctx, service)
 			return
 		}
+
+		//检查请求参数
+		if !checkSchema(ctx) {
+			return
+		}
+
 		//处理RPC服务调用
 		if addr, ok := global.IsProto(service, global.ProtoRPC); ok {
 			response, err := components.Def.RPC().GetRegularRPC().Swap(addr, ctx)
@@ -32,3 +41,18 @@ func ExecuteHandler(service string) Handler {
 		ctx.Response().WriteAny(result)
 	}
 }
+
+//checkSchema 根据路由声明的参数验证请求参数，验证失败时返回400及所有失败的字段
+func checkSchema(ctx IMiddleContext) bool {
+	router, err := ctx.Request().Path().GetRouter()
+	if err != nil || len(router.Schema) == 0 {
+		return true
+	}
+	fields := router.Schema.Validate(ctx.Request().GetMap())
+	if len(fields) == 0 {
+		return true
+	}
+	ctx.Response().AddSpecial("schema")
+	ctx.Response().Write(http.StatusBadRequest, context.NewError(context.ErrInvalidInput).WithDetails(fields).WithCause(fields))
+	return false
+}
@@ -0,0 +1,177 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/micro-plat/hydra/components/caches"
+	"github.com/micro-plat/hydra/conf/server/idempotent"
+	"github.com/micro-plat/hydra/context"
+	"github.com/micro-plat/lib4go/security/md5"
+	"github.com/micro-plat/lib4go/types"
+)
+
+//idempotentRecord 幂等键对应的处理结果，Pending为true时表示请求正在处理
+type idempotentRecord struct {
+	Pending     bool        `json:"pending,omitempty"`
+	Fingerprint string      `json:"fingerprint,omitempty"`
+	Status      int         `json:"status,omitempty"`
+	ContentType string      `json:"contentType,omitempty"`
+	Content     string      `json:"content,omitempty"`
+	Headers     http.Header `json:"headers,omitempty"`
+}
+
+//skipHeaders 不需要保存的响应头，由响应流写入时重新生成
+var skipHeaders = map[string]bool{
+	"Content-Type":     true,
+	"Content-Length":   true,
+	"Content-Encoding": true,
+	"Date":             true,
+}
+
+//authHeaders 认证相关的响应头，包含调用方的凭证，不能保存后返回给其它请求
+var authHeaders = map[string]bool{
+	"Authorization":       true,
+	"Set-Cookie":          true,
+	"Www-Authenticate":    true,
+	"Proxy-Authenticate":  true,
+	"Proxy-Authorization": true,
+}
+
+const idempotentPollInterval = 50 * time.Millisecond
+
+//Idempotent 幂等请求处理，请求头包含幂等键时保存首次处理结果，重试请求直接返回已保存的结果，
+//相同幂等键的请求正在处理时等待处理完成或返回409
+func Idempotent() Handler {
+	return func(ctx IMiddleContext) {
+
+		//1. 获取幂等配置
+		cnf, err := ctx.APPConf().GetIdempotentConf()
+		if err != nil {
+			ctx.Response().Abort(http.StatusNotExtended, err)
+			return
+		}
+		path := ctx.Request().Path()
+		if path.IsStream() || !cnf.IsIdempotent(path.GetMethod(), path.GetRequestPath()) {
+			ctx.Next()
+			return
+		}
+		key := ctx.Request().Headers().GetString(cnf.GetHeader())
+		if key == "" {
+			ctx.Next()
+			return
+		}
+		ctx.Response().AddSpecial("idem")
+
+		//2. 获取幂等键的处理权
+		store, err := getCache(cnf.Cache)
+		if err != nil {
+			ctx.Response().Abort(http.StatusNotExtended, err)
+			return
+		}
+		ckey := fmt.Sprintf("hydra:idempotent:%s:%s:%s:%s", path.GetMethod(), path.GetRequestPath(), md5.Encrypt(getSubject(ctx)), key)
+		body, query, _ := ctx.Request().GetFullRaw()
+		fingerprint := md5.Encrypt(string(body) + query)
+		rcd, err := acquireIdempotent(store, ckey, fingerprint, cnf)
+		if err != nil {
+			ctx.Response().Abort(http.StatusNotExtended, err)
+			return
+		}
+
+		//3. 已有处理结果或正在处理
+		if rcd != nil {
+			switch {
+			case rcd.Fingerprint != fingerprint:
+				ctx.Response().Abort(http.StatusUnprocessableEntity, fmt.Errorf("幂等键%s已用于其它请求参数", key))
+			case rcd.Pending:
+				ctx.Response().Abort(http.StatusConflict, fmt.Errorf("幂等键%s对应的请求正在处理", key))
+			default:
+				for k, v := range rcd.Headers {
+					for _, i := range v {
+						ctx.WHeaders().Add(k, i)
+					}
+				}
+				ctx.Response().Header("Idempotent-Replayed", "true")
+				ctx.Response().ContentType(rcd.ContentType)
+				ctx.Response().Abort(rcd.Status, rcd.Content)
+			}
+			return
+		}
+
+		//4. 处理请求并保存处理结果，服务器错误时删除幂等键允许客户端重试
+		ctx.Next()
+		status, content, ctp := ctx.Response().GetFinalResponse()
+		if status == 0 || status >= http.StatusInternalServerError {
+			if err := store.Delete(ckey); err != nil {
+				ctx.Log().Error("删除幂等键失败:", err)
+			}
+			return
+		}
+		rcd = &idempotentRecord{Fingerprint: fingerprint, Status: status, ContentType: ctp, Content: content, Headers: getStoreHeaders(ctx)}
+		buff, _ := json.Marshal(rcd)
+		if err := store.Set(ckey, string(buff), cnf.GetExpire()); err != nil {
+			ctx.Log().Error("保存幂等请求结果失败:", err)
+		}
+	}
+}
+
+//acquireIdempotent 获取幂等键的处理权，获取成功时返回nil，否则返回已保存的处理结果或处理中的记录
+func acquireIdempotent(store caches.ICache, key string, fingerprint string, cnf *idempotent.Idempotent) (*idempotentRecord, error) {
+	pending, _ := json.Marshal(&idempotentRecord{Pending: true, Fingerprint: fingerprint})
+	deadline := time.Now().Add(cnf.GetWait())
+	for i := 0; ; i++ {
+		if err := store.Add(key, string(pending), cnf.GetLock()); err == nil {
+			return nil, nil
+		}
+		v, err := store.Get(key)
+		if err == nil && v != "" {
+			rcd := &idempotentRecord{}
+			if err := json.Unmarshal([]byte(v), rcd); err != nil {
+				return nil, fmt.Errorf("幂等请求结果格式有误:%w", err)
+			}
+			if !rcd.Pending || rcd.Fingerprint != fingerprint || time.Now().After(deadline) {
+				return rcd, nil
+			}
+		} else if i > 0 && time.Now().After(deadline) {
+			return nil, fmt.Errorf("获取幂等键%s失败:%v", key, err)
+		}
+		time.Sleep(idempotentPollInterval)
+	}
+}
+
+//getSubject 获取已认证的用户标识，依次使用jwt声明中的sub、basic认证用户名或客户端证书名称、静态密钥标识，未认证时返回空
+func getSubject(ctx IMiddleContext) string {
+	if claims, ok := ctx.Meta().GetValue(context.JWTClaims).(map[string]interface{}); ok {
+		if sub := types.GetString(claims["sub"]); sub != "" {
+			return "jwt:" + sub
+		}
+	}
+	if name := ctx.User().GetUserName(); name != "" {
+		return "user:" + name
+	}
+	if id := ctx.Meta().GetString(context.APIKeyID); id != "" {
+		return "apikey:" + id
+	}
+	return ""
+}
+
+//getStoreHeaders 获取需要保存的响应头，排除由响应流重新生成的响应头与认证相关的响应头
+func getStoreHeaders(ctx IMiddleContext, skip ...string) http.Header {
+	excludes := map[string]bool{}
+	for _, k := range skip {
+		excludes[http.CanonicalHeaderKey(k)] = true
+	}
+	if j, err := ctx.APPConf().GetJWTConf(); err == nil && !j.Disable {
+		excludes[http.CanonicalHeaderKey(j.Name)] = true
+		excludes[http.CanonicalHeaderKey(j.GetRefreshName())] = true
+	}
+	headers := http.Header{}
+	for k, v := range ctx.WHeaders() {
+		if !skipHeaders[k] && !authHeaders[k] && !excludes[k] && len(v) > 0 {
+			headers[k] = append([]string{}, v...)
+		}
+	}
+	return headers
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/micro-plat/hydra/conf"
+	"github.com/micro-plat/hydra/conf/app"
+	xjwt "github.com/micro-plat/hydra/conf/server/auth/jwt"
+	"github.com/micro-plat/hydra/context"
+	"github.com/micro-plat/lib4go/assert"
+)
+
+type testAPPConf struct {
+	app.IAPPConf
+	jwt *xjwt.JWTAuth
+}
+
+func (c *testAPPConf) GetJWTConf() (*xjwt.JWTAuth, error) {
+	return c.jwt, nil
+}
+
+type testUser struct {
+	context.IUser
+	name string
+}
+
+func (u *testUser) GetUserName() string {
+	return u.name
+}
+
+type testMiddleContext struct {
+	IMiddleContext
+	meta    conf.Meta
+	user    *testUser
+	headers http.Header
+	appConf *testAPPConf
+}
+
+func newTestMiddleContext() *testMiddleContext {
+	return &testMiddleContext{
+		meta:    conf.NewMeta(),
+		user:    &testUser{},
+		headers: http.Header{},
+		appConf: &testAPPConf{jwt: xjwt.NewJWT(xjwt.WithName("X-Token"), xjwt.WithHeader())},
+	}
+}
+
+func (c *testMiddleContext) Meta() conf.IMeta      { return c.meta }
+func (c *testMiddleContext) User() context.IUser   { return c.user }
+func (c *testMiddleContext) WHeaders() http.Header { return c.headers }
+func (c *testMiddleContext) APPConf() app.IAPPConf { return c.appConf }
+
+func TestGetSubject(t *testing.T) {
+	ctx := newTestMiddleContext()
+	assert.Equal(t, "", getSubject(ctx), "未认证")
+
+	ctx.meta.SetValue(context.APIKeyID, "k1")
+	assert.Equal(t, "apikey:k1", getSubject(ctx), "静态密钥")
+
+	ctx.user.name = "admin"
+	assert.Equal(t, "user:admin", getSubject(ctx), "basic认证")
+
+	ctx.meta.SetValue(context.JWTClaims, map[string]interface{}{"sub": "u1"})
+	assert.Equal(t, "jwt:u1", getSubject(ctx), "jwt认证")
+}
+
+func TestGetStoreHeaders(t *testing.T) {
+	ctx := newTestMiddleContext()
+	ctx.headers.Add("Set-Cookie", "a=1")
+	ctx.headers.Add("Set-Cookie", "b=2")
+	ctx.headers.Set("Authorization", "Bearer x")
+	ctx.headers.Set("X-Token", "Bearer x")
+	ctx.headers.Set("Content-Type", "application/json")
+	ctx.headers.Add("Link", "</a>")
+	ctx.headers.Add("Link", "</b>")
+	ctx.headers.Set("X-Cache", "MISS")
+
+	headers := getStoreHeaders(ctx, "X-Cache")
+	assert.Equal(t, http.Header{"Link": []string{"</a>", "</b>"}}, headers, "只保存非认证响应头的所有值")
+}
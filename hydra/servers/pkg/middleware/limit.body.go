@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/micro-plat/lib4go/errs"
+)
+
+//RequestLimit 请求内容或上传文件超过限制时返回413，并上报metric
+func RequestLimit(m *Metric) Handler {
+	return func(ctx IMiddleContext) {
+		err := ctx.Request().GetError()
+		if err == nil || errs.GetCode(err) != http.StatusRequestEntityTooLarge {
+			ctx.Next()
+			return
+		}
+		ctx.Response().AddSpecial("limit")
+		m.Reject(ctx, "size")
+		ctx.Response().Abort(http.StatusRequestEntityTooLarge, err)
+	}
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"fmt"
 	"net/http"
 	"time"
 )
@@ -20,15 +21,25 @@ func Logging() Handler {
 		//3. 将结果刷新到响应流
 		ctx.Response().Flush()
 
-		//4. 处理响应日志
+		//4. 处理响应日志，响应流与事件流记录已推送的字节数与连接时长
 		code, _, _ := ctx.Response().GetFinalResponse()
+		size := getSize(ctx)
 		if code >= http.StatusOK && code < http.StatusBadRequest {
-			ctx.Log().Info(ctx.APPConf().GetServerConf().GetServerType()+".response:", ctx.Request().Path().GetMethod(), path, code, ctx.Response().GetSpecials(), time.Since(start))
+			ctx.Log().Info(ctx.APPConf().GetServerConf().GetServerType()+".response:", ctx.Request().Path().GetMethod(), path, code, ctx.Response().GetSpecials(), size, time.Since(start))
 		} else {
-			ctx.Log().Error(ctx.APPConf().GetServerConf().GetServerType()+".response:", ctx.Request().Path().GetMethod(), path, code, ctx.Response().GetSpecials(), time.Since(start))
+			ctx.Log().Error(ctx.APPConf().GetServerConf().GetServerType()+".response:", ctx.Request().Path().GetMethod(), path, code, ctx.Response().GetSpecials(), size, time.Since(start))
 		}
 
 		//5.释放资源
 		ctx.Close()
 	}
 }
+
+//getSize 响应内容字节数，压缩时记录原始与压缩后字节数
+func getSize(ctx IMiddleContext) string {
+	raw, written := ctx.Response().GetSize()
+	if raw == written {
+		return fmt.Sprintf("%dB", raw)
+	}
+	return fmt.Sprintf("%dB/%dB", raw, written)
+}
@@ -3,6 +3,7 @@ package middleware
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/micro-plat/hydra/components/pkgs/metrics"
 	"github.com/micro-plat/lib4go/logger"
@@ -72,7 +73,6 @@ func (m *Metric) Handle() Handler {
 		//1. 初始化三类统计器---请求的QPS/正在处理的计数器/时间统计器
 		url := ctx.Request().Path().GetRequestPath()
 		conterName := metrics.MakeName(ctx.APPConf().GetServerConf().GetServerType()+".server.request", metrics.WORKING, "server", ctx.APPConf().GetServerConf().GetServerName(), "host", m.ip, "url", url) //堵塞计数
-		timerName := metrics.MakeName(ctx.APPConf().GetServerConf().GetServerType()+".server.request", metrics.TIMER, "server", ctx.APPConf().GetServerConf().GetServerName(), "host", m.ip, "url", url)    //堵塞计数
 		requestName := metrics.MakeName(ctx.APPConf().GetServerConf().GetServerType()+".server.request", metrics.QPS, "server", ctx.APPConf().GetServerConf().GetServerName(), "host", m.ip, "url", url)    //请求数
 
 		//2. 对QPS进行计数
@@ -82,10 +82,15 @@ func (m *Metric) Handle() Handler {
 		counter := metrics.GetOrRegisterCounter(conterName, m.currentRegistry)
 		counter.Inc(1)
 
-		//4. 对服务处理时长进行统计
-		metrics.GetOrRegisterTimer(timerName, m.currentRegistry).Time(func() {
-			ctx.Next()
-		})
+		//4. 对服务处理时长进行统计，响应流与事件流的连接时长单独统计，不计入请求处理时长
+		start := time.Now()
+		ctx.Next()
+		kind := ".server.request"
+		if ctx.Response().IsStream() {
+			kind = ".server.stream"
+		}
+		timerName := metrics.MakeName(ctx.APPConf().GetServerConf().GetServerType()+kind, metrics.TIMER, "server", ctx.APPConf().GetServerConf().GetServerName(), "host", m.ip, "url", url)
+		metrics.GetOrRegisterTimer(timerName, m.currentRegistry).UpdateSince(start)
 
 		//5. 服务处理完成后进行减数
 		counter.Dec(1)
@@ -96,10 +101,31 @@ func (m *Metric) Handle() Handler {
 			"url", url, "status", fmt.Sprintf("%d", statusCode)) //完成数
 		//7. 对服务处理结果的状态码进行上报
 		metrics.GetOrRegisterMeter(responseName, m.currentRegistry).Mark(1)
+
+		//8. 对响应内容的原始与写入(压缩后)字节数进行上报
+		raw, written := ctx.Response().GetSize()
+		rawName := metrics.MakeName(ctx.APPConf().GetServerConf().GetServerType()+".server.response.bytes", metrics.METER, "server", ctx.APPConf().GetServerConf().GetServerName(), "host", m.ip,
+			"url", url, "kind", "raw")
+		writtenName := metrics.MakeName(ctx.APPConf().GetServerConf().GetServerType()+".server.response.bytes", metrics.METER, "server", ctx.APPConf().GetServerConf().GetServerName(), "host", m.ip,
+			"url", url, "kind", "written")
+		metrics.GetOrRegisterMeter(rawName, m.currentRegistry).Mark(int64(raw))
+		metrics.GetOrRegisterMeter(writtenName, m.currentRegistry).Mark(int64(written))
 	}
 
 }
 
+//Reject 上报被拒绝的请求数，reason为拒绝原因
+func (m *Metric) Reject(ctx IMiddleContext, reason string) {
+	m.onceDo(ctx)
+	if !m.needCollect {
+		return
+	}
+	url := ctx.Request().Path().GetRequestPath()
+	rejectName := metrics.MakeName(ctx.APPConf().GetServerConf().GetServerType()+".server.request.rejected", metrics.METER, "server", ctx.APPConf().GetServerConf().GetServerName(), "host", m.ip,
+		"url", url, "reason", reason)
+	metrics.GetOrRegisterMeter(rejectName, m.currentRegistry).Mark(1)
+}
+
 //Stop stop metric
 func (m *Metric) Stop() {
 	if m.reporter != nil {
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"bytes"
+	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -10,6 +11,7 @@ import (
 	"net/http"
 	"net/url"
 
+	"github.com/micro-plat/hydra/context"
 	"github.com/micro-plat/hydra/hydra/servers/pkg/dispatcher"
 	"github.com/micro-plat/lib4go/types"
 )
@@ -136,6 +138,25 @@ func (g *dispCtx) GetFile(fileKey string) (string, io.ReadCloser, int64, error)
 func (g *dispCtx) GetHTTPReqResp() (*http.Request, http.ResponseWriter) {
 	return nil, nil
 }
+//GetPeerCertificates 获取客户端tls证书(rpc请求有效)
+func (g *dispCtx) GetPeerCertificates() []*x509.Certificate {
+	if r, ok := g.Context.Request.(interface {
+		GetPeerCertificates() []*x509.Certificate
+	}); ok {
+		return r.GetPeerCertificates()
+	}
+	return nil
+}
+
+//GetRPCStream 获取rpc流式请求的数据流(rpc流式请求有效)
+func (g *dispCtx) GetRPCStream() context.IRPCStream {
+	if r, ok := g.Context.Request.(interface {
+		GetRPCStream() context.IRPCStream
+	}); ok {
+		return r.GetRPCStream()
+	}
+	return nil
+}
 func (g *dispCtx) ClearAuth(c ...bool) bool {
 	if len(c) == 0 {
 		return g.needClearAuth
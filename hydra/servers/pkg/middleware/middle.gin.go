@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"crypto/x509"
 	"io"
 	"net/http"
 	"net/url"
@@ -8,6 +9,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
+	"github.com/micro-plat/hydra/context"
 	"github.com/micro-plat/lib4go/types"
 )
 
@@ -123,6 +125,18 @@ func (g *ginCtx) GetFile(fileKey string) (string, io.ReadCloser, int64, error) {
 func (g *ginCtx) GetHTTPReqResp() (*http.Request, http.ResponseWriter) {
 	return g.Request, g.Writer
 }
+//GetPeerCertificates 获取客户端tls证书
+func (g *ginCtx) GetPeerCertificates() []*x509.Certificate {
+	if g.Request.TLS == nil {
+		return nil
+	}
+	return g.Request.TLS.PeerCertificates
+}
+
+//GetRPCStream 获取rpc流式请求的数据流(http请求无效)
+func (g *ginCtx) GetRPCStream() context.IRPCStream {
+	return nil
+}
 func (g *ginCtx) ClearAuth(c ...bool) bool {
 	if len(c) == 0 {
 		return g.needClearAuth
@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"net/http"
+
 	"github.com/gin-gonic/gin"
 	"github.com/micro-plat/hydra/context"
 	"github.com/micro-plat/hydra/context/ctx"
@@ -10,9 +12,11 @@ import (
 
 type imiddle interface {
 	Next()
+	Abort()
 	Find(path string) bool
 	Service(string)
 	ClearAuth(c ...bool) bool
+	WHeaders() http.Header
 }
 
 //IMiddleContext 中间件转换器，在context.IContext中扩展next函数
@@ -11,6 +11,11 @@ func Render() Handler {
 		//render是最后根据配置修改相应结果   所以需要先执行了业务逻辑 然后在执行下面逻辑
 		ctx.Next()
 
+		//流式处理的响应已直接写入响应流
+		if ctx.Request().Path().IsStream() {
+			return
+		}
+
 		//加载渲染配置
 		render, err := ctx.APPConf().GetRenderConf()
 		if err != nil {
@@ -0,0 +1,184 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/micro-plat/hydra/components"
+	"github.com/micro-plat/hydra/components/caches"
+	"github.com/micro-plat/hydra/conf/server/router"
+	"github.com/micro-plat/lib4go/security/md5"
+	"github.com/micro-plat/lib4go/types"
+)
+
+const (
+	rcachePrefix  = "hydra:rcache:"
+	rcacheVersion = "hydra:rcache:v:"
+
+	//rcacheVersionExpire 路径版本号的保存时长(秒)，需大于缓存内容的保存时长
+	rcacheVersionExpire = 86400 * 30
+	rcacheLockExpire    = 30
+)
+
+//rcacheEntry 缓存的响应内容
+type rcacheEntry struct {
+	Version     string      `json:"version,omitempty"`
+	Time        int64       `json:"time"`
+	Status      int         `json:"status"`
+	ContentType string      `json:"contentType,omitempty"`
+	Content     string      `json:"content,omitempty"`
+	Headers     http.Header `json:"headers,omitempty"`
+}
+
+//ResponseCache 响应缓存，按路由的缓存配置在执行服务前返回已缓存的响应内容，
+//在render,jwt回写与响应压缩之前执行，命中缓存的响应仍由这些组件处理
+func ResponseCache() Handler {
+	return func(ctx IMiddleContext) {
+
+		//1. 检查是否需要缓存
+		path := ctx.Request().Path()
+		method := path.GetMethod()
+		if method != http.MethodGet && method != http.MethodHead {
+			ctx.Next()
+			return
+		}
+		router, err := path.GetRouter()
+		if err != nil || router.Cache == nil || router.Cache.TTL <= 0 {
+			ctx.Next()
+			return
+		}
+		policy := router.Cache
+		subject := getSubject(ctx)
+		if policy.VaryUser && subject == "" {
+			ctx.Next()
+			return
+		}
+		store, err := getCache(policy.Name)
+		if err != nil {
+			ctx.Log().Error("获取响应缓存失败:", err)
+			ctx.Next()
+			return
+		}
+		ctx.Response().AddSpecial("rcache")
+
+		//2. 检查缓存内容，过期的内容只由一个请求刷新
+		reqPath := path.GetRequestPath()
+		key := rcachePrefix + reqPath + ":" + getVaryKey(ctx, policy, subject)
+		version := getPathVersion(store, reqPath)
+		if entry := getCacheEntry(store, key); entry != nil && entry.Version == version {
+			age := time.Since(time.Unix(entry.Time, 0))
+			switch {
+			case age < policy.GetTTL():
+				writeCacheEntry(ctx, entry, "HIT", age)
+				return
+			case store.Add(key+":lock", "1", rcacheLockExpire) != nil:
+				writeCacheEntry(ctx, entry, "STALE", age)
+				return
+			}
+			defer store.Delete(key + ":lock")
+		}
+
+		//3. 执行服务并缓存响应内容
+		ctx.Response().Header("X-Cache", "MISS")
+		ctx.Next()
+		status, content, ctp := ctx.Response().GetFinalResponse()
+		if status != http.StatusOK || path.IsStream() {
+			return
+		}
+		entry := &rcacheEntry{Version: version, Time: time.Now().Unix(), Status: status, ContentType: ctp, Content: content, Headers: getStoreHeaders(ctx, "X-Cache")}
+		buff, _ := json.Marshal(entry)
+		if err := store.Set(key, string(buff), policy.GetExpire()); err != nil {
+			ctx.Log().Error("保存响应缓存失败:", err)
+		}
+	}
+}
+
+//PurgeResponseCache 清除指定路径前缀下的所有响应缓存，如/product清除/product/list,/product/1等，
+//name为路由缓存配置中的缓存名称，未指定时使用默认缓存
+func PurgeResponseCache(prefix string, name ...string) error {
+	store, err := getCache(types.GetStringByIndex(name, 0))
+	if err != nil {
+		return err
+	}
+	prefix = "/" + strings.Trim(prefix, "/")
+	return store.Set(rcacheVersion+prefix, strconv.FormatInt(time.Now().UnixNano(), 10), rcacheVersionExpire)
+}
+
+//writeCacheEntry 输出缓存的响应内容，不再执行服务
+func writeCacheEntry(ctx IMiddleContext, entry *rcacheEntry, state string, age time.Duration) {
+	for k, v := range entry.Headers {
+		for _, i := range v {
+			ctx.WHeaders().Add(k, i)
+		}
+	}
+	ctx.Response().Header("X-Cache", state)
+	ctx.Response().Header("Age", strconv.Itoa(int(age.Seconds())))
+	ctx.Response().ContentType(entry.ContentType)
+	ctx.Response().Write(entry.Status, entry.Content)
+	ctx.Abort()
+}
+
+//getVaryKey 根据查询参数、请求头与已认证的用户标识计算缓存键
+func getVaryKey(ctx IMiddleContext, policy *router.Cache, subject string) string {
+	var sb strings.Builder
+	_, query, _ := ctx.Request().GetFullRaw()
+	values, _ := url.ParseQuery(query)
+	if len(policy.VaryQuery) > 0 {
+		vary := url.Values{}
+		for _, k := range policy.VaryQuery {
+			if v, ok := values[k]; ok {
+				vary[k] = v
+			}
+		}
+		values = vary
+	}
+	sb.WriteString(values.Encode())
+	for _, h := range policy.VaryHeaders {
+		sb.WriteString(fmt.Sprintf("|%s=%s", h, ctx.Request().Headers().GetString(h)))
+	}
+	if policy.VaryUser {
+		sb.WriteString("|user=" + subject)
+	}
+	return md5.Encrypt(sb.String())
+}
+
+//getPathVersion 获取请求路径及其所有上级路径的版本号，清除缓存时更新版本号使已缓存内容失效
+func getPathVersion(store caches.ICache, path string) string {
+	keys := []string{rcacheVersion + "/"}
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	for i := range parts {
+		if parts[i] != "" {
+			keys = append(keys, rcacheVersion+"/"+strings.Join(parts[:i+1], "/"))
+		}
+	}
+	versions, err := store.Gets(keys...)
+	if err != nil {
+		return ""
+	}
+	return strings.Join(versions, ",")
+}
+
+func getCacheEntry(store caches.ICache, key string) *rcacheEntry {
+	v, err := store.Get(key)
+	if err != nil || v == "" {
+		return nil
+	}
+	entry := &rcacheEntry{}
+	if err := json.Unmarshal([]byte(v), entry); err != nil {
+		return nil
+	}
+	return entry
+}
+
+//getCache 获取缓存，未指定名称时使用默认缓存
+func getCache(name string) (caches.ICache, error) {
+	if name == "" {
+		return components.Def.Cache().GetCache()
+	}
+	return components.Def.Cache().GetCache(name)
+}
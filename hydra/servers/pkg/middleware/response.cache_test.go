@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"testing"
+
+	"github.com/micro-plat/hydra/conf/server/router"
+	"github.com/micro-plat/hydra/context"
+	"github.com/micro-plat/lib4go/assert"
+)
+
+type testRequest struct {
+	context.IRequest
+	query string
+}
+
+func (r *testRequest) GetFullRaw() ([]byte, string, error) {
+	return nil, r.query, nil
+}
+
+type testCacheContext struct {
+	*testMiddleContext
+	request *testRequest
+}
+
+func (c *testCacheContext) Request() context.IRequest { return c.request }
+
+func TestGetVaryKey(t *testing.T) {
+	ctx := &testCacheContext{testMiddleContext: newTestMiddleContext(), request: &testRequest{query: "page=1&t=2"}}
+	policy := &router.Cache{TTL: 60, VaryQuery: []string{"page"}}
+	k1 := getVaryKey(ctx, policy, "")
+	ctx.request.query = "page=1&t=3"
+	assert.Equal(t, k1, getVaryKey(ctx, policy, ""), "未参与计算的查询参数不影响缓存键")
+
+	policy.VaryUser = true
+	assert.NotEqual(t, getVaryKey(ctx, policy, "jwt:u1"), getVaryKey(ctx, policy, "jwt:u2"), "不同用户使用不同的缓存键")
+}
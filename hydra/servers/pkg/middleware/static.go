@@ -2,8 +2,12 @@ package middleware
 
 import (
 	"fmt"
+	"mime"
 	"net/http"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 )
 
 //Static 静态文件处理插件
@@ -45,13 +49,59 @@ func Static() Handler {
 			return
 		}
 
-		//文件已存在，则返回文件
-		if gzfile := static.GetGzFile(fpath); gzfile != "" {
+		//设置缓存策略，304与range请求由文件输出时根据ETag、Last-Modified处理
+		if cc := static.GetCacheControl(fpath); cc != "" {
+			ctx.Response().Header("Cache-Control", cc)
+		}
+		if ctp := mime.TypeByExtension(filepath.Ext(fpath)); ctp != "" {
+			ctx.Response().Header("Content-Type", ctp)
+		}
+		etag := static.GetETag(fpath, finfo)
+
+		//优先返回客户端支持的br、gzip压缩文件，压缩文件需预先生成(如app.js.br、app.js.gz)
+		brfile, gzfile := static.GetBrFile(fpath), static.GetGzFile(fpath)
+		if brfile != "" || gzfile != "" {
+			ctx.Response().Header("Vary", "Accept-Encoding")
+		}
+		accept := ctx.Request().Headers().GetString("Accept-Encoding")
+		switch {
+		case brfile != "" && acceptEncoding(accept, "br"):
+			ctx.Response().Header("Content-Encoding", "br")
+			ctx.Response().Header("ETag", encodingETag(etag, "br"))
+			ctx.Response().File(brfile)
+		case gzfile != "" && acceptEncoding(accept, "gzip"):
 			ctx.Response().Header("Content-Encoding", "gzip")
+			ctx.Response().Header("ETag", encodingETag(etag, "gzip"))
 			ctx.Response().File(gzfile)
-			return
+		default:
+			ctx.Response().Header("ETag", etag)
+			ctx.Response().File(fpath)
+		}
+	}
+}
+
+//acceptEncoding 客户端是否支持指定的压缩方式
+func acceptEncoding(accept string, encoding string) bool {
+	for _, v := range strings.Split(accept, ",") {
+		parts := strings.Split(strings.TrimSpace(v), ";")
+		if !strings.EqualFold(strings.TrimSpace(parts[0]), encoding) {
+			continue
 		}
-		ctx.Response().File(fpath)
-		return
+		for _, p := range parts[1:] {
+			p = strings.TrimSpace(p)
+			if !strings.HasPrefix(p, "q=") {
+				continue
+			}
+			if q, err := strconv.ParseFloat(strings.TrimPrefix(p, "q="), 64); err == nil && q == 0 {
+				return false
+			}
+		}
+		return true
 	}
+	return false
+}
+
+//encodingETag 压缩文件与原文件内容不同，使用不同的ETag
+func encodingETag(etag string, encoding string) string {
+	return strings.TrimSuffix(etag, `"`) + "-" + encoding + `"`
 }
@@ -12,6 +12,15 @@ func Trace() Handler {
 
 		ctx.Response().AddSpecial("trace")
 
+		//流式处理的请求与响应内容不在内存中，不打印
+		if ctx.Request().Path().IsStream() {
+			ctx.Log().Debug("> trace.request:", ctx.Request().GetMap(), "[stream]")
+			ctx.Next()
+			s, _, _ := ctx.Response().GetFinalResponse()
+			ctx.Log().Debug("> trace.response:", s, "[stream]")
+			return
+		}
+
 		//1.打印请求参数
 		input := ctx.Request().GetMap()
 		ctx.Log().Debug("> trace.request:", input)
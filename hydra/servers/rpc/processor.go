@@ -11,6 +11,8 @@ import (
 	"github.com/micro-plat/hydra/hydra/servers/pkg/dispatcher"
 	"github.com/micro-plat/hydra/hydra/servers/pkg/middleware"
 	"github.com/micro-plat/lib4go/jsons"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
 )
 
 //Processor cron管理程序，用于管理多个任务的执行，暂停，恢复，动态添加，移除
@@ -32,10 +34,13 @@ func NewProcessor(routers ...*router.Router) (p *Processor) {
 	p.Engine.Use(middleware.Logging().DispFunc())
 	p.Engine.Use(middleware.Recovery().DispFunc())
 
+	p.Engine.Use(middleware.APM().DispFunc())   //链路跟踪
 	p.Engine.Use(middleware.Trace().DispFunc()) //跟踪信息
 	p.Engine.Use(middleware.Delay().DispFunc())
+	p.Engine.Use(middleware.Idempotent().DispFunc()) //幂等请求处理
 	p.Engine.Use(p.metric.Handle().DispFunc())
 	p.Engine.Use(middlewares.DispFunc()...)
+	p.Engine.Use(middleware.ResponseCache().DispFunc()) //响应缓存(命中时不再执行服务)
 	p.addRouter(routers...)
 	return p
 }
@@ -59,6 +64,40 @@ func (s *Processor) Request(context context.Context, request *pb.RequestContext)
 		p.Result = fmt.Sprintf("输入参数有误:%v", err)
 		return p, nil
 	}
+	return s.handle(context, req), nil
+}
+
+//Stream 处理流式请求，首个消息作为请求参数执行服务，服务通过数据流接收与发送其它消息，
+//服务执行完成后发送最终的响应内容
+func (s *Processor) Stream(stream pb.RPC_StreamServer) error {
+
+	//读取首个消息
+	request, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	//转换输入参数
+	req, err := NewRequest(request)
+	if err != nil {
+		p := &pb.ResponseContext{}
+		p.Status = int32(http.StatusNotAcceptable)
+		p.Result = fmt.Sprintf("输入参数有误:%v", err)
+		return stream.Send(p)
+	}
+	req.stream = &rpcStream{stream: stream, contentType: req.header["Content-Type"], binary: pb.AcceptBinary(req.header)}
+	return stream.Send(s.handle(stream.Context(), req))
+}
+
+//handle 执行本地服务并构建响应内容
+func (s *Processor) handle(context context.Context, req *Request) (p *pb.ResponseContext) {
+
+	//获取客户端tls证书
+	if pr, ok := peer.FromContext(context); ok {
+		if info, ok := pr.AuthInfo.(credentials.TLSInfo); ok {
+			req.certs = info.State.PeerCertificates
+		}
+	}
 
 	//发起本地处理
 	w, err := s.Engine.HandleRequest(req)
@@ -66,22 +105,28 @@ func (s *Processor) Request(context context.Context, request *pb.RequestContext)
 		p = &pb.ResponseContext{}
 		p.Status = int32(http.StatusInternalServerError)
 		p.Result = fmt.Sprintf("处理请求有误%s", err.Error())
-		return p, nil
+		return p
 	}
 
-	//处理响应内容
+	//处理响应内容，仅支持字符串的客户端不返回二进制内容
+	if pb.IsBinary(w.Data()) && !pb.AcceptBinary(req.header) {
+		p = &pb.ResponseContext{}
+		p.Status = int32(http.StatusNotAcceptable)
+		p.Result = "客户端不支持接收二进制内容"
+		return p
+	}
 	p = &pb.ResponseContext{}
 	p.Status = int32(w.Status())
-	p.Result = string(w.Data())
+	p.SetPayload(w.Data())
 	h, err := jsons.Marshal(w.Header())
 	if err != nil {
 		p = &pb.ResponseContext{}
 		p.Status = int32(http.StatusInternalServerError)
 		p.Result = fmt.Sprintf("输换响应头失败 %s", err.Error())
-		return p, nil
+		return p
 	}
 	p.Header = string(h)
-	return p, nil
+	return p
 }
 
 //Close 关闭处理程序
@@ -1,10 +1,12 @@
 package rpc
 
 import (
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 
 	"github.com/micro-plat/hydra/components/rpcs/rpc/pb"
+	"github.com/micro-plat/hydra/context"
 )
 
 //Request 处理任务请求
@@ -12,6 +14,8 @@ type Request struct {
 	request *pb.RequestContext
 	form    map[string]interface{}
 	header  map[string]string
+	certs   []*x509.Certificate
+	stream  *rpcStream
 }
 
 //NewRequest 构建任务请求
@@ -32,8 +36,11 @@ func NewRequest(request *pb.RequestContext) (r *Request, err error) {
 		r.header["Content-Type"] = "application/json"
 	}
 
-	//缓存数据用于body直接获取
+	//缓存数据用于body直接获取，二进制内容通过body传输
 	r.form["__body__"] = request.Input
+	if len(request.GetBody()) > 0 {
+		r.form["__body__"] = request.GetBody()
+	}
 	return r, nil
 }
 
@@ -70,3 +77,16 @@ func (m *Request) GetHeader() map[string]string {
 func (m *Request) getHeader(key string) string {
 	return m.header[key]
 }
+
+//GetPeerCertificates 获取客户端tls证书
+func (m *Request) GetPeerCertificates() []*x509.Certificate {
+	return m.certs
+}
+
+//GetRPCStream 获取流式请求的数据流，非流式请求返回nil
+func (m *Request) GetRPCStream() context.IRPCStream {
+	if m.stream == nil {
+		return nil
+	}
+	return m.stream
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/micro-plat/hydra/conf"
 	"github.com/micro-plat/hydra/conf/app"
 	"github.com/micro-plat/hydra/conf/server/rpc"
+	tlsconf "github.com/micro-plat/hydra/conf/server/tls"
+	xctx "github.com/micro-plat/hydra/context/ctx"
 	"github.com/micro-plat/hydra/global"
 	"github.com/micro-plat/hydra/hydra/servers"
 	"github.com/micro-plat/hydra/registry/pub"
@@ -79,7 +81,7 @@ func (w *Responsive) Notify(c app.IAPPConf) (change bool, err error) {
 	if !w.comparer.IsChanged() {
 		return false, nil
 	}
-	if w.comparer.IsValueChanged() || w.comparer.IsSubConfChanged() {
+	if w.comparer.IsValueChanged() || w.comparer.IsSubConfChanged() || w.isTLSChanged(c) {
 		w.log.Info("关键配置发生变化，准备重启服务器")
 		server, err := w.getServer(c)
 		if err != nil {
@@ -110,6 +112,7 @@ func (w *Responsive) Shutdown() {
 	w.log.Infof("关闭[%s]服务...", w.conf.GetServerConf().GetServerType())
 	w.Server.Shutdown()
 	w.pub.Clear()
+	xctx.CloseTracer(w.conf.GetServerConf().GetServerPath())
 	if err := services.Def.DoClosing(w.conf); err != nil {
 		w.log.Infof("关闭[%s]服务,出现错误", err)
 		return
@@ -170,7 +173,26 @@ func (w *Responsive) getServer(cnf app.IAPPConf) (*Server, error) {
 	if err != nil {
 		return nil, err
 	}
-	return NewServer(rpcConf.Address, router.Routers, rpcConf.GetMaxRecvMsgSize(), rpcConf.GetMaxSendMsgSize())
+
+	//启用tls时证书由注册中心配置动态加载
+	tlsConf, err := cnf.GetTLSConf()
+	if err != nil {
+		return nil, err
+	}
+	if !tlsConf.IsEnabled() {
+		return NewServer(rpcConf.Address, router.Routers, rpcConf.GetMaxRecvMsgSize(), rpcConf.GetMaxSendMsgSize())
+	}
+	cfg, err := tlsconf.NewServerConfig(tlsConf, cnf.GetVarConf(), app.TLSGetter(RPC), "h2")
+	if err != nil {
+		return nil, fmt.Errorf("rpc tls配置有误 %w", err)
+	}
+	return NewServer(rpcConf.Address, router.Routers, rpcConf.GetMaxRecvMsgSize(), rpcConf.GetMaxSendMsgSize(), cfg)
+}
+
+//isTLSChanged tls启用状态发生变化，须重启服务器;证书变更时由tls配置动态加载，无需重启
+func (w *Responsive) isTLSChanged(c app.IAPPConf) bool {
+	tlsConf, err := c.GetTLSConf()
+	return err == nil && tlsConf.IsEnabled() != w.Server.IsTLS()
 }
 
 func init() {
@@ -1,6 +1,7 @@
 package rpc
 
 import (
+	"crypto/tls"
 	"fmt"
 	xnet "net"
 	"strings"
@@ -14,6 +15,7 @@ import (
 	"github.com/micro-plat/hydra/conf/server/router"
 	"github.com/micro-plat/lib4go/net"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 //Server cron服务器
@@ -22,18 +24,26 @@ type Server struct {
 	engine  *grpc.Server
 	running bool
 	addr    string
+	tls     bool
 }
 
 //NewServer 创建mqc服务器
 //未使用压缩，由于传输数据默认限制为4M(已修改为20M)压缩后会影响系统并发能力
 // grpc.RPCDecompressor(grpc.NewGZIPDecompressor())
-func NewServer(addr string, routers []*router.Router, maxRecvSize, maxSendSize int) (t *Server, err error) {
+//tlsConf不为空时启用tls
+func NewServer(addr string, routers []*router.Router, maxRecvSize, maxSendSize int, tlsConf ...*tls.Config) (t *Server, err error) {
+	opts := []grpc.ServerOption{
+		grpc.MaxRecvMsgSize(maxRecvSize),
+		grpc.MaxSendMsgSize(maxSendSize),
+	}
+	useTLS := len(tlsConf) > 0 && tlsConf[0] != nil
+	if useTLS {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConf[0])))
+	}
 	t = &Server{
 		Processor: NewProcessor(routers...),
-		engine: grpc.NewServer(
-			grpc.MaxRecvMsgSize(maxRecvSize),
-			grpc.MaxSendMsgSize(maxSendSize),
-		),
+		engine:    grpc.NewServer(opts...),
+		tls:       useTLS,
 	}
 
 	if t.addr, err = GetAddress(addr); err != nil {
@@ -124,6 +134,11 @@ func (s *Server) Shutdown() {
 	}
 }
 
+//IsTLS 是否启用tls
+func (s *Server) IsTLS() bool {
+	return s.tls
+}
+
 //GetAddress 获取当前服务地址
 func (s *Server) GetAddress() string {
 	return fmt.Sprintf("tcp://%s", s.addr)
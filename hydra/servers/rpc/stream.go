@@ -0,0 +1,72 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/micro-plat/hydra/components/rpcs/rpc/pb"
+	"github.com/micro-plat/hydra/pkgs/codec"
+)
+
+//rpcStream rpc流式请求的数据流
+type rpcStream struct {
+	stream      pb.RPC_StreamServer
+	contentType string
+	binary      bool
+	recv        int64
+	sent        int64
+}
+
+//Recv 接收客户端发送的下一条消息，客户端发送完成时返回io.EOF
+func (s *rpcStream) Recv() (string, error) {
+	m, err := s.stream.Recv()
+	if err != nil {
+		return "", err
+	}
+	atomic.AddInt64(&s.recv, 1)
+	return string(m.GetPayload()), nil
+}
+
+//Send 向客户端发送消息，字符串与[]byte直接发送，其它类型使用请求Content-Type对应的编解码器编码，
+//未注册编解码器时转换为json发送，客户端未声明支持二进制内容时不能发送非utf-8内容
+func (s *rpcStream) Send(content interface{}) error {
+	var result []byte
+	switch v := content.(type) {
+	case string:
+		result = []byte(v)
+	case []byte:
+		result = v
+	default:
+		c, ok := codec.Get(s.contentType)
+		if !ok {
+			c, _ = codec.Get("application/json")
+		}
+		buff, err := c.Marshal(v)
+		if err != nil {
+			return err
+		}
+		result = buff
+	}
+	if pb.IsBinary(result) && !s.binary {
+		return fmt.Errorf("客户端不支持接收二进制内容")
+	}
+	p := &pb.ResponseContext{Status: http.StatusOK, Stream: true}
+	p.SetPayload(result)
+	if err := s.stream.Send(p); err != nil {
+		return err
+	}
+	atomic.AddInt64(&s.sent, 1)
+	return nil
+}
+
+//GetCount 获取已接收与已发送的消息数
+func (s *rpcStream) GetCount() (recv int64, sent int64) {
+	return atomic.LoadInt64(&s.recv), atomic.LoadInt64(&s.sent)
+}
+
+//Context 数据流的上下文，客户端断开或取消请求时结束
+func (s *rpcStream) Context() context.Context {
+	return s.stream.Context()
+}
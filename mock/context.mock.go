@@ -2,6 +2,7 @@ package mock
 
 import (
 	"bytes"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -184,6 +185,15 @@ func (m *mock) GetFile(fileKey string) (string, io.ReadCloser, int64, error) {
 func (m *mock) GetHTTPReqResp() (*http.Request, http.ResponseWriter) {
 	return nil, nil
 }
+//GetPeerCertificates 获取客户端tls证书
+func (m *mock) GetPeerCertificates() []*x509.Certificate {
+	return nil
+}
+
+//GetRPCStream 获取rpc流式请求的数据流
+func (m *mock) GetRPCStream() context.IRPCStream {
+	return nil
+}
 func (m *mock) ClearAuth(c ...bool) bool {
 	return false
 }
@@ -0,0 +1,49 @@
+package codec
+
+import (
+	"errors"
+	"strings"
+	"sync"
+)
+
+//ErrUnsupportedType 编解码器不支持的数据类型
+var ErrUnsupportedType = errors.New("编解码器不支持的数据类型")
+
+//ICodec 内容编解码器
+type ICodec interface {
+	Name() string
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+var codecs = map[string]ICodec{}
+var lock sync.RWMutex
+
+//Register 注册content-type对应的编解码器，已存在时覆盖原编解码器
+func Register(contentType string, c ICodec) {
+	lock.Lock()
+	defer lock.Unlock()
+	codecs[mediaType(contentType)] = c
+}
+
+//Get 根据content-type获取编解码器，忽略charset等参数
+func Get(contentType string) (ICodec, bool) {
+	lock.RLock()
+	defer lock.RUnlock()
+	c, ok := codecs[mediaType(contentType)]
+	return c, ok
+}
+
+//IsBinary content-type是否对应二进制编解码器(非json的已注册编解码器)
+func IsBinary(contentType string) bool {
+	c, ok := Get(contentType)
+	return ok && c.Name() != JSON
+}
+
+//mediaType 获取content-type中的媒体类型
+func mediaType(contentType string) string {
+	if i := strings.Index(contentType, ";"); i >= 0 {
+		contentType = contentType[:i]
+	}
+	return strings.ToLower(strings.TrimSpace(contentType))
+}
@@ -0,0 +1,83 @@
+package codec
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/micro-plat/lib4go/assert"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestGet(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		want        string
+		ok          bool
+	}{
+		{name: "1. json", contentType: "application/json", want: JSON, ok: true},
+		{name: "2. 带charset的json", contentType: "application/json; charset=utf-8", want: JSON, ok: true},
+		{name: "3. protobuf", contentType: "application/x-protobuf", want: Protobuf, ok: true},
+		{name: "4. 大写protobuf", contentType: "Application/Protobuf", want: Protobuf, ok: true},
+		{name: "5. msgpack", contentType: "application/x-msgpack;charset=utf-8", want: Msgpack, ok: true},
+		{name: "6. 未注册", contentType: "text/plain", ok: false},
+	}
+	for _, tt := range tests {
+		c, ok := Get(tt.contentType)
+		assert.Equal(t, tt.ok, ok, tt.name)
+		if ok {
+			assert.Equal(t, tt.want, c.Name(), tt.name)
+		}
+	}
+	assert.Equal(t, false, IsBinary("application/json"), "json不是二进制")
+	assert.Equal(t, true, IsBinary("application/x-msgpack"), "msgpack是二进制")
+}
+
+func TestJSON(t *testing.T) {
+	c, _ := Get("application/json")
+	buff, err := c.Marshal(map[string]interface{}{"id": 100})
+	assert.Equal(t, nil, err)
+	data := map[string]interface{}{}
+	err = c.Unmarshal(buff, &data)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, json.Number("100"), data["id"])
+}
+
+func TestProtobuf(t *testing.T) {
+	c, _ := Get("application/x-protobuf")
+	buff, err := c.Marshal(&timestamppb.Timestamp{Seconds: 1600000000, Nanos: 100})
+	assert.Equal(t, nil, err)
+
+	r := &timestamppb.Timestamp{}
+	err = c.Unmarshal(buff, r)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1600000000), r.Seconds)
+	assert.Equal(t, int32(100), r.Nanos)
+
+	_, err = c.Marshal(map[string]interface{}{})
+	assert.Equal(t, true, errors.Is(err, ErrUnsupportedType))
+	err = c.Unmarshal(buff, &map[string]interface{}{})
+	assert.Equal(t, true, errors.Is(err, ErrUnsupportedType))
+}
+
+func TestMsgpack(t *testing.T) {
+	type image struct {
+		Name string `json:"name"`
+		Data []byte `json:"data"`
+	}
+	c, _ := Get("application/x-msgpack")
+	buff, err := c.Marshal(&image{Name: "a.png", Data: []byte{0x89, 0x50}})
+	assert.Equal(t, nil, err)
+
+	v := &image{}
+	err = c.Unmarshal(buff, v)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "a.png", v.Name)
+	assert.Equal(t, []byte{0x89, 0x50}, v.Data)
+
+	data := map[string]interface{}{}
+	err = c.Unmarshal(buff, &data)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "a.png", data["name"])
+}
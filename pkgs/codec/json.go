@@ -0,0 +1,32 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+//JSON json编解码器名称
+const JSON = "json"
+
+func init() {
+	Register("application/json", jsonCodec{})
+}
+
+type jsonCodec struct{}
+
+//Name 编解码器名称
+func (jsonCodec) Name() string {
+	return JSON
+}
+
+//Marshal 将对象编码为json
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+//Unmarshal 将json解码到对象，数字保留为json.Number
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	return d.Decode(v)
+}
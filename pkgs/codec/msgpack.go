@@ -0,0 +1,44 @@
+package codec
+
+import (
+	"reflect"
+
+	"github.com/ugorji/go/codec"
+)
+
+//Msgpack msgpack编解码器名称
+const Msgpack = "msgpack"
+
+func init() {
+	Register("application/x-msgpack", newMsgpackCodec())
+	Register("application/msgpack", newMsgpackCodec())
+}
+
+type msgpackCodec struct {
+	handle *codec.MsgpackHandle
+}
+
+func newMsgpackCodec() *msgpackCodec {
+	h := &codec.MsgpackHandle{}
+	h.MapType = reflect.TypeOf(map[string]interface{}(nil))
+	h.RawToString = true
+	h.WriteExt = true
+	h.TypeInfos = codec.NewTypeInfos([]string{"json"})
+	return &msgpackCodec{handle: h}
+}
+
+//Name 编解码器名称
+func (c *msgpackCodec) Name() string {
+	return Msgpack
+}
+
+//Marshal 将对象编码为msgpack
+func (c *msgpackCodec) Marshal(v interface{}) (buff []byte, err error) {
+	err = codec.NewEncoderBytes(&buff, c.handle).Encode(v)
+	return buff, err
+}
+
+//Unmarshal 将msgpack解码到对象
+func (c *msgpackCodec) Unmarshal(data []byte, v interface{}) error {
+	return codec.NewDecoderBytes(data, c.handle).Decode(v)
+}
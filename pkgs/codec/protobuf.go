@@ -0,0 +1,40 @@
+package codec
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+)
+
+//Protobuf protobuf编解码器名称
+const Protobuf = "protobuf"
+
+func init() {
+	Register("application/x-protobuf", protobufCodec{})
+	Register("application/protobuf", protobufCodec{})
+}
+
+type protobufCodec struct{}
+
+//Name 编解码器名称
+func (protobufCodec) Name() string {
+	return Protobuf
+}
+
+//Marshal 将proto.Message编码为二进制
+func (protobufCodec) Marshal(v interface{}) ([]byte, error) {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("%w:%T未实现proto.Message", ErrUnsupportedType, v)
+	}
+	return proto.Marshal(m)
+}
+
+//Unmarshal 将二进制解码到proto.Message
+func (protobufCodec) Unmarshal(data []byte, v interface{}) error {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("%w:%T未实现proto.Message", ErrUnsupportedType, v)
+	}
+	return proto.Unmarshal(data, m)
+}
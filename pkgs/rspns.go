@@ -3,12 +3,14 @@ package pkgs
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strings"
 
 	"github.com/asaskevich/govalidator"
+	"github.com/micro-plat/hydra/pkgs/codec"
 	"github.com/micro-plat/lib4go/encoding"
 	"github.com/micro-plat/lib4go/errs"
 	"github.com/micro-plat/lib4go/types"
@@ -68,6 +70,9 @@ func NewRspnsByHD(status int, header string, result interface{}) (r *Rspns) {
 	case string:
 		//转换数据
 		r.data, r.err = r.getMap(fmt.Sprint(r.header["Content-Type"]), types.StringToBytes(v))
+	case []byte:
+		//二进制内容
+		r.data, r.err = r.getMap(fmt.Sprint(r.header["Content-Type"]), v)
 	case map[string]interface{}:
 		r.data = v
 	default:
@@ -96,6 +101,17 @@ func (r *Rspns) GetResult() interface{} {
 	return r.result
 }
 
+//GetBytes 获取响应的原始内容，二进制响应(protobuf,msgpack等)可通过此方法获取
+func (r *Rspns) GetBytes() []byte {
+	switch v := r.result.(type) {
+	case []byte:
+		return v
+	case string:
+		return types.StringToBytes(v)
+	}
+	return nil
+}
+
 //GetError 获取远程请求的error或result转换为map
 func (r *Rspns) GetError() error {
 	return r.err
@@ -106,8 +122,13 @@ func (r *Rspns) Bind(obj interface{}) error {
 	if r.err != nil {
 		return r.err
 	}
-	//处理数据结构转换
-	if err := r.data.ToAnyStruct(obj); err != nil {
+	//处理数据结构转换，二进制编解码器(protobuf,msgpack等)直接解码原始内容
+	ctp := fmt.Sprint(r.header["Content-Type"])
+	if c, ok := codec.Get(ctp); ok && codec.IsBinary(ctp) {
+		if err := c.Unmarshal(r.GetBytes(), obj); err != nil {
+			return errs.NewError(http.StatusNotAcceptable, fmt.Errorf("对象%s解码有误 %v", c.Name(), err))
+		}
+	} else if err := r.data.ToAnyStruct(obj); err != nil {
 		return errs.NewError(http.StatusNotAcceptable, fmt.Errorf("对象转换有误 %v", err))
 	}
 
@@ -156,6 +177,15 @@ func (r *Rspns) getMap(ctp string, body []byte) (data map[string]interface{}, er
 			}
 			data[k] = types.BytesToString(buff)
 		}
+	case codec.IsBinary(ctp):
+		//无法解码为map的类型(如protobuf)只能通过Bind或GetBytes读取
+		c, _ := codec.Get(ctp)
+		if err = c.Unmarshal(body, &data); err != nil {
+			if !errors.Is(err, codec.ErrUnsupportedType) {
+				return nil, fmt.Errorf("%s转换为map失败:%w", c.Name(), err)
+			}
+			data["__body__"] = body
+		}
 	default:
 		data["__body__"] = types.BytesToString(body)
 	}
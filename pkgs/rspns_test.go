@@ -3,6 +3,7 @@ package pkgs
 import (
 	"testing"
 
+	"github.com/micro-plat/hydra/pkgs/codec"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -14,3 +15,26 @@ func TestRPCResponse(t *testing.T) {
 	assert.Equal(t, nil, resp.err)
 	assert.Equal(t, 3, len(resp.GetMap()))
 }
+
+func TestRPCResponse_Binary(t *testing.T) {
+	type image struct {
+		Name string `json:"name" valid:"required"`
+		Data []byte `json:"data"`
+	}
+	c, _ := codec.Get("application/x-msgpack")
+	buff, err := c.Marshal(&image{Name: "a.png", Data: []byte{0x89, 0x50, 0x00}})
+	assert.Equal(t, nil, err)
+
+	resp := NewRspnsByHD(200, `{"Content-Type":"application/x-msgpack"}`, buff)
+	assert.Equal(t, nil, resp.GetError())
+	assert.Equal(t, "a.png", resp.GetMap().GetString("name"))
+	assert.Equal(t, buff, resp.GetBytes())
+
+	v := &image{}
+	assert.Equal(t, nil, resp.Bind(v))
+	assert.Equal(t, []byte{0x89, 0x50, 0x00}, v.Data)
+
+	resp = NewRspnsByHD(200, `{"Content-Type":"application/x-protobuf"}`, []byte{0x08, 0x96, 0x01})
+	assert.Equal(t, nil, resp.GetError())
+	assert.Equal(t, []byte{0x08, 0x96, 0x01}, resp.GetMap()["__body__"])
+}
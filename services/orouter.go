@@ -118,6 +118,13 @@ func (p *pathRouter) Add(service string, action []string, opts ...interface{}) e
 	return nil
 }
 func (p *pathRouter) GetRouters() ([]*router.Router, error) {
+	//检查路由选项是否有误
+	for _, r := range p.routers {
+		if err := r.GetError(); err != nil {
+			return nil, fmt.Errorf("服务%s的路由配置有误:%w", r.Service, err)
+		}
+	}
+
 	//检查配置中是否有且只有一个路由未指定action
 	var first = -1
 	var hasRepeat bool
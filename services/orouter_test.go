@@ -181,3 +181,11 @@ func Test_pathRouter_GetRouters(t *testing.T) {
 		}
 	}
 }
+
+func TestORouter_GetRouters_OptionError(t *testing.T) {
+	s := NewORouter()
+	err := s.Add("/order", "/order", []string{"POST"}, router.WithJSONSchema(`{"type":"array"}`))
+	assert.Equal(t, nil, err, "注册时不panic")
+	_, err = s.GetRouters()
+	assert.NotEqual(t, nil, err, "获取路由时返回声明错误")
+}